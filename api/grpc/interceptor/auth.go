@@ -0,0 +1,89 @@
+package interceptor
+
+import (
+	"context"
+	"coupon-service/internal/infrastructure/auth"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+)
+
+const bearerPrefix = "Bearer "
+
+// TokenVerifier 요청 토큰을 검증한다
+type TokenVerifier interface {
+	Verify(token string) (*auth.Claims, error)
+}
+
+// AuthInterceptor Authorization 헤더의 Bearer 토큰을 검증하고 호출자를 컨텍스트에 담는다
+//   - userProcedures 에 포함된 RPC 는 유효한 토큰이면 호출할 수 있다
+//   - 그 외의 RPC 는 관리자 RPC 로 취급하며 adminScope 를 가진 토큰만 호출할 수 있다
+type AuthInterceptor struct {
+	verifier       TokenVerifier
+	adminScope     string
+	userProcedures map[string]bool
+}
+
+func NewAuthInterceptor(verifier TokenVerifier, adminScope string, userProcedures ...string) *AuthInterceptor {
+	procedures := make(map[string]bool, len(userProcedures))
+	for _, procedure := range userProcedures {
+		procedures[procedure] = true
+	}
+	return &AuthInterceptor{
+		verifier:       verifier,
+		adminScope:     adminScope,
+		userProcedures: procedures,
+	}
+}
+
+func (i *AuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		authCtx, err := i.authenticate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(authCtx, req)
+	}
+}
+
+func (i *AuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *AuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		authCtx, err := i.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(authCtx, conn)
+	}
+}
+
+func (i *AuthInterceptor) authenticate(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	value := header.Get("Authorization")
+	if !strings.HasPrefix(value, bearerPrefix) {
+		return nil, unauthenticated(errors.New("missing bearer token"))
+	}
+
+	claims, err := i.verifier.Verify(strings.TrimPrefix(value, bearerPrefix))
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+	admin := claims.HasScope(i.adminScope)
+	if !i.userProcedures[procedure] && !admin {
+		return nil, connect.NewError(connect.CodePermissionDenied, errors.New("admin scope is required"))
+	}
+	return auth.WithPrincipal(ctx, &auth.Principal{Claims: claims, Admin: admin}), nil
+}
+
+func unauthenticated(err error) *connect.Error {
+	connectErr := connect.NewError(connect.CodeUnauthenticated, err)
+	connectErr.Meta().Set("WWW-Authenticate", "Bearer")
+	return connectErr
+}
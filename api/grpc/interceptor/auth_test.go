@@ -0,0 +1,102 @@
+package interceptor
+
+import (
+	"context"
+	"coupon-service/internal/infrastructure/auth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	userProcedure  = "/test.Service/User"
+	adminProcedure = "/test.Service/Admin"
+)
+
+type fakeVerifier map[string]*auth.Claims
+
+func (v fakeVerifier) Verify(token string) (*auth.Claims, error) {
+	if claims, ok := v[token]; ok {
+		return claims, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+// newAuthTestServer 호출자의 subject 를 응답하는 RPC 두 개를 인증 인터셉터와 함께 실행한다
+func newAuthTestServer(t *testing.T) string {
+	verifier := fakeVerifier{
+		"user":  {Subject: "user-1"},
+		"admin": {Subject: "admin-1", Scopes: []string{"coupon:admin"}},
+	}
+	opts := connect.WithInterceptors(NewAuthInterceptor(verifier, "coupon:admin", userProcedure))
+	whoAmI := func(ctx context.Context, _ *connect.Request[emptypb.Empty]) (*connect.Response[wrapperspb.StringValue], error) {
+		principal, _ := auth.PrincipalFromContext(ctx)
+		return connect.NewResponse(wrapperspb.String(principal.Claims.Subject)), nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(userProcedure, connect.NewUnaryHandler(userProcedure, whoAmI, opts))
+	mux.Handle(adminProcedure, connect.NewUnaryHandler(adminProcedure, whoAmI, opts))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func call(baseURL string, procedure string, token string) (string, error) {
+	client := connect.NewClient[emptypb.Empty, wrapperspb.StringValue](http.DefaultClient, baseURL+procedure)
+	req := connect.NewRequest(&emptypb.Empty{})
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.CallUnary(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Msg.GetValue(), nil
+}
+
+func assertCode(t *testing.T, err error, code connect.Code) {
+	var connectErr *connect.Error
+	require.True(t, errors.As(err, &connectErr))
+	assert.Equal(t, code, connectErr.Code())
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	baseURL := newAuthTestServer(t)
+
+	t.Run("토큰이 없거나 유효하지 않으면 Unauthenticated 가 반환되어야 한다", func(t *testing.T) {
+		_, err := call(baseURL, userProcedure, "")
+		assertCode(t, err, connect.CodeUnauthenticated)
+
+		_, err = call(baseURL, userProcedure, "unknown")
+		assertCode(t, err, connect.CodeUnauthenticated)
+	})
+
+	t.Run("일반 사용자 토큰으로 사용자 RPC 를 호출할 수 있어야 한다", func(t *testing.T) {
+		subject, err := call(baseURL, userProcedure, "user")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", subject)
+	})
+
+	t.Run("관리자 scope 가 없으면 관리자 RPC 호출이 거부되어야 한다", func(t *testing.T) {
+		_, err := call(baseURL, adminProcedure, "user")
+
+		assertCode(t, err, connect.CodePermissionDenied)
+	})
+
+	t.Run("관리자 토큰으로 모든 RPC 를 호출할 수 있어야 한다", func(t *testing.T) {
+		_, err := call(baseURL, adminProcedure, "admin")
+		require.NoError(t, err)
+
+		_, err = call(baseURL, userProcedure, "admin")
+		require.NoError(t, err)
+	})
+}
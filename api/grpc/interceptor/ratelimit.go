@@ -0,0 +1,169 @@
+package interceptor
+
+import (
+	"context"
+	"coupon-service/internal/infrastructure/auth"
+	"coupon-service/internal/infrastructure/ratelimit"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimitInterceptor 규칙별로 요청 한도를 확인하고 초과하면 ResourceExhausted 로 응답한다
+// 한도를 넘은 요청에는 Retry-After 헤더(초)와 재시도까지의 시간(google.protobuf.Duration) 에러 상세를 담는다
+// 한도 확인에 실패하면 발급을 막지 않도록 요청을 허용한다
+type RateLimitInterceptor struct {
+	limiter ratelimit.Limiter
+	rules   []ratelimit.Rule
+	// trustForwardedFor 프록시 뒤에서 실행될 때 X-Forwarded-For 의 첫 번째 주소를 클라이언트 IP 로 사용한다
+	trustForwardedFor bool
+}
+
+func NewRateLimitInterceptor(
+	limiter ratelimit.Limiter,
+	rules []ratelimit.Rule,
+	trustForwardedFor bool,
+) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter:           limiter,
+		rules:             rules,
+		trustForwardedFor: trustForwardedFor,
+	}
+}
+
+// userIDGetter, campaignIDGetter 요청 메시지에서 사용자, 캠페인 ID 를 꺼내기 위한 인터페이스
+type userIDGetter interface {
+	GetUserId() string
+}
+
+type campaignIDGetter interface {
+	GetCampaignId() string
+}
+
+// rateLimitRequest 요청에서 규칙의 기준별 값을 꺼낼 때 사용하는 정보
+type rateLimitRequest struct {
+	procedure string
+	peerAddr  string
+	header    http.Header
+	message   any
+}
+
+func (i *RateLimitInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.check(ctx, rateLimitRequest{
+			procedure: req.Spec().Procedure,
+			peerAddr:  req.Peer().Addr,
+			header:    req.Header(),
+			message:   req.Any(),
+		}); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *RateLimitInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler 스트리밍 RPC 는 메시지를 받기 전에 확인하므로 요청 메시지의 값을 기준으로 하는 규칙은 적용되지 않는다
+func (i *RateLimitInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(ctx, rateLimitRequest{
+			procedure: conn.Spec().Procedure,
+			peerAddr:  conn.Peer().Addr,
+			header:    conn.RequestHeader(),
+		}); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *RateLimitInterceptor) check(ctx context.Context, req rateLimitRequest) error {
+	for _, rule := range i.rules {
+		if !rule.Matches(req.procedure) {
+			continue
+		}
+		value := i.dimensionValue(ctx, rule.Dimension, req)
+		if value == "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%s:%s", req.procedure, rule.Dimension, value)
+		result, err := i.limiter.Allow(ctx, key, rule.Limit)
+		if err != nil {
+			log.Printf("failed to check rate limit(%s): %v", key, err)
+			continue
+		}
+		if !result.Allowed {
+			return resourceExhausted(rule, result)
+		}
+	}
+	return nil
+}
+
+func (i *RateLimitInterceptor) dimensionValue(ctx context.Context, dimension ratelimit.Dimension, req rateLimitRequest) string {
+	switch dimension {
+	case ratelimit.ByRPC:
+		return req.procedure
+	case ratelimit.ByUser:
+		// 인증된 요청은 요청 본문 대신 토큰의 subject 를 기준으로 한다
+		if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.Admin {
+			return principal.Claims.Subject
+		}
+		if msg, ok := req.message.(userIDGetter); ok {
+			return msg.GetUserId()
+		}
+	case ratelimit.ByIP:
+		return ClientIP(req.peerAddr, req.header, i.trustForwardedFor)
+	case ratelimit.ByCampaign:
+		if msg, ok := req.message.(campaignIDGetter); ok {
+			return msg.GetCampaignId()
+		}
+	}
+	return ""
+}
+
+// ClientIP 요청한 클라이언트의 IP. trustForwardedFor 이면 X-Forwarded-For 의 첫 번째 주소를 사용한다
+// 로드 밸런서가 헤더를 덮어쓰지 않는 환경에서는 클라이언트가 값을 위조할 수 있으므로 사용하지 않아야 한다
+func ClientIP(peerAddr string, header http.Header, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(peerAddr)
+	if err != nil {
+		return peerAddr
+	}
+	return host
+}
+
+func resourceExhausted(rule ratelimit.Rule, result ratelimit.Result) *connect.Error {
+	connectErr := connect.NewError(
+		connect.CodeResourceExhausted,
+		errors.New(fmt.Sprintf("rate limit exceeded: %s per %s", rule.Limit, rule.Dimension)),
+	)
+	seconds := int(math.Ceil(result.RetryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	connectErr.Meta().Set("Retry-After", strconv.Itoa(seconds))
+	if detail, err := connect.NewErrorDetail(durationpb.New(result.RetryAfter)); err == nil {
+		connectErr.AddDetail(detail)
+	}
+	return connectErr
+}
@@ -0,0 +1,74 @@
+package interceptor
+
+import (
+	"context"
+	"coupon-service/internal/infrastructure/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// countingLimiter 키별 요청 수가 한도를 넘으면 거부한다
+type countingLimiter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (l *countingLimiter) Allow(_ context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.counts[key]++
+	if l.counts[key] > limit.Rate {
+		return ratelimit.Result{RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	return ratelimit.Result{Allowed: true}, nil
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	const procedure = "/test.Service/Echo"
+	limiter := &countingLimiter{counts: map[string]int{}}
+	rules := []ratelimit.Rule{
+		{Procedure: "Echo", Dimension: ratelimit.ByIP, Limit: ratelimit.Limit{Rate: 2, Period: time.Second}},
+	}
+	echo := func(_ context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[emptypb.Empty], error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, echo,
+		connect.WithInterceptors(NewRateLimitInterceptor(limiter, rules, true)),
+	))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := connect.NewClient[wrapperspb.StringValue, emptypb.Empty](http.DefaultClient, server.URL+procedure)
+	call := func(ip string) error {
+		req := connect.NewRequest(wrapperspb.String("hello"))
+		req.Header().Set("X-Forwarded-For", ip+", 10.0.0.1")
+		_, err := client.CallUnary(context.Background(), req)
+		return err
+	}
+
+	require.NoError(t, call("1.1.1.1"))
+	require.NoError(t, call("1.1.1.1"))
+	require.NoError(t, call("2.2.2.2"))
+
+	err := call("1.1.1.1")
+
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, connect.CodeResourceExhausted, connectErr.Code())
+	assert.Equal(t, "2", connectErr.Meta().Get("Retry-After"))
+	require.Len(t, connectErr.Details(), 1)
+	detail, valueErr := connectErr.Details()[0].Value()
+	require.NoError(t, valueErr)
+	assert.Equal(t, 1500*time.Millisecond, detail.(*durationpb.Duration).AsDuration())
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// AdminServiceName 운영용 RPC 를 제공하는 서비스
+// 인터페이스 모듈에 정의되지 않은 RPC 이므로 요청과 응답에는 protobuf well-known 타입을 사용한다
+const AdminServiceName = "io.coupon.service.AdminService"
+
+const (
+	AdminServiceBlockUserProcedure        = "/" + AdminServiceName + "/BlockUser"
+	AdminServiceUnblockUserProcedure      = "/" + AdminServiceName + "/UnblockUser"
+	AdminServiceListBlockedUsersProcedure = "/" + AdminServiceName + "/ListBlockedUsers"
+
+	AdminServiceAddCampaignUsersProcedure    = "/" + AdminServiceName + "/AddCampaignUsers"
+	AdminServiceRemoveCampaignUsersProcedure = "/" + AdminServiceName + "/RemoveCampaignUsers"
+	AdminServiceListCampaignUsersProcedure   = "/" + AdminServiceName + "/ListCampaignUsers"
+
+	AdminServiceImportCodesProcedure = "/" + AdminServiceName + "/ImportCodes"
+
+	AdminServiceRevokeCouponProcedure = "/" + AdminServiceName + "/RevokeCoupon"
+
+	AdminServicePauseCampaignProcedure  = "/" + AdminServiceName + "/PauseCampaign"
+	AdminServiceResumeCampaignProcedure = "/" + AdminServiceName + "/ResumeCampaign"
+
+	AdminServiceCreateWebhookSubscriptionProcedure = "/" + AdminServiceName + "/CreateWebhookSubscription"
+	AdminServiceListWebhookSubscriptionsProcedure  = "/" + AdminServiceName + "/ListWebhookSubscriptions"
+	AdminServiceDeleteWebhookSubscriptionProcedure = "/" + AdminServiceName + "/DeleteWebhookSubscription"
+	AdminServiceListWebhookDeliveriesProcedure     = "/" + AdminServiceName + "/ListWebhookDeliveries"
+	AdminServiceRetryWebhookDeliveryProcedure      = "/" + AdminServiceName + "/RetryWebhookDelivery"
+)
+
+const (
+	// CampaignIDHeader 클라이언트 스트리밍 RPC 에서 대상 캠페인 ID 를 전달하는 요청 헤더
+	CampaignIDHeader = "Coupon-Campaign-Id"
+	// importCodesBatchSize ImportCodes 에서 한 번에 등록하는 코드 수
+	importCodesBatchSize = 1000
+)
+
+type AdminServiceHandler struct {
+	couponService *application.CouponService
+}
+
+func NewAdminServiceHandler(couponService *application.CouponService) *AdminServiceHandler {
+	return &AdminServiceHandler{
+		couponService: couponService,
+	}
+}
+
+// NewAdminServiceHTTPHandler serviceconnect 의 생성 코드와 같이 서비스 경로와 핸들러를 반환한다
+func NewAdminServiceHTTPHandler(svc *AdminServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle(AdminServiceBlockUserProcedure, connect.NewUnaryHandler(AdminServiceBlockUserProcedure, svc.BlockUser, opts...))
+	mux.Handle(AdminServiceUnblockUserProcedure, connect.NewUnaryHandler(AdminServiceUnblockUserProcedure, svc.UnblockUser, opts...))
+	mux.Handle(AdminServiceListBlockedUsersProcedure, connect.NewUnaryHandler(AdminServiceListBlockedUsersProcedure, svc.ListBlockedUsers, opts...))
+	mux.Handle(AdminServiceAddCampaignUsersProcedure, connect.NewUnaryHandler(AdminServiceAddCampaignUsersProcedure, svc.AddCampaignUsers, opts...))
+	mux.Handle(AdminServiceRemoveCampaignUsersProcedure, connect.NewUnaryHandler(AdminServiceRemoveCampaignUsersProcedure, svc.RemoveCampaignUsers, opts...))
+	mux.Handle(AdminServiceListCampaignUsersProcedure, connect.NewUnaryHandler(AdminServiceListCampaignUsersProcedure, svc.ListCampaignUsers, opts...))
+	mux.Handle(AdminServiceImportCodesProcedure, connect.NewClientStreamHandler(AdminServiceImportCodesProcedure, svc.ImportCodes, opts...))
+	mux.Handle(AdminServiceRevokeCouponProcedure, connect.NewUnaryHandler(AdminServiceRevokeCouponProcedure, svc.RevokeCoupon, opts...))
+	mux.Handle(AdminServicePauseCampaignProcedure, connect.NewUnaryHandler(AdminServicePauseCampaignProcedure, svc.PauseCampaign, opts...))
+	mux.Handle(AdminServiceResumeCampaignProcedure, connect.NewUnaryHandler(AdminServiceResumeCampaignProcedure, svc.ResumeCampaign, opts...))
+	mux.Handle(AdminServiceCreateWebhookSubscriptionProcedure, connect.NewUnaryHandler(AdminServiceCreateWebhookSubscriptionProcedure, svc.CreateWebhookSubscription, opts...))
+	mux.Handle(AdminServiceListWebhookSubscriptionsProcedure, connect.NewUnaryHandler(AdminServiceListWebhookSubscriptionsProcedure, svc.ListWebhookSubscriptions, opts...))
+	mux.Handle(AdminServiceDeleteWebhookSubscriptionProcedure, connect.NewUnaryHandler(AdminServiceDeleteWebhookSubscriptionProcedure, svc.DeleteWebhookSubscription, opts...))
+	mux.Handle(AdminServiceListWebhookDeliveriesProcedure, connect.NewUnaryHandler(AdminServiceListWebhookDeliveriesProcedure, svc.ListWebhookDeliveries, opts...))
+	mux.Handle(AdminServiceRetryWebhookDeliveryProcedure, connect.NewUnaryHandler(AdminServiceRetryWebhookDeliveryProcedure, svc.RetryWebhookDelivery, opts...))
+	return "/" + AdminServiceName + "/", mux
+}
+
+// BlockUser 요청 값은 차단할 사용자 ID 이다
+func (s *AdminServiceHandler) BlockUser(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[emptypb.Empty], error) {
+	userID := strings.TrimSpace(req.Msg.GetValue())
+	if userID == "" {
+		apiErr := invalidArgument("INVALID_USER_ID", "user id must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	if err := s.couponService.BlockUser(ctx, userID); err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// UnblockUser 요청 값은 차단을 해제할 사용자 ID 이다
+func (s *AdminServiceHandler) UnblockUser(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[emptypb.Empty], error) {
+	if err := s.couponService.UnblockUser(ctx, strings.TrimSpace(req.Msg.GetValue())); err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// ListBlockedUsers 차단된 사용자 ID 목록을 반환한다
+func (s *AdminServiceHandler) ListBlockedUsers(
+	ctx context.Context,
+	_ *connect.Request[emptypb.Empty],
+) (*connect.Response[structpb.ListValue], error) {
+	users, err := s.couponService.ListBlockedUsers(ctx)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+
+	return connect.NewResponse(stringListValue(users)), nil
+}
+
+// AddCampaignUsers 요청 값은 {"campaign_id": string, "user_ids": [string]} 형식이며
+// 사용자 목록의 용도(허용, 차단)는 캠페인의 발급 대상 조건을 따른다
+func (s *AdminServiceHandler) AddCampaignUsers(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[emptypb.Empty], error) {
+	campaignID, userIDs, apiErr := campaignUsersRequest(req.Msg)
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	if err := s.couponService.AddCampaignUsers(ctx, campaignID, userIDs...); err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// RemoveCampaignUsers 요청 값은 AddCampaignUsers 와 같다
+func (s *AdminServiceHandler) RemoveCampaignUsers(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[emptypb.Empty], error) {
+	campaignID, userIDs, apiErr := campaignUsersRequest(req.Msg)
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	if err := s.couponService.RemoveCampaignUsers(ctx, campaignID, userIDs...); err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// ListCampaignUsers 요청 값은 캠페인 ID 이다
+func (s *AdminServiceHandler) ListCampaignUsers(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[structpb.ListValue], error) {
+	users, err := s.couponService.ListCampaignUsers(ctx, strings.TrimSpace(req.Msg.GetValue()))
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(stringListValue(users)), nil
+}
+
+// ImportCodes 코드 풀 캠페인에 코드를 등록한다. 캠페인 ID 는 Coupon-Campaign-Id 헤더로, 코드는 메시지마다 하나씩 전달한다
+// 받은 코드는 일정 개수마다 나누어 등록하므로 중간에 실패하면 이전 묶음까지는 등록된 상태로 남는다
+// 응답 값은 {"received": 받은 코드 수, "imported": 새로 등록된 코드 수} 형식이다
+func (s *AdminServiceHandler) ImportCodes(
+	ctx context.Context,
+	stream *connect.ClientStream[wrapperspb.StringValue],
+) (*connect.Response[structpb.Struct], error) {
+	campaignID := strings.TrimSpace(stream.RequestHeader().Get(CampaignIDHeader))
+	if campaignID == "" {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_ID", CampaignIDHeader+" header must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+
+	var received, imported int
+	batch := make([]string, 0, importCodesBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		count, err := s.couponService.ImportCodes(ctx, campaignID, batch)
+		if err != nil {
+			apiErr := toAPIError(err)
+			return apiErr.connectError(apiErr.detail())
+		}
+		imported += count
+		batch = batch[:0]
+		return nil
+	}
+	for stream.Receive() {
+		batch = append(batch, stream.Msg().GetValue())
+		received++
+		if len(batch) == importCodesBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&structpb.Struct{Fields: map[string]*structpb.Value{
+		"received": structpb.NewNumberValue(float64(received)),
+		"imported": structpb.NewNumberValue(float64(imported)),
+	}}), nil
+}
+
+// RevokeCoupon 요청 값은 취소할 발급 쿠폰의 ID 이며, 응답 값은 Redeem 응답과 같은 형식이다
+func (s *AdminServiceHandler) RevokeCoupon(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[structpb.Struct], error) {
+	issuedCouponID := strings.TrimSpace(req.Msg.GetValue())
+	if issuedCouponID == "" {
+		apiErr := invalidArgument("INVALID_ISSUED_COUPON_ID", "issued coupon id must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	issuedCoupon, err := s.couponService.RevokeCoupon(ctx, issuedCouponID)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(issuedCouponValue(issuedCoupon)), nil
+}
+
+// PauseCampaign 요청 값은 발급을 일시 중지할 캠페인 ID 이며, 응답 값은 {"campaign_id", "paused", "paused_at"} 형식이다
+// 이미 중지된 캠페인이면 상태를 바꾸지 않고 현재 상태로 응답한다
+func (s *AdminServiceHandler) PauseCampaign(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[structpb.Struct], error) {
+	return s.setCampaignPaused(ctx, req.Msg.GetValue(), s.couponService.PauseCampaign)
+}
+
+// ResumeCampaign 요청 값은 발급을 다시 시작할 캠페인 ID 이며, 응답 값은 PauseCampaign 응답과 같은 형식이다
+func (s *AdminServiceHandler) ResumeCampaign(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[structpb.Struct], error) {
+	return s.setCampaignPaused(ctx, req.Msg.GetValue(), s.couponService.ResumeCampaign)
+}
+
+func (s *AdminServiceHandler) setCampaignPaused(
+	ctx context.Context,
+	campaignID string,
+	set func(ctx context.Context, couponId string) (*domain.Coupon, error),
+) (*connect.Response[structpb.Struct], error) {
+	campaignID = strings.TrimSpace(campaignID)
+	if campaignID == "" {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_ID", "campaign id must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	coupon, err := set(ctx, campaignID)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	fields := map[string]*structpb.Value{
+		"campaign_id": structpb.NewStringValue(coupon.ID),
+		"paused":      structpb.NewBoolValue(coupon.Paused()),
+	}
+	if coupon.PausedAt != nil {
+		fields["paused_at"] = structpb.NewStringValue(coupon.PausedAt.UTC().Format(time.RFC3339))
+	}
+	return connect.NewResponse(&structpb.Struct{Fields: fields}), nil
+}
+
+func campaignUsersRequest(msg *structpb.Struct) (string, []string, *apiError) {
+	fields := msg.GetFields()
+	campaignID := strings.TrimSpace(fields["campaign_id"].GetStringValue())
+	if campaignID == "" {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_ID", "campaign_id must not be empty")
+		return "", nil, &apiErr
+	}
+
+	var userIDs []string
+	for _, value := range fields["user_ids"].GetListValue().GetValues() {
+		userID := strings.TrimSpace(value.GetStringValue())
+		if userID == "" {
+			apiErr := invalidArgument("INVALID_USER_ID", "user_ids must be non-empty strings")
+			return "", nil, &apiErr
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if len(userIDs) == 0 {
+		apiErr := invalidArgument("INVALID_USER_ID", "user_ids must not be empty")
+		return "", nil, &apiErr
+	}
+	return campaignID, userIDs, nil
+}
+
+func stringListValue(values []string) *structpb.ListValue {
+	list := make([]*structpb.Value, len(values))
+	for i, value := range values {
+		list[i] = structpb.NewStringValue(value)
+	}
+	return &structpb.ListValue{Values: list}
+}
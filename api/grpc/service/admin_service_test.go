@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestImportCodesRequiresCampaignID(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(NewAdminServiceHTTPHandler(NewAdminServiceHandler(nil)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := connect.NewClient[wrapperspb.StringValue, structpb.Struct](
+		server.Client(), server.URL+AdminServiceImportCodesProcedure,
+	)
+	stream := client.CallClientStream(context.Background())
+	require.NoError(t, stream.Send(wrapperspb.String("PIN-1")))
+	_, err := stream.CloseAndReceive()
+
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, "INVALID_CAMPAIGN_ID", connectErr.Meta().Get(ErrorCodeHeader))
+}
+
+func TestListWebhookDeliveriesValidatesFilter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(NewAdminServiceHTTPHandler(NewAdminServiceHandler(nil)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := connect.NewClient[structpb.Struct, structpb.ListValue](
+		server.Client(), server.URL+AdminServiceListWebhookDeliveriesProcedure,
+	)
+
+	tests := []struct {
+		name   string
+		fields map[string]any
+		code   string
+	}{
+		{"알 수 없는 상태", map[string]any{"status": "failed"}, "INVALID_WEBHOOK_DELIVERY_STATUS"},
+		{"범위를 벗어난 개수", map[string]any{"limit": 1001}, "INVALID_LIMIT"},
+		{"정수가 아닌 개수", map[string]any{"limit": 1.5}, "INVALID_LIMIT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := structpb.NewStruct(tt.fields)
+			require.NoError(t, err)
+
+			_, err = client.CallUnary(context.Background(), connect.NewRequest(msg))
+
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			assert.Equal(t, tt.code, connectErr.Meta().Get(ErrorCodeHeader))
+		})
+	}
+}
+
+func TestSetCampaignPausedRequiresCampaignID(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(NewAdminServiceHTTPHandler(NewAdminServiceHandler(nil)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for name, procedure := range map[string]string{
+		"일시 중지": AdminServicePauseCampaignProcedure,
+		"발급 재개": AdminServiceResumeCampaignProcedure,
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := connect.NewClient[wrapperspb.StringValue, structpb.Struct](server.Client(), server.URL+procedure)
+
+			_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String(" ")))
+
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			assert.Equal(t, "INVALID_CAMPAIGN_ID", connectErr.Meta().Get(ErrorCodeHeader))
+		})
+	}
+}
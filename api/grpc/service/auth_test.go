@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"coupon-service/api/grpc/interceptor"
+	"coupon-service/internal/infrastructure/auth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeVerifier map[string]*auth.Claims
+
+func (v fakeVerifier) Verify(token string) (*auth.Claims, error) {
+	if claims, ok := v[token]; ok {
+		return claims, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+// TestUserProcedures 서버에 등록하는 사용자 RPC 목록으로 인증 인터셉터를 만들어 사용자 토큰으로 호출할 수 있는 RPC 를 확인한다
+func TestUserProcedures(t *testing.T) {
+	verifier := fakeVerifier{"user": {Subject: "user-1"}}
+	opts := connect.WithInterceptors(interceptor.NewAuthInterceptor(verifier, "coupon:admin", UserProcedures()...))
+	empty := func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	mux := http.NewServeMux()
+	procedures := []string{
+		serviceconnect.GreetServiceGetCampaignProcedure,
+		serviceconnect.GreetServiceIssueCouponProcedure,
+		serviceconnect.GreetServiceCreateCampaignProcedure,
+	}
+	for _, procedure := range procedures {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure, empty, opts))
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	call := func(procedure string) error {
+		client := connect.NewClient[emptypb.Empty, emptypb.Empty](http.DefaultClient, server.URL+procedure)
+		req := connect.NewRequest(&emptypb.Empty{})
+		req.Header().Set("Authorization", "Bearer user")
+		_, err := client.CallUnary(context.Background(), req)
+		return err
+	}
+
+	t.Run("사용자 토큰으로 GetCampaign 을 호출할 수 있어야 한다", func(t *testing.T) {
+		assert.NoError(t, call(serviceconnect.GreetServiceGetCampaignProcedure))
+		assert.NoError(t, call(serviceconnect.GreetServiceIssueCouponProcedure))
+	})
+
+	t.Run("사용자 토큰으로 CreateCampaign 을 호출하면 거부되어야 한다", func(t *testing.T) {
+		err := call(serviceconnect.GreetServiceCreateCampaignProcedure)
+
+		var connectErr *connect.Error
+		require.True(t, errors.As(err, &connectErr))
+		assert.Equal(t, connect.CodePermissionDenied, connectErr.Code())
+	})
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"coupon-service/internal/application"
+	"coupon-service/internal/infrastructure/auth"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// CampaignServiceName 캠페인 페이지에서 사용하는 조회 RPC 를 제공하는 서비스
+// 인터페이스 모듈에 정의되지 않은 RPC 이므로 요청과 응답에는 protobuf well-known 타입을 사용한다
+const CampaignServiceName = "io.coupon.service.CampaignService"
+
+const (
+	CampaignServiceWatchCampaignProcedure           = "/" + CampaignServiceName + "/WatchCampaign"
+	CampaignServiceGetCampaignAvailabilityProcedure = "/" + CampaignServiceName + "/GetCampaignAvailability"
+	CampaignServiceGetUserClaimStatusProcedure      = "/" + CampaignServiceName + "/GetUserClaimStatus"
+	CampaignServiceGetUserClaimStatusesProcedure    = "/" + CampaignServiceName + "/GetUserClaimStatuses"
+)
+
+const (
+	maxAvailabilityCampaigns = 100
+	maxClaimStatusCampaigns  = 100
+	// availabilityCacheControl CDN 이 HTTP GET 으로 호출한 응답을 1초 동안 캐싱할 수 있도록 한다
+	availabilityCacheControl = "public, max-age=1"
+)
+
+type CampaignServiceHandler struct {
+	couponService *application.CouponService
+}
+
+func NewCampaignServiceHandler(couponService *application.CouponService) *CampaignServiceHandler {
+	return &CampaignServiceHandler{
+		couponService: couponService,
+	}
+}
+
+// NewCampaignServiceHTTPHandler serviceconnect 의 생성 코드와 같이 서비스 경로와 핸들러를 반환한다
+func NewCampaignServiceHTTPHandler(svc *CampaignServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle(CampaignServiceWatchCampaignProcedure, connect.NewServerStreamHandler(CampaignServiceWatchCampaignProcedure, svc.WatchCampaign, opts...))
+	mux.Handle(CampaignServiceGetCampaignAvailabilityProcedure, connect.NewUnaryHandler(
+		CampaignServiceGetCampaignAvailabilityProcedure,
+		svc.GetCampaignAvailability,
+		append(opts, connect.WithIdempotency(connect.IdempotencyNoSideEffects))...,
+	))
+	mux.Handle(CampaignServiceGetUserClaimStatusProcedure, connect.NewUnaryHandler(
+		CampaignServiceGetUserClaimStatusProcedure,
+		svc.GetUserClaimStatus,
+		append(opts, connect.WithIdempotency(connect.IdempotencyNoSideEffects))...,
+	))
+	mux.Handle(CampaignServiceGetUserClaimStatusesProcedure, connect.NewUnaryHandler(
+		CampaignServiceGetUserClaimStatusesProcedure,
+		svc.GetUserClaimStatuses,
+		append(opts, connect.WithIdempotency(connect.IdempotencyNoSideEffects))...,
+	))
+	return "/" + CampaignServiceName + "/", mux
+}
+
+// WatchCampaign 요청 값은 캠페인 ID 이며, 현재 상태를 먼저 보낸 뒤 재고나 발급 상태가 바뀔 때마다
+// {"campaign_id", "remaining", "status", "server_time"} 형식의 메시지를 보낸다
+// status 는 scheduled, open, paused, sold_out, ended 중 하나이고, 변경이 없어도 일정 간격마다 서버 시각을 보낸다
+func (s *CampaignServiceHandler) WatchCampaign(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+	stream *connect.ServerStream[structpb.Struct],
+) error {
+	campaignID := strings.TrimSpace(req.Msg.GetValue())
+	if campaignID == "" {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_ID", "campaign id must not be empty")
+		return apiErr.connectError(apiErr.detail())
+	}
+
+	err := s.couponService.WatchCampaign(ctx, campaignID, func(snapshot application.CampaignSnapshot) error {
+		return stream.Send(campaignSnapshotValue(snapshot))
+	})
+	if err != nil && ctx.Err() == nil {
+		apiErr := toAPIError(err)
+		return apiErr.connectError(apiErr.detail())
+	}
+	return nil
+}
+
+// GetCampaignAvailability 요청 값은 캠페인 ID 목록이며 최대 100개까지 조회할 수 있다
+// 응답 값은 요청 순서대로 {"campaign_id", "found", "remaining", "status"} 형식이며, 중복된 ID 는 한 번만 포함한다
+// 부수 효과가 없는 RPC 이므로 Connect 의 HTTP GET 으로도 호출할 수 있고 응답은 1초 동안 캐싱할 수 있다
+func (s *CampaignServiceHandler) GetCampaignAvailability(
+	ctx context.Context,
+	req *connect.Request[structpb.ListValue],
+) (*connect.Response[structpb.ListValue], error) {
+	campaignIDs, apiErr := campaignIDList(req.Msg, maxAvailabilityCampaigns)
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+
+	availabilities, err := s.couponService.GetCampaignAvailability(ctx, campaignIDs)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	values := make([]*structpb.Value, len(availabilities))
+	for i, availability := range availabilities {
+		values[i] = structpb.NewStructValue(campaignAvailabilityValue(availability))
+	}
+	res := connect.NewResponse(&structpb.ListValue{Values: values})
+	res.Header().Set("Cache-Control", availabilityCacheControl)
+	return res, nil
+}
+
+func campaignIDList(msg *structpb.ListValue, limit int) ([]string, *apiError) {
+	values := msg.GetValues()
+	if len(values) == 0 || len(values) > limit {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_IDS", fmt.Sprintf("campaign ids must contain between 1 and %d ids", limit))
+		return nil, &apiErr
+	}
+	campaignIDs := make([]string, len(values))
+	for i, value := range values {
+		campaignIDs[i] = strings.TrimSpace(value.GetStringValue())
+		if campaignIDs[i] == "" {
+			apiErr := invalidArgument("INVALID_CAMPAIGN_IDS", "campaign ids must be non-empty strings")
+			return nil, &apiErr
+		}
+	}
+	return campaignIDs, nil
+}
+
+func campaignAvailabilityValue(availability application.CampaignAvailability) *structpb.Struct {
+	fields := map[string]*structpb.Value{
+		"campaign_id": structpb.NewStringValue(availability.CampaignID),
+		"found":       structpb.NewBoolValue(availability.Found),
+	}
+	if availability.Found {
+		fields["remaining"] = structpb.NewNumberValue(float64(availability.Remaining))
+		fields["status"] = structpb.NewStringValue(string(availability.Status))
+	}
+	return &structpb.Struct{Fields: fields}
+}
+
+// GetUserClaimStatus 요청 값은 {"campaign_id": string, "user_id": string} 형식이며
+// 응답 값은 {"campaign_id", "claimed", "coupon"} 형식이다. coupon 은 발급받은 쿠폰의 코드와 상태로, 발급받지 않았으면 생략한다
+// 일반 사용자는 user_id 대신 토큰의 subject 로만 조회할 수 있다
+func (s *CampaignServiceHandler) GetUserClaimStatus(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	fields := req.Msg.GetFields()
+	campaignID := strings.TrimSpace(fields["campaign_id"].GetStringValue())
+	if campaignID == "" {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_ID", "campaign_id must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	userID, apiErr := claimStatusUserID(ctx, fields["user_id"].GetStringValue())
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+
+	status, err := s.couponService.GetUserClaimStatus(ctx, campaignID, userID)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(claimStatusValue(status)), nil
+}
+
+// GetUserClaimStatuses 요청 값은 {"campaign_ids": [string], "user_id": string} 형식이며 최대 100개의 캠페인을 조회할 수 있다
+// 응답 값은 요청 순서대로 GetUserClaimStatus 의 응답 목록이며, 중복된 ID 는 한 번만 포함한다
+func (s *CampaignServiceHandler) GetUserClaimStatuses(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.ListValue], error) {
+	fields := req.Msg.GetFields()
+	campaignIDs, apiErr := campaignIDList(fields["campaign_ids"].GetListValue(), maxClaimStatusCampaigns)
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	userID, apiErr := claimStatusUserID(ctx, fields["user_id"].GetStringValue())
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+
+	statuses, err := s.couponService.GetUserClaimStatuses(ctx, campaignIDs, userID)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	values := make([]*structpb.Value, len(statuses))
+	for i, status := range statuses {
+		values[i] = structpb.NewStructValue(claimStatusValue(status))
+	}
+	return connect.NewResponse(&structpb.ListValue{Values: values}), nil
+}
+
+// claimStatusUserID 일반 사용자는 다른 사용자의 발급 여부를 조회할 수 없으므로 토큰의 subject 를 사용한다
+func claimStatusUserID(ctx context.Context, userID string) (string, *apiError) {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.Admin {
+		userID = principal.Claims.Subject
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		apiErr := invalidArgument("INVALID_USER_ID", "user_id must not be empty")
+		return "", &apiErr
+	}
+	return userID, nil
+}
+
+func claimStatusValue(status application.ClaimStatus) *structpb.Struct {
+	fields := map[string]*structpb.Value{
+		"campaign_id": structpb.NewStringValue(status.CampaignID),
+		"claimed":     structpb.NewBoolValue(status.Claimed),
+	}
+	if status.Coupon != nil {
+		fields["coupon"] = structpb.NewStructValue(issuedCouponValue(status.Coupon))
+	}
+	return &structpb.Struct{Fields: fields}
+}
+
+func campaignSnapshotValue(snapshot application.CampaignSnapshot) *structpb.Struct {
+	return &structpb.Struct{Fields: map[string]*structpb.Value{
+		"campaign_id": structpb.NewStringValue(snapshot.CampaignID),
+		"remaining":   structpb.NewNumberValue(float64(snapshot.Remaining)),
+		"status":      structpb.NewStringValue(string(snapshot.Status)),
+		"server_time": structpb.NewStringValue(snapshot.ServerTime.UTC().Format(time.RFC3339Nano)),
+	}}
+}
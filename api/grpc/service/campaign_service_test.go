@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWatchCampaignRequiresCampaignID(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(NewCampaignServiceHTTPHandler(NewCampaignServiceHandler(nil)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := connect.NewClient[wrapperspb.StringValue, structpb.Struct](
+		server.Client(), server.URL+CampaignServiceWatchCampaignProcedure,
+	)
+	stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.String(" ")))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	assert.False(t, stream.Receive())
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(stream.Err()))
+	var connectErr *connect.Error
+	require.ErrorAs(t, stream.Err(), &connectErr)
+	assert.Equal(t, "INVALID_CAMPAIGN_ID", connectErr.Meta().Get(ErrorCodeHeader))
+}
+
+func TestGetCampaignAvailabilityValidatesCampaignIDs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(NewCampaignServiceHTTPHandler(NewCampaignServiceHandler(nil)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// CDN 에서 캐싱할 수 있도록 HTTP GET 으로 호출할 수 있어야 한다
+	client := connect.NewClient[structpb.ListValue, structpb.ListValue](
+		server.Client(), server.URL+CampaignServiceGetCampaignAvailabilityProcedure,
+		connect.WithHTTPGet(),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+	)
+
+	tooMany := make([]any, maxAvailabilityCampaigns+1)
+	for i := range tooMany {
+		tooMany[i] = "campaign"
+	}
+	for name, ids := range map[string][]any{
+		"빈 목록":      {},
+		"빈 캠페인 ID":  {"campaign", " "},
+		"문자열이 아닌 값": {1},
+		"최대 개수 초과":  tooMany,
+	} {
+		t.Run(name+"은 INVALID_CAMPAIGN_IDS 로 거부되어야 한다", func(t *testing.T) {
+			list, err := structpb.NewList(ids)
+			require.NoError(t, err)
+
+			_, err = client.CallUnary(context.Background(), connect.NewRequest(list))
+
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			assert.Equal(t, "INVALID_CAMPAIGN_IDS", connectErr.Meta().Get(ErrorCodeHeader))
+		})
+	}
+}
+
+func TestGetUserClaimStatusesValidatesRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(NewCampaignServiceHTTPHandler(NewCampaignServiceHandler(nil)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	single := connect.NewClient[structpb.Struct, structpb.Struct](
+		server.Client(), server.URL+CampaignServiceGetUserClaimStatusProcedure,
+	)
+	batch := connect.NewClient[structpb.Struct, structpb.ListValue](
+		server.Client(), server.URL+CampaignServiceGetUserClaimStatusesProcedure,
+	)
+	tests := []struct {
+		name string
+		call func(*structpb.Struct) error
+		req  map[string]any
+		code string
+	}{
+		{
+			name: "캠페인 ID 가 없으면 INVALID_CAMPAIGN_ID",
+			call: func(msg *structpb.Struct) error {
+				_, err := single.CallUnary(context.Background(), connect.NewRequest(msg))
+				return err
+			},
+			req:  map[string]any{"user_id": "user"},
+			code: "INVALID_CAMPAIGN_ID",
+		},
+		{
+			name: "사용자 ID 가 없으면 INVALID_USER_ID",
+			call: func(msg *structpb.Struct) error {
+				_, err := single.CallUnary(context.Background(), connect.NewRequest(msg))
+				return err
+			},
+			req:  map[string]any{"campaign_id": "campaign", "user_id": " "},
+			code: "INVALID_USER_ID",
+		},
+		{
+			name: "캠페인 ID 목록이 비어 있으면 INVALID_CAMPAIGN_IDS",
+			call: func(msg *structpb.Struct) error {
+				_, err := batch.CallUnary(context.Background(), connect.NewRequest(msg))
+				return err
+			},
+			req:  map[string]any{"campaign_ids": []any{}, "user_id": "user"},
+			code: "INVALID_CAMPAIGN_IDS",
+		},
+		{
+			name: "목록 조회도 사용자 ID 가 없으면 INVALID_USER_ID",
+			call: func(msg *structpb.Struct) error {
+				_, err := batch.CallUnary(context.Background(), connect.NewRequest(msg))
+				return err
+			},
+			req:  map[string]any{"campaign_ids": []any{"campaign"}},
+			code: "INVALID_USER_ID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" 로 거부되어야 한다", func(t *testing.T) {
+			msg, err := structpb.NewStruct(tt.req)
+			require.NoError(t, err)
+
+			err = tt.call(msg)
+
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			assert.Equal(t, tt.code, connectErr.Meta().Get(ErrorCodeHeader))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Sujin1135/coupon-service-interface/protobuf/entity"
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ErrorCodeHeader 실패한 응답에 애플리케이션 에러 코드를 담는 응답 헤더
+	ErrorCodeHeader = "Coupon-Error-Code"
+	// ErrorRetryableHeader 같은 요청을 다시 시도해도 되는지 여부를 담는 응답 헤더
+	ErrorRetryableHeader = "Coupon-Error-Retryable"
+	// ErrorModeHeader 값이 connect 이면 실패한 요청을 응답 메시지 대신 connect 에러 코드로 반환한다
+	ErrorModeHeader = "Coupon-Error-Mode"
+)
+
+const errorModeConnect = "connect"
+
+const unknownErrorMessage = "internal error occurred"
+
+// errorCategory 응답 메시지의 에러 oneof 중 어떤 필드로 응답할지를 나타낸다
+type errorCategory int
+
+const (
+	categoryInternal errorCategory = iota
+	categoryNotFound
+	categoryBadRequest
+)
+
+type errorMapping struct {
+	code     connect.Code
+	category errorCategory
+}
+
+// errorMappings 애플리케이션 에러 분류별 connect 코드와 응답 에러 필드
+var errorMappings = map[application.ErrorKind]errorMapping{
+	application.KindInternal:           {code: connect.CodeInternal, category: categoryInternal},
+	application.KindNotFound:           {code: connect.CodeNotFound, category: categoryNotFound},
+	application.KindInvalidArgument:    {code: connect.CodeInvalidArgument, category: categoryBadRequest},
+	application.KindFailedPrecondition: {code: connect.CodeFailedPrecondition, category: categoryBadRequest},
+	application.KindAlreadyExists:      {code: connect.CodeAlreadyExists, category: categoryBadRequest},
+	application.KindResourceExhausted:  {code: connect.CodeResourceExhausted, category: categoryBadRequest},
+	application.KindUnavailable:        {code: connect.CodeUnavailable, category: categoryInternal},
+	application.KindPermissionDenied:   {code: connect.CodePermissionDenied, category: categoryBadRequest},
+}
+
+// apiError 애플리케이션 에러를 응답으로 변환하기 위한 값
+// 원인 에러는 로그로만 남기고 응답에는 사용자에게 노출해도 되는 메시지만 담는다
+type apiError struct {
+	errorMapping
+	errorCode  string
+	message    string
+	retryable  bool
+	violations []domain.FieldViolation
+}
+
+// invalidArgument 애플리케이션 레이어를 거치기 전에 요청 검증에 실패했을 때 사용한다
+func invalidArgument(errorCode string, message string) apiError {
+	return apiError{
+		errorMapping: errorMappings[application.KindInvalidArgument],
+		errorCode:    errorCode,
+		message:      message,
+	}
+}
+
+// toAPIError 서버 측 장애(KindInternal, KindUnavailable)와 알 수 없는 에러만 로그로 남긴다
+// 매진, 중복 발급처럼 요청에 따라 정상적으로 발생하는 에러는 트래픽이 몰릴 때 로그가 넘치지 않도록 남기지 않는다
+func toAPIError(err error) apiError {
+	var appErr *application.Error
+	if !errors.As(err, &appErr) {
+		log.Println(err.Error())
+		return apiError{
+			errorMapping: errorMappings[application.KindInternal],
+			message:      unknownErrorMessage,
+		}
+	}
+
+	mapping, ok := errorMappings[appErr.Kind]
+	if !ok {
+		mapping = errorMappings[application.KindInternal]
+	}
+	if !ok || appErr.Kind == application.KindInternal || appErr.Kind == application.KindUnavailable {
+		log.Println(err.Error())
+	}
+	apiErr := apiError{
+		errorMapping: mapping,
+		errorCode:    appErr.Code,
+		message:      appErr.Message,
+		retryable:    appErr.Retryable,
+	}
+	// 검증에 실패한 필드 목록은 사용자가 요청을 고칠 수 있도록 응답에 포함한다
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		apiErr.message = appErr.Message + ": " + validationErr.Error()
+		apiErr.violations = validationErr.Violations
+	}
+	return apiErr
+}
+
+func (e apiError) setHeaders(header http.Header) {
+	if e.errorCode != "" {
+		header.Set(ErrorCodeHeader, e.errorCode)
+	}
+	header.Set(ErrorRetryableHeader, strconv.FormatBool(e.retryable))
+}
+
+func (e apiError) notFound() *entity.NotFoundError {
+	return &entity.NotFoundError{Message: &e.message}
+}
+
+func (e apiError) badRequest() *entity.BadRequestError {
+	return &entity.BadRequestError{Message: &e.message}
+}
+
+func (e apiError) internal() *entity.InternalError {
+	return &entity.InternalError{Message: &e.message}
+}
+
+// detail 응답 에러 필드와 같은 메시지를 connect 에러 상세로 반환한다
+func (e apiError) detail() proto.Message {
+	switch e.category {
+	case categoryNotFound:
+		return e.notFound()
+	case categoryBadRequest:
+		return e.badRequest()
+	default:
+		return e.internal()
+	}
+}
+
+// connectError 표의 connect 코드로 에러를 만들고 payload 를 에러 상세로 담는다
+// payload 로 응답 메시지의 에러 필드를 전달하면 클라이언트는 기존과 같은 타입으로 에러를 해석할 수 있다
+func (e apiError) connectError(payload proto.Message) *connect.Error {
+	connectErr := connect.NewError(e.code, errors.New(e.message))
+	details := []proto.Message{payload}
+	if len(e.violations) > 0 {
+		details = append(details, e.violationsDetail())
+	}
+	for _, message := range details {
+		detail, err := connect.NewErrorDetail(message)
+		if err != nil {
+			log.Println(err.Error())
+			continue
+		}
+		connectErr.AddDetail(detail)
+	}
+	e.setHeaders(connectErr.Meta())
+	return connectErr
+}
+
+// violationsDetail 필드별 검증 실패 목록을 google.rpc.BadRequest 로 반환한다
+func (e apiError) violationsDetail() *errdetails.BadRequest {
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(e.violations))
+	for i, violation := range e.violations {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		}
+	}
+	return &errdetails.BadRequest{FieldViolations: violations}
+}
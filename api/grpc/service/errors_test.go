@@ -0,0 +1,51 @@
+package service
+
+import (
+	"bytes"
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAPIErrorHidesIneligibleReasons(t *testing.T) {
+	err := application.IneligibleUserError.Wrap(&domain.IneligibleError{Reasons: []string{"user is in the denylist"}})
+
+	apiErr := toAPIError(err)
+
+	assert.Equal(t, "NOT_ELIGIBLE", apiErr.errorCode)
+	assert.Equal(t, "user is not eligible for this campaign", apiErr.message)
+}
+
+func TestToAPIErrorLogging(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		name   string
+		err    error
+		logged bool
+	}{
+		{name: "매진은 로그로 남기지 않아야 한다", err: application.AllCouponIssuedError, logged: false},
+		{name: "중복 발급은 로그로 남기지 않아야 한다", err: application.DuplicatedCouponUserError, logged: false},
+		{name: "캠페인을 찾을 수 없으면 로그로 남기지 않아야 한다", err: application.DataKeyNotFoundError.Wrap(cause), logged: false},
+		{name: "내부 에러는 로그로 남겨야 한다", err: application.IssuedCouponCreationError.Wrap(cause), logged: true},
+		{name: "일시적인 장애는 로그로 남겨야 한다", err: application.CacheUnavailableError.Wrap(cause), logged: true},
+		{name: "알 수 없는 에러는 로그로 남겨야 한다", err: cause, logged: true},
+	}
+
+	var buf bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(output)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+
+			toAPIError(tt.err)
+
+			assert.Equal(t, tt.logged, buf.Len() > 0)
+		})
+	}
+}
@@ -2,25 +2,107 @@ package service
 
 import (
 	"context"
+	"coupon-service/api/grpc/interceptor"
 	"coupon-service/internal/application"
 	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/auth"
+	"encoding/json"
 	"github.com/bufbuild/connect-go"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Sujin1135/coupon-service-interface/protobuf/entity"
 	svcpb "github.com/Sujin1135/coupon-service-interface/protobuf/service"
 	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
 )
 
+const (
+	// StockShardsHeader 캠페인 생성 시 재고 카운터 샤드 수를 지정하는 요청 헤더. 1 이상 domain.MaxStockShards 이하이며 발급 수량을 넘을 수 없다
+	StockShardsHeader = "Coupon-Stock-Shards"
+	// DeviceIDHeader 발급 요청 시 클라이언트가 전달하는 기기 식별값. 어뷰징 검사에 사용한다
+	DeviceIDHeader = "Coupon-Device-Id"
+	// EligibilityHeader 캠페인 생성 시 발급 대상 조건을 지정하는 요청 헤더(JSON)
+	EligibilityHeader = "Coupon-Eligibility"
+	// UserSegmentsHeader, UserRegionHeader 발급 요청 시 사용자의 세그먼트(쉼표로 구분)와 지역
+	// 인증된 사용자의 요청에서는 토큰의 클레임을 사용하고 헤더는 무시한다
+	UserSegmentsHeader = "Coupon-User-Segments"
+	UserRegionHeader   = "Coupon-User-Region"
+	// CodeSourceHeader 캠페인 생성 시 코드를 만드는 방식(generated, pool, shared)을 지정하는 요청 헤더
+	CodeSourceHeader = "Coupon-Code-Source"
+	// SharedCodeHeader, PerUserLimitHeader 공용 코드 캠페인의 코드와 사용자당 사용 횟수(기본 1)
+	SharedCodeHeader   = "Coupon-Shared-Code"
+	PerUserLimitHeader = "Coupon-Per-User-Limit"
+	// UsageValidityHeader 캠페인 생성 시 발급된 쿠폰의 사용 기간을 지정하는 요청 헤더(JSON)
+	UsageValidityHeader = "Coupon-Usage-Validity"
+	// ValidFromHeader, ValidUntilHeader 발급 응답에 발급된 쿠폰의 사용 기간(RFC 3339)을 전달하는 응답 헤더
+	// 기한이 없는 쿠폰은 ValidUntilHeader 를 설정하지 않는다
+	ValidFromHeader  = "Coupon-Valid-From"
+	ValidUntilHeader = "Coupon-Valid-Until"
+)
+
+// UserProcedures 관리자 scope 없이 인증된 사용자가 호출할 수 있는 RPC. 나머지 RPC 는 관리자만 호출할 수 있다
+func UserProcedures() []string {
+	return []string{
+		serviceconnect.GreetServiceIssueCouponProcedure,
+		serviceconnect.GreetServiceGetCampaignProcedure,
+		RedemptionServiceRedeemProcedure,
+		CampaignServiceWatchCampaignProcedure,
+		CampaignServiceGetCampaignAvailabilityProcedure,
+		CampaignServiceGetUserClaimStatusProcedure,
+		CampaignServiceGetUserClaimStatusesProcedure,
+	}
+}
+
+// usageValidityHeader UsageValidityHeader 의 형식. from, until 은 RFC 3339 시각, duration 은 "72h" 와 같은 기간 문자열이다
+type usageValidityHeader struct {
+	From     *time.Time `json:"from"`
+	Until    *time.Time `json:"until"`
+	Duration string     `json:"duration"`
+}
+
+// eligibilityHeader EligibilityHeader 의 형식. new_user_max_age 는 "720h" 와 같은 기간 문자열이다
+type eligibilityHeader struct {
+	UserListMode  string   `json:"user_list_mode"`
+	Segments      []string `json:"segments"`
+	NewUsersOnly  bool     `json:"new_users_only"`
+	NewUserMaxAge string   `json:"new_user_max_age"`
+	Regions       []string `json:"regions"`
+}
+
 type GreetServiceHandler struct {
 	serviceconnect.UnimplementedGreetServiceHandler
-	couponService *application.CouponService
+	couponService     *application.CouponService
+	connectErrors     bool
+	trustForwardedFor bool
 }
 
-func NewGreetServiceHandler(couponService *application.CouponService) *GreetServiceHandler {
-	return &GreetServiceHandler{
+type HandlerOption func(*GreetServiceHandler)
+
+// WithConnectErrors 모든 요청의 실패를 connect 에러 코드로 반환한다
+func WithConnectErrors() HandlerOption {
+	return func(s *GreetServiceHandler) {
+		s.connectErrors = true
+	}
+}
+
+// WithTrustForwardedFor X-Forwarded-For 의 첫 번째 주소를 클라이언트 IP 로 사용한다
+func WithTrustForwardedFor() HandlerOption {
+	return func(s *GreetServiceHandler) {
+		s.trustForwardedFor = true
+	}
+}
+
+func NewGreetServiceHandler(couponService *application.CouponService, opts ...HandlerOption) *GreetServiceHandler {
+	handler := &GreetServiceHandler{
 		couponService: couponService,
 	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler
 }
 
 func (s *GreetServiceHandler) CreateCampaign(
@@ -30,40 +112,62 @@ func (s *GreetServiceHandler) CreateCampaign(
 	// 요청 데이터 추출
 	name := req.Msg.Name
 	amount := req.Msg.Amount
-	issuedAt := req.Msg.IssuedAt.AsTime()
-	expiresAt := req.Msg.ExpiresAt.AsTime()
+	issuedAt := timestampToTime(req.Msg.IssuedAt)
+	expiresAt := timestampToTime(req.Msg.ExpiresAt)
 
-	campaign, err := s.couponService.CreateCoupon(ctx, name, amount, issuedAt, expiresAt)
-	if err != nil {
-		switch err {
-		case application.FailedSaveCouponError, application.CouponDataRecoveryError,
-			application.CouponCacheDataRecoveryError, application.CouponCacheError:
-			message := err.Error()
-			return connect.NewResponse(&svcpb.CreateCampaignResponse{
-				Value: &svcpb.CreateCampaignResponse_Error_{
-					Error: &svcpb.CreateCampaignResponse_Error{
-						Error: &svcpb.CreateCampaignResponse_Error_InternalProblem{
-							InternalProblem: &entity.InternalError{
-								Message: &message,
-							},
-						},
-					},
-				},
-			}), nil
-		default:
-			message := "internal error occurred"
-			return connect.NewResponse(&svcpb.CreateCampaignResponse{
-				Value: &svcpb.CreateCampaignResponse_Error_{
-					Error: &svcpb.CreateCampaignResponse_Error{
-						Error: &svcpb.CreateCampaignResponse_Error_InternalProblem{
-							InternalProblem: &entity.InternalError{
-								Message: &message,
-							},
-						},
-					},
-				},
-			}), nil
+	var opts []domain.CouponOption
+	if value := req.Header().Get(StockShardsHeader); value != "" {
+		shards, parseErr := strconv.Atoi(value)
+		if parseErr != nil || shards < 1 {
+			return s.createCampaignFailure(req.Header(), invalidArgument(
+				"INVALID_STOCK_SHARDS", "invalid "+StockShardsHeader+" header",
+			))
+		}
+		opts = append(opts, domain.WithStockShards(shards))
+	}
+	switch domain.CodeSource(req.Header().Get(CodeSourceHeader)) {
+	case "", domain.CodeSourceGenerated:
+	case domain.CodeSourcePool:
+		opts = append(opts, domain.WithCodePool())
+	case domain.CodeSourceShared:
+		perUserLimit := 1
+		if value := req.Header().Get(PerUserLimitHeader); value != "" {
+			limit, parseErr := strconv.Atoi(value)
+			if parseErr != nil {
+				return s.createCampaignFailure(req.Header(), invalidArgument(
+					"INVALID_PER_USER_LIMIT", "invalid "+PerUserLimitHeader+" header",
+				))
+			}
+			perUserLimit = limit
+		}
+		opts = append(opts, domain.WithSharedCode(req.Header().Get(SharedCodeHeader), perUserLimit))
+	default:
+		return s.createCampaignFailure(req.Header(), invalidArgument(
+			"INVALID_CODE_SOURCE", "invalid "+CodeSourceHeader+" header",
+		))
+	}
+	if value := req.Header().Get(EligibilityHeader); value != "" {
+		rules, parseErr := parseEligibility(value)
+		if parseErr != nil {
+			return s.createCampaignFailure(req.Header(), invalidArgument(
+				"INVALID_ELIGIBILITY", "invalid "+EligibilityHeader+" header",
+			))
+		}
+		opts = append(opts, domain.WithEligibility(rules))
+	}
+	if value := req.Header().Get(UsageValidityHeader); value != "" {
+		validity, parseErr := parseUsageValidity(value)
+		if parseErr != nil {
+			return s.createCampaignFailure(req.Header(), invalidArgument(
+				"INVALID_USAGE_VALIDITY", "invalid "+UsageValidityHeader+" header",
+			))
 		}
+		opts = append(opts, domain.WithUsageValidity(validity))
+	}
+
+	campaign, err := s.couponService.CreateCoupon(ctx, name, amount, issuedAt, expiresAt, opts...)
+	if err != nil {
+		return s.createCampaignFailure(req.Header(), toAPIError(err))
 	}
 
 	protoCampaign := domainCampaignToProtoCampaign(campaign)
@@ -83,53 +187,13 @@ func (s *GreetServiceHandler) IssueCoupon(
 	ctx context.Context,
 	req *connect.Request[svcpb.IssueCouponRequest],
 ) (*connect.Response[svcpb.IssueCouponResponse], error) {
+	// campaign_id 에는 캠페인 ID 대신 공용 코드를 전달할 수도 있다
 	campaignID := req.Msg.CampaignId
-	userID := req.Msg.UserId
+	userID, issueOpts := s.issueOptions(ctx, req.Peer(), req.Header(), req.Msg.UserId)
 
-	err := s.couponService.IssueCoupon(ctx, campaignID, userID)
+	issuedCoupon, err := s.couponService.Issue(ctx, campaignID, userID, issueOpts...)
 	if err != nil {
-		switch err {
-		case application.DataKeyNotFoundError:
-			message := err.Error()
-			return connect.NewResponse(&svcpb.IssueCouponResponse{
-				Value: &svcpb.IssueCouponResponse_Error_{
-					Error: &svcpb.IssueCouponResponse_Error{
-						Error: &svcpb.IssueCouponResponse_Error_NotFound{
-							NotFound: &entity.NotFoundError{
-								Message: &message,
-							},
-						},
-					},
-				},
-			}), nil
-		case application.CouponNotStartedError, application.CouponExpiredError,
-			application.DuplicatedCouponUserError, application.AllCouponIssuedError:
-			message := err.Error()
-			return connect.NewResponse(&svcpb.IssueCouponResponse{
-				Value: &svcpb.IssueCouponResponse_Error_{
-					Error: &svcpb.IssueCouponResponse_Error{
-						Error: &svcpb.IssueCouponResponse_Error_BadRequest{
-							BadRequest: &entity.BadRequestError{
-								Message: &message,
-							},
-						},
-					},
-				},
-			}), nil
-		default:
-			message := err.Error()
-			return connect.NewResponse(&svcpb.IssueCouponResponse{
-				Value: &svcpb.IssueCouponResponse_Error_{
-					Error: &svcpb.IssueCouponResponse_Error{
-						Error: &svcpb.IssueCouponResponse_Error_InternalProblem{
-							InternalProblem: &entity.InternalError{
-								Message: &message,
-							},
-						},
-					},
-				},
-			}), nil
-		}
+		return s.issueCouponFailure(req.Header(), err)
 	}
 
 	resp := connect.NewResponse(&svcpb.IssueCouponResponse{
@@ -139,6 +203,7 @@ func (s *GreetServiceHandler) IssueCoupon(
 			},
 		},
 	})
+	setValidityHeaders(resp.Header(), issuedCoupon)
 
 	return resp, nil
 }
@@ -151,18 +216,24 @@ func (s *GreetServiceHandler) GetCampaign(
 
 	campaign, err := s.couponService.GetCoupon(id)
 	if err != nil {
-		message := "campaign not found"
-		return connect.NewResponse(&svcpb.GetCampaignResponse{
-			Value: &svcpb.GetCampaignResponse_Error_{
-				Error: &svcpb.GetCampaignResponse_Error{
-					Error: &svcpb.GetCampaignResponse_Error_NotFound{
-						NotFound: &entity.NotFoundError{
-							Message: &message,
-						},
-					},
-				},
+		apiErr := toAPIError(err)
+		// 응답 메시지에는 NotFound 필드만 있으므로 그 외의 에러는 항상 connect 에러로 반환한다
+		if apiErr.category != categoryNotFound {
+			return nil, apiErr.connectError(apiErr.detail())
+		}
+		payload := &svcpb.GetCampaignResponse_Error{
+			Error: &svcpb.GetCampaignResponse_Error_NotFound{
+				NotFound: apiErr.notFound(),
 			},
-		}), nil
+		}
+		if s.useConnectErrors(req.Header()) {
+			return nil, apiErr.connectError(payload)
+		}
+		resp := connect.NewResponse(&svcpb.GetCampaignResponse{
+			Value: &svcpb.GetCampaignResponse_Error_{Error: payload},
+		})
+		apiErr.setHeaders(resp.Header())
+		return resp, nil
 	}
 
 	protoCampaign := domainCampaignToProtoCampaign(campaign)
@@ -178,6 +249,108 @@ func (s *GreetServiceHandler) GetCampaign(
 	return resp, nil
 }
 
+// issueOptions 발급 요청의 사용자 ID 와 발급 옵션
+// 일반 사용자는 요청 본문의 user_id 대신 토큰의 subject 로만 발급받을 수 있다
+func (s *GreetServiceHandler) issueOptions(
+	ctx context.Context,
+	peer connect.Peer,
+	header http.Header,
+	userID string,
+) (string, []application.IssueOption) {
+	issueOpts := []application.IssueOption{
+		application.WithClient(
+			interceptor.ClientIP(peer.Addr, header, s.trustForwardedFor),
+			header.Get(DeviceIDHeader),
+		),
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && !principal.Admin {
+		return principal.Claims.Subject, append(issueOpts,
+			application.WithAccountCreatedAt(principal.Claims.AccountCreatedAt),
+			application.WithSegments(principal.Claims.Segments...),
+			application.WithRegion(principal.Claims.Region),
+		)
+	}
+	return userID, append(issueOpts,
+		application.WithSegments(splitHeaderValues(header.Get(UserSegmentsHeader))...),
+		application.WithRegion(strings.TrimSpace(header.Get(UserRegionHeader))),
+	)
+}
+
+// useConnectErrors 서버 설정 또는 요청 헤더로 connect 에러 코드 응답을 선택했는지 확인한다
+func (s *GreetServiceHandler) useConnectErrors(header http.Header) bool {
+	return s.connectErrors || strings.EqualFold(header.Get(ErrorModeHeader), errorModeConnect)
+}
+
+func (s *GreetServiceHandler) createCampaignFailure(
+	header http.Header,
+	apiErr apiError,
+) (*connect.Response[svcpb.CreateCampaignResponse], error) {
+	payload := createCampaignError(apiErr)
+	if s.useConnectErrors(header) {
+		return nil, apiErr.connectError(payload)
+	}
+	resp := connect.NewResponse(&svcpb.CreateCampaignResponse{
+		Value: &svcpb.CreateCampaignResponse_Error_{Error: payload},
+	})
+	apiErr.setHeaders(resp.Header())
+	return resp, nil
+}
+
+// createCampaignError 응답 메시지에 NotFound 필드가 없으므로 내부 에러로 응답한다
+func createCampaignError(apiErr apiError) *svcpb.CreateCampaignResponse_Error {
+	if apiErr.category == categoryBadRequest {
+		return &svcpb.CreateCampaignResponse_Error{
+			Error: &svcpb.CreateCampaignResponse_Error_BadRequest{BadRequest: apiErr.badRequest()},
+		}
+	}
+	return &svcpb.CreateCampaignResponse_Error{
+		Error: &svcpb.CreateCampaignResponse_Error_InternalProblem{InternalProblem: apiErr.internal()},
+	}
+}
+
+// issueCouponFailure 발급 실패를 응답 메시지의 에러 필드로, 요청하면 connect 에러로 반환한다
+func (s *GreetServiceHandler) issueCouponFailure(
+	header http.Header,
+	err error,
+) (*connect.Response[svcpb.IssueCouponResponse], error) {
+	apiErr := toAPIError(err)
+	payload := issueCouponError(apiErr)
+	if s.useConnectErrors(header) {
+		return nil, apiErr.connectError(payload)
+	}
+	resp := connect.NewResponse(&svcpb.IssueCouponResponse{
+		Value: &svcpb.IssueCouponResponse_Error_{Error: payload},
+	})
+	apiErr.setHeaders(resp.Header())
+	return resp, nil
+}
+
+func issueCouponError(apiErr apiError) *svcpb.IssueCouponResponse_Error {
+	switch apiErr.category {
+	case categoryNotFound:
+		return &svcpb.IssueCouponResponse_Error{
+			Error: &svcpb.IssueCouponResponse_Error_NotFound{NotFound: apiErr.notFound()},
+		}
+	case categoryBadRequest:
+		return &svcpb.IssueCouponResponse_Error{
+			Error: &svcpb.IssueCouponResponse_Error_BadRequest{BadRequest: apiErr.badRequest()},
+		}
+	default:
+		return &svcpb.IssueCouponResponse_Error{
+			Error: &svcpb.IssueCouponResponse_Error_InternalProblem{InternalProblem: apiErr.internal()},
+		}
+	}
+}
+
+// timestampToTime 값이 없거나 올바르지 않은 타임스탬프는 zero time 으로 변환해 도메인 검증에서 거부되도록 한다
+// nil 에 AsTime 을 호출하면 1970-01-01 이 되어 유효한 값처럼 보이기 때문이다
+func timestampToTime(ts *timestamppb.Timestamp) time.Time {
+	if !ts.IsValid() {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
 func domainCampaignToProtoCampaign(campaign *domain.Coupon) *entity.Campaign {
 	issuedCoupons := make([]*entity.IssuedCoupon, len(campaign.IssuedCoupons))
 	for i, issuedCoupon := range campaign.IssuedCoupons {
@@ -201,3 +374,58 @@ func domainCampaignToProtoCampaign(campaign *domain.Coupon) *entity.Campaign {
 		ModifiedAt:    timestamppb.New(campaign.ModifiedAt),
 	}
 }
+
+func parseEligibility(value string) (domain.EligibilityRules, error) {
+	var header eligibilityHeader
+	if err := json.Unmarshal([]byte(value), &header); err != nil {
+		return domain.EligibilityRules{}, err
+	}
+	rules := domain.EligibilityRules{
+		UserListMode: domain.UserListMode(header.UserListMode),
+		Segments:     header.Segments,
+		NewUsersOnly: header.NewUsersOnly,
+		Regions:      header.Regions,
+	}
+	if header.NewUserMaxAge != "" {
+		maxAge, err := time.ParseDuration(header.NewUserMaxAge)
+		if err != nil {
+			return domain.EligibilityRules{}, err
+		}
+		rules.NewUserMaxAge = maxAge
+	}
+	return rules, nil
+}
+
+func parseUsageValidity(value string) (domain.UsageValidity, error) {
+	var header usageValidityHeader
+	if err := json.Unmarshal([]byte(value), &header); err != nil {
+		return domain.UsageValidity{}, err
+	}
+	validity := domain.UsageValidity{From: header.From, Until: header.Until}
+	if header.Duration != "" {
+		duration, err := time.ParseDuration(header.Duration)
+		if err != nil {
+			return domain.UsageValidity{}, err
+		}
+		validity.Duration = duration
+	}
+	return validity, nil
+}
+
+func setValidityHeaders(header http.Header, issuedCoupon *domain.IssuedCoupon) {
+	header.Set(ValidFromHeader, issuedCoupon.ValidFrom.UTC().Format(time.RFC3339))
+	if issuedCoupon.ValidUntil != nil {
+		header.Set(ValidUntilHeader, issuedCoupon.ValidUntil.UTC().Format(time.RFC3339))
+	}
+}
+
+// splitHeaderValues 쉼표로 구분된 헤더 값에서 빈 값을 제외한 목록
+func splitHeaderValues(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
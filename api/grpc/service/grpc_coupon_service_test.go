@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	svcpb "github.com/Sujin1135/coupon-service-interface/protobuf/service"
+)
+
+func newInvalidShardsRequest() *connect.Request[svcpb.CreateCampaignRequest] {
+	req := connect.NewRequest(&svcpb.CreateCampaignRequest{
+		Name:      "campaign",
+		Amount:    10,
+		IssuedAt:  timestamppb.Now(),
+		ExpiresAt: timestamppb.Now(),
+	})
+	req.Header().Set(StockShardsHeader, "0")
+	return req
+}
+
+func TestCreateCampaignErrorMode(t *testing.T) {
+	t.Run("기본적으로 실패는 응답 메시지의 에러 필드로 반환되어야 한다", func(t *testing.T) {
+		handler := NewGreetServiceHandler(nil)
+
+		resp, err := handler.CreateCampaign(context.Background(), newInvalidShardsRequest())
+
+		require.NoError(t, err)
+		assert.NotNil(t, resp.Msg.GetError().GetBadRequest())
+		assert.Equal(t, "INVALID_STOCK_SHARDS", resp.Header().Get(ErrorCodeHeader))
+		assert.Equal(t, "false", resp.Header().Get(ErrorRetryableHeader))
+	})
+
+	t.Run("헤더로 요청하면 실패는 connect 에러로 반환되어야 한다", func(t *testing.T) {
+		handler := NewGreetServiceHandler(nil)
+		req := newInvalidShardsRequest()
+		req.Header().Set(ErrorModeHeader, "connect")
+
+		_, err := handler.CreateCampaign(context.Background(), req)
+
+		assertInvalidShardsConnectError(t, err)
+	})
+
+	t.Run("옵션으로 활성화하면 실패는 connect 에러로 반환되어야 한다", func(t *testing.T) {
+		handler := NewGreetServiceHandler(nil, WithConnectErrors())
+
+		_, err := handler.CreateCampaign(context.Background(), newInvalidShardsRequest())
+
+		assertInvalidShardsConnectError(t, err)
+	})
+}
+
+func assertInvalidShardsConnectError(t *testing.T, err error) {
+	var connectErr *connect.Error
+	require.True(t, errors.As(err, &connectErr))
+	assert.Equal(t, connect.CodeInvalidArgument, connectErr.Code())
+	assert.Equal(t, "INVALID_STOCK_SHARDS", connectErr.Meta().Get(ErrorCodeHeader))
+
+	require.Len(t, connectErr.Details(), 1)
+	detail, valueErr := connectErr.Details()[0].Value()
+	require.NoError(t, valueErr)
+	payload, ok := detail.(*svcpb.CreateCampaignResponse_Error)
+	require.True(t, ok)
+	assert.NotNil(t, payload.GetBadRequest())
+}
+
+func TestIssueCouponFailure(t *testing.T) {
+	handler := NewGreetServiceHandler(nil)
+	cause := errors.New("cause")
+	tests := []struct {
+		name      string
+		err       error
+		code      connect.Code
+		errorCode string
+		retryable string
+		field     func(*svcpb.IssueCouponResponse_Error) any
+	}{
+		{
+			name:      "매진은 ResourceExhausted 로 반환되어야 한다",
+			err:       application.AllCouponIssuedError,
+			code:      connect.CodeResourceExhausted,
+			errorCode: "COUPON_SOLD_OUT",
+			retryable: "false",
+			field:     func(e *svcpb.IssueCouponResponse_Error) any { return e.GetBadRequest() },
+		},
+		{
+			name:      "캠페인을 찾을 수 없으면 NotFound 로 반환되어야 한다",
+			err:       application.DataKeyNotFoundError.Wrap(cause),
+			code:      connect.CodeNotFound,
+			errorCode: "COUPON_DATA_NOT_FOUND",
+			retryable: "false",
+			field:     func(e *svcpb.IssueCouponResponse_Error) any { return e.GetNotFound() },
+		},
+		{
+			name:      "중복 발급은 AlreadyExists 로 반환되어야 한다",
+			err:       application.DuplicatedCouponUserError,
+			code:      connect.CodeAlreadyExists,
+			errorCode: "COUPON_ALREADY_ISSUED",
+			retryable: "false",
+			field:     func(e *svcpb.IssueCouponResponse_Error) any { return e.GetBadRequest() },
+		},
+		{
+			name:      "Redis 를 사용할 수 없으면 재시도 가능한 Unavailable 로 반환되어야 한다",
+			err:       application.CacheUnavailableError.Wrap(cause),
+			code:      connect.CodeUnavailable,
+			errorCode: "CACHE_UNAVAILABLE",
+			retryable: "true",
+			field:     func(e *svcpb.IssueCouponResponse_Error) any { return e.GetInternalProblem() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := handler.issueCouponFailure(http.Header{}, tt.err)
+
+			require.NoError(t, err)
+			assert.NotNil(t, tt.field(resp.Msg.GetError()))
+			assert.Equal(t, tt.errorCode, resp.Header().Get(ErrorCodeHeader))
+			assert.Equal(t, tt.retryable, resp.Header().Get(ErrorRetryableHeader))
+
+			header := http.Header{}
+			header.Set(ErrorModeHeader, errorModeConnect)
+			_, err = handler.issueCouponFailure(header, tt.err)
+
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			assert.Equal(t, tt.code, connectErr.Code())
+			assert.Equal(t, tt.errorCode, connectErr.Meta().Get(ErrorCodeHeader))
+			assert.Equal(t, tt.retryable, connectErr.Meta().Get(ErrorRetryableHeader))
+		})
+	}
+}
+
+func TestCreateCampaignValidation(t *testing.T) {
+	// 검증은 저장소에 접근하기 전에 끝나므로 저장소 없이 동작하는 MySQL 전략으로 생성한다
+	couponService := application.NewCouponService(nil, nil, nil, application.WithIssuanceStrategy(application.MySQLIssuance))
+	handler := NewGreetServiceHandler(couponService)
+	newRequest := func() *connect.Request[svcpb.CreateCampaignRequest] {
+		return connect.NewRequest(&svcpb.CreateCampaignRequest{
+			Name:   strings.Repeat("a", 21),
+			Amount: 0,
+		})
+	}
+
+	t.Run("검증에 실패한 필드는 BadRequest 로 반환되어야 한다", func(t *testing.T) {
+		resp, err := handler.CreateCampaign(context.Background(), newRequest())
+
+		require.NoError(t, err)
+		badRequest := resp.Msg.GetError().GetBadRequest()
+		require.NotNil(t, badRequest)
+		assert.Equal(t,
+			"invalid request: name: must be at most 20 characters, amount: must be greater than 0, "+
+				"issued_at: must be set, expires_at: must be set",
+			badRequest.GetMessage(),
+		)
+		assert.Equal(t, "INVALID_REQUEST", resp.Header().Get(ErrorCodeHeader))
+	})
+
+	t.Run("connect 에러 모드에서는 검증에 실패한 필드가 에러 상세로 반환되어야 한다", func(t *testing.T) {
+		req := newRequest()
+		req.Header().Set(ErrorModeHeader, "connect")
+
+		_, err := handler.CreateCampaign(context.Background(), req)
+
+		var connectErr *connect.Error
+		require.True(t, errors.As(err, &connectErr))
+		assert.Equal(t, connect.CodeInvalidArgument, connectErr.Code())
+		require.Len(t, connectErr.Details(), 2)
+		detail, valueErr := connectErr.Details()[1].Value()
+		require.NoError(t, valueErr)
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		require.True(t, ok)
+		assert.Len(t, badRequest.GetFieldViolations(), 4)
+	})
+}
+
+func TestCreateCampaignEligibilityHeader(t *testing.T) {
+	couponService := application.NewCouponService(nil, nil, nil, application.WithIssuanceStrategy(application.MySQLIssuance))
+	handler := NewGreetServiceHandler(couponService)
+	newRequest := func(eligibility string) *connect.Request[svcpb.CreateCampaignRequest] {
+		req := connect.NewRequest(&svcpb.CreateCampaignRequest{
+			Name:      "campaign",
+			Amount:    10,
+			IssuedAt:  timestamppb.Now(),
+			ExpiresAt: timestamppb.New(time.Now().Add(time.Hour)),
+		})
+		req.Header().Set(EligibilityHeader, eligibility)
+		return req
+	}
+
+	t.Run("형식이 잘못된 헤더는 거절되어야 한다", func(t *testing.T) {
+		resp, err := handler.CreateCampaign(context.Background(), newRequest(`{"new_user_max_age":"30 days"}`))
+
+		require.NoError(t, err)
+		assert.NotNil(t, resp.Msg.GetError().GetBadRequest())
+		assert.Equal(t, "INVALID_ELIGIBILITY", resp.Header().Get(ErrorCodeHeader))
+	})
+
+	t.Run("올바르지 않은 조건은 검증 실패로 반환되어야 한다", func(t *testing.T) {
+		resp, err := handler.CreateCampaign(context.Background(), newRequest(`{"user_list_mode":"everyone"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t,
+			"invalid request: eligibility.user_list_mode: must be one of allow, deny",
+			resp.Msg.GetError().GetBadRequest().GetMessage(),
+		)
+	})
+}
+
+func TestParseEligibility(t *testing.T) {
+	rules, err := parseEligibility(`{"user_list_mode":"allow","segments":["vip"],"new_users_only":true,"new_user_max_age":"720h","regions":["KR"]}`)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.EligibilityRules{
+		UserListMode:  domain.UserListAllow,
+		Segments:      []string{"vip"},
+		NewUsersOnly:  true,
+		NewUserMaxAge: 720 * time.Hour,
+		Regions:       []string{"KR"},
+	}, rules)
+	assert.Equal(t, []string{"vip", "beta"}, splitHeaderValues(" vip, ,beta"))
+}
+
+func TestCreateCampaignSharedCodeHeaders(t *testing.T) {
+	couponService := application.NewCouponService(nil, nil, nil, application.WithIssuanceStrategy(application.MySQLIssuance))
+	handler := NewGreetServiceHandler(couponService)
+	newRequest := func(code, perUserLimit string) *connect.Request[svcpb.CreateCampaignRequest] {
+		req := connect.NewRequest(&svcpb.CreateCampaignRequest{
+			Name:      "campaign",
+			Amount:    10,
+			IssuedAt:  timestamppb.Now(),
+			ExpiresAt: timestamppb.New(time.Now().Add(time.Hour)),
+		})
+		req.Header().Set(CodeSourceHeader, string(domain.CodeSourceShared))
+		req.Header().Set(SharedCodeHeader, code)
+		req.Header().Set(PerUserLimitHeader, perUserLimit)
+		return req
+	}
+
+	t.Run("형식이 잘못된 사용자별 발급 한도는 거절되어야 한다", func(t *testing.T) {
+		resp, err := handler.CreateCampaign(context.Background(), newRequest("SPRING2026", "many"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "INVALID_PER_USER_LIMIT", resp.Header().Get(ErrorCodeHeader))
+	})
+
+	t.Run("공용 코드가 없으면 검증 실패로 반환되어야 한다", func(t *testing.T) {
+		resp, err := handler.CreateCampaign(context.Background(), newRequest("", "2"))
+
+		require.NoError(t, err)
+		assert.Equal(t,
+			"invalid request: shared_code: must not be empty",
+			resp.Msg.GetError().GetBadRequest().GetMessage(),
+		)
+	})
+}
+
+func TestRedeemRequiresCode(t *testing.T) {
+	handler := NewGreetServiceHandler(nil)
+
+	_, err := handler.Redeem(context.Background(), connect.NewRequest(&structpb.Struct{}))
+
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	var connectErr *connect.Error
+	require.ErrorAs(t, err, &connectErr)
+	assert.Equal(t, "INVALID_CODE", connectErr.Meta().Get(ErrorCodeHeader))
+}
+
+func TestParseUsageValidity(t *testing.T) {
+	validity, err := parseUsageValidity(`{"until":"2026-04-30T00:00:00Z","duration":"72h"}`)
+
+	require.NoError(t, err)
+	until := time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+	assert.Nil(t, validity.From)
+	assert.True(t, until.Equal(*validity.Until))
+	assert.Equal(t, 72*time.Hour, validity.Duration)
+
+	_, err = parseUsageValidity(`{"duration":"3 days"}`)
+	assert.Error(t, err)
+}
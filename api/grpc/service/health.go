@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
+	"github.com/bufbuild/connect-grpchealth-go"
+	"github.com/bufbuild/connect-grpcreflect-go"
+)
+
+// ServiceNames 서버가 제공하는 서비스 이름. 헬스 체크는 모든 서비스의 상태를 제공한다
+func ServiceNames() []string {
+	return []string{
+		serviceconnect.GreetServiceName,
+		AdminServiceName,
+		RedemptionServiceName,
+		CampaignServiceName,
+		grpchealth.HealthV1ServiceName,
+		grpcreflect.ReflectV1ServiceName,
+		grpcreflect.ReflectV1AlphaServiceName,
+	}
+}
+
+// ReflectionServiceNames 리플렉션으로 조회할 수 있는 서비스 이름
+// AdminService, RedemptionService, CampaignService 는 proto 정의 없이 structpb 등의 메시지로 만든 서비스이므로 포함하지 않는다
+func ReflectionServiceNames() []string {
+	return []string{
+		serviceconnect.GreetServiceName,
+		grpchealth.HealthV1ServiceName,
+		grpcreflect.ReflectV1ServiceName,
+		grpcreflect.ReflectV1AlphaServiceName,
+	}
+}
+
+// HealthChecker 서비스별 상태를 제공하는 grpchealth.Checker
+// grpchealth.StaticChecker 는 서버 전체(빈 서비스 이름)를 항상 SERVING 으로 응답하므로 종료를 시작하면 NOT_SERVING 으로 응답한다
+type HealthChecker struct {
+	*grpchealth.StaticChecker
+	services []string
+	shutdown atomic.Bool
+}
+
+// NewHealthChecker services 를 SERVING 상태로 만든다
+func NewHealthChecker(services ...string) *HealthChecker {
+	return &HealthChecker{StaticChecker: grpchealth.NewStaticChecker(services...), services: services}
+}
+
+// Shutdown 종료를 시작하면 모든 서비스를 NOT_SERVING 으로 바꿔 새 요청이 들어오지 않도록 한다
+func (h *HealthChecker) Shutdown() {
+	h.shutdown.Store(true)
+	for _, service := range h.services {
+		h.SetStatus(service, grpchealth.StatusNotServing)
+	}
+}
+
+// Check 등록되지 않은 서비스는 NotFound 로 응답한다
+func (h *HealthChecker) Check(ctx context.Context, req *grpchealth.CheckRequest) (*grpchealth.CheckResponse, error) {
+	if req.Service == "" && h.shutdown.Load() {
+		return &grpchealth.CheckResponse{Status: grpchealth.StatusNotServing}, nil
+	}
+	return h.StaticChecker.Check(ctx, req)
+}
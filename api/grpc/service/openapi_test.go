@@ -0,0 +1,116 @@
+package service
+
+import (
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// restSchema REST 게이트웨이 문서(api/rest/openapi.json)의 스키마 중 검증에 필요한 부분
+type restSchema struct {
+	Required   []string `json:"required"`
+	Properties map[string]struct {
+		Enum []string `json:"enum"`
+	} `json:"properties"`
+}
+
+// TestOpenAPISchemas REST 게이트웨이가 그대로 전달하는 RPC 응답과 헤더 형식이 OpenAPI 문서의 스키마와 같은지 확인한다
+func TestOpenAPISchemas(t *testing.T) {
+	document, err := os.ReadFile("../../rest/openapi.json")
+	require.NoError(t, err)
+	var spec struct {
+		Components struct {
+			Schemas map[string]restSchema `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(document, &spec))
+	schemas := spec.Components.Schemas
+
+	now := time.Now()
+	coupon := &domain.IssuedCoupon{
+		ID: "issued-1", CouponID: "campaign-1", Code: "CODE", Status: domain.IssuedCouponRedeemed,
+		ValidFrom: now, ValidUntil: &now, RedeemedAt: &now,
+	}
+	responses := []struct {
+		schema  string
+		full    *structpb.Struct
+		minimal *structpb.Struct
+	}{
+		{
+			schema:  "IssuedCoupon",
+			full:    issuedCouponValue(coupon),
+			minimal: issuedCouponValue(&domain.IssuedCoupon{}),
+		},
+		{
+			schema:  "CampaignAvailability",
+			full:    campaignAvailabilityValue(application.CampaignAvailability{Found: true}),
+			minimal: campaignAvailabilityValue(application.CampaignAvailability{}),
+		},
+		{
+			schema:  "ClaimStatus",
+			full:    claimStatusValue(application.ClaimStatus{Claimed: true, Coupon: coupon}),
+			minimal: claimStatusValue(application.ClaimStatus{}),
+		},
+	}
+	for _, response := range responses {
+		t.Run(response.schema+" 응답 필드가 스키마와 같아야 한다", func(t *testing.T) {
+			schema, ok := schemas[response.schema]
+			require.True(t, ok)
+			properties := make([]string, 0, len(schema.Properties))
+			for name := range schema.Properties {
+				properties = append(properties, name)
+			}
+			assert.ElementsMatch(t, properties, fieldNames(response.full))
+			assert.ElementsMatch(t, schema.Required, fieldNames(response.minimal))
+		})
+	}
+
+	t.Run("상태 값이 도메인의 상태와 같아야 한다", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{
+			string(domain.CampaignStatusScheduled), string(domain.CampaignStatusOpen),
+			string(domain.CampaignStatusPaused), string(domain.CampaignStatusSoldOut),
+			string(domain.CampaignStatusEnded),
+		}, schemas["CampaignAvailability"].Properties["status"].Enum)
+		assert.ElementsMatch(t, []string{
+			string(domain.IssuedCouponIssued), string(domain.IssuedCouponRedeemed),
+			string(domain.IssuedCouponExpired), string(domain.IssuedCouponRevoked),
+		}, schemas["IssuedCoupon"].Properties["status"].Enum)
+	})
+
+	headers := map[string]any{
+		"Eligibility":   eligibilityHeader{},
+		"UsageValidity": usageValidityHeader{},
+	}
+	for name, header := range headers {
+		t.Run(name+" 속성이 헤더 형식과 같아야 한다", func(t *testing.T) {
+			typ := reflect.TypeOf(header)
+			var tags []string
+			for i := 0; i < typ.NumField(); i++ {
+				tag, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+				tags = append(tags, tag)
+			}
+			properties := make([]string, 0, len(schemas[name].Properties))
+			for property := range schemas[name].Properties {
+				properties = append(properties, property)
+			}
+			assert.ElementsMatch(t, tags, properties)
+		})
+	}
+}
+
+func fieldNames(value *structpb.Struct) []string {
+	names := make([]string, 0, len(value.GetFields()))
+	for name := range value.GetFields() {
+		names = append(names, name)
+	}
+	return names
+}
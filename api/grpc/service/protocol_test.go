@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	svcpb "github.com/Sujin1135/coupon-service-interface/protobuf/service"
+	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
+	"github.com/bufbuild/connect-go"
+	"github.com/bufbuild/connect-grpchealth-go"
+	"github.com/bufbuild/connect-grpcreflect-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// protocols 핸들러가 지원하는 세 프로토콜의 클라이언트 옵션
+var protocols = []struct {
+	name string
+	opts []connect.ClientOption
+}{
+	{name: "Connect"},
+	{name: "gRPC", opts: []connect.ClientOption{connect.WithGRPC()}},
+	{name: "gRPC-Web", opts: []connect.ClientOption{connect.WithGRPCWeb()}},
+}
+
+// newH2CServer 운영 서버와 같이 TLS 없는 HTTP/2(h2c) 로 핸들러를 제공한다
+func newH2CServer(t *testing.T, handler http.Handler) *httptest.Server {
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newH2CClient gRPC 는 HTTP/2 가 필요하므로 TLS 없이 HTTP/2 로 연결한다
+func newH2CClient() *http.Client {
+	return &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+// newGRPCConn grpcurl, Kubernetes 프로브와 같은 grpc-go 클라이언트로 연결한다
+func newGRPCConn(t *testing.T, server *httptest.Server) *grpc.ClientConn {
+	conn, err := grpc.NewClient(
+		strings.TrimPrefix(server.URL, "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// healthCheckProcedure 표준 헬스 체크 서비스의 Check 경로
+const healthCheckProcedure = "/" + grpchealth.HealthV1ServiceName + "/Check"
+
+func newHealthTestServer(t *testing.T) (*HealthChecker, *httptest.Server) {
+	checker := NewHealthChecker(CampaignServiceName)
+	mux := http.NewServeMux()
+	mux.Handle(grpchealth.NewHandler(checker))
+	return checker, newH2CServer(t, mux)
+}
+
+func TestHealth(t *testing.T) {
+	for _, protocol := range protocols {
+		t.Run(protocol.name+" 로 서비스 상태를 조회할 수 있어야 한다", func(t *testing.T) {
+			checker, server := newHealthTestServer(t)
+			check := connect.NewClient[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse](
+				newH2CClient(), server.URL+healthCheckProcedure, protocol.opts...,
+			)
+
+			for _, name := range []string{"", CampaignServiceName} {
+				res, err := check.CallUnary(context.Background(), connect.NewRequest(&healthpb.HealthCheckRequest{Service: name}))
+				require.NoError(t, err)
+				assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Msg.GetStatus())
+			}
+
+			_, err := check.CallUnary(context.Background(), connect.NewRequest(&healthpb.HealthCheckRequest{Service: "unknown"}))
+			assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+			checker.SetStatus(CampaignServiceName, grpchealth.StatusNotServing)
+			res, err := check.CallUnary(context.Background(), connect.NewRequest(&healthpb.HealthCheckRequest{Service: CampaignServiceName}))
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Msg.GetStatus())
+		})
+
+		t.Run(protocol.name+" 로 조회하면 종료를 시작한 뒤 서버 전체와 서비스가 NOT_SERVING 이어야 한다", func(t *testing.T) {
+			checker, server := newHealthTestServer(t)
+			check := connect.NewClient[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse](
+				newH2CClient(), server.URL+healthCheckProcedure, protocol.opts...,
+			)
+
+			checker.Shutdown()
+
+			for _, name := range []string{"", CampaignServiceName} {
+				res, err := check.CallUnary(context.Background(), connect.NewRequest(&healthpb.HealthCheckRequest{Service: name}))
+				require.NoError(t, err)
+				assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Msg.GetStatus())
+			}
+		})
+	}
+
+	t.Run("grpc-go 헬스 클라이언트로 조회할 수 있어야 한다", func(t *testing.T) {
+		_, server := newHealthTestServer(t)
+		client := healthpb.NewHealthClient(newGRPCConn(t, server))
+
+		res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+	})
+}
+
+func TestReflection(t *testing.T) {
+	reflector := grpcreflect.NewStaticReflector(ReflectionServiceNames()...)
+	mux := http.NewServeMux()
+	mux.Handle(grpcreflect.NewHandlerV1(reflector))
+	mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector))
+	conn := newGRPCConn(t, newH2CServer(t, mux))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	defer func() { _ = stream.CloseSend() }()
+
+	reflect := func(t *testing.T, req *reflectionpb.ServerReflectionRequest) *reflectionpb.ServerReflectionResponse {
+		t.Helper()
+		require.NoError(t, stream.Send(req))
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		require.Nil(t, res.GetErrorResponse())
+		return res
+	}
+
+	t.Run("모든 서비스 이름을 조회할 수 있어야 한다", func(t *testing.T) {
+		res := reflect(t, &reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+
+		var names []string
+		for _, service := range res.GetListServicesResponse().GetService() {
+			names = append(names, service.GetName())
+		}
+		assert.ElementsMatch(t, ReflectionServiceNames(), names)
+	})
+
+	for _, name := range ReflectionServiceNames() {
+		t.Run(name+" 의 메서드 정의를 조회할 수 있어야 한다", func(t *testing.T) {
+			res := reflect(t, &reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: name},
+			})
+
+			files := res.GetFileDescriptorResponse().GetFileDescriptorProto()
+			require.NotEmpty(t, files)
+			file := &descriptorpb.FileDescriptorProto{}
+			require.NoError(t, proto.Unmarshal(files[0], file))
+			var methods int
+			for _, service := range file.GetService() {
+				if file.GetPackage()+"."+service.GetName() == name {
+					methods = len(service.GetMethod())
+				}
+			}
+			assert.Positive(t, methods)
+		})
+	}
+
+	t.Run("v1alpha 를 사용하는 이전 클라이언트도 조회할 수 있어야 한다", func(t *testing.T) {
+		alphaStream, err := reflectionalphapb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		require.NoError(t, err)
+		defer func() { _ = alphaStream.CloseSend() }()
+
+		require.NoError(t, alphaStream.Send(&reflectionalphapb.ServerReflectionRequest{
+			MessageRequest: &reflectionalphapb.ServerReflectionRequest_ListServices{},
+		}))
+		res, err := alphaStream.Recv()
+		require.NoError(t, err)
+		assert.Len(t, res.GetListServicesResponse().GetService(), len(ReflectionServiceNames()))
+	})
+}
+
+// callUnary 프로토콜별 클라이언트로 단항 RPC 를 호출한다
+func callUnary[Req, Res any](
+	t *testing.T,
+	baseURL string,
+	procedure string,
+	opts []connect.ClientOption,
+	msg *Req,
+) (*Res, error) {
+	t.Helper()
+	client := connect.NewClient[Req, Res](newH2CClient(), baseURL+procedure, opts...)
+	res, err := client.CallUnary(context.Background(), connect.NewRequest(msg))
+	if err != nil {
+		return nil, err
+	}
+	return res.Msg, nil
+}
+
+func newStruct(t *testing.T, fields map[string]any) *structpb.Struct {
+	t.Helper()
+	msg, err := structpb.NewStruct(fields)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestProtocolsWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(
+		&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.CouponCodeEntity{},
+		&entity.WebhookSubscriptionEntity{}, &entity.WebhookDeliveryEntity{}, &entity.WebhookAttemptEntity{},
+	)
+	couponService := application.NewCouponService(
+		redisContainer.Client,
+		repository.NewCouponRepository(mysqlContainer.DB),
+		repository.NewIssuedCouponRepository(mysqlContainer.DB),
+		application.WithWebhooks(repository.NewWebhookRepository(mysqlContainer.DB), application.DefaultWebhookConfig),
+	)
+	greetService := NewGreetServiceHandler(couponService, WithConnectErrors())
+
+	mux := http.NewServeMux()
+	mux.Handle(serviceconnect.NewGreetServiceHandler(greetService))
+	mux.Handle(NewAdminServiceHTTPHandler(NewAdminServiceHandler(couponService)))
+	mux.Handle(NewRedemptionServiceHTTPHandler(greetService))
+	mux.Handle(NewCampaignServiceHTTPHandler(NewCampaignServiceHandler(couponService)))
+	mux.Handle(grpchealth.NewHandler(NewHealthChecker(ServiceNames()...)))
+	server := newH2CServer(t, mux)
+
+	for i, protocol := range protocols {
+		t.Run(protocol.name+" 로 모든 RPC 를 호출할 수 있어야 한다", func(t *testing.T) {
+			httpClient := newH2CClient()
+			greet := serviceconnect.NewGreetServiceClient(httpClient, server.URL, protocol.opts...)
+			now := time.Now()
+			userID := uuid.New().String()
+			newCampaignRequest := func(name string) *connect.Request[svcpb.CreateCampaignRequest] {
+				return connect.NewRequest(&svcpb.CreateCampaignRequest{
+					Name:      name,
+					Amount:    10,
+					IssuedAt:  timestamppb.New(now.Add(-time.Hour)),
+					ExpiresAt: timestamppb.New(now.Add(time.Hour)),
+				})
+			}
+
+			// GreetService
+			created, err := greet.CreateCampaign(ctx, newCampaignRequest("프로토콜 쿠폰"))
+			require.NoError(t, err)
+			campaignID := created.Msg.GetData().GetCampaign().GetId()
+			require.NotEmpty(t, campaignID)
+
+			campaign, err := greet.GetCampaign(ctx, connect.NewRequest(&svcpb.GetCampaignRequest{Id: campaignID}))
+			require.NoError(t, err)
+			assert.Equal(t, "프로토콜 쿠폰", campaign.Msg.GetData().GetCampaign().GetName())
+
+			issued, err := greet.IssueCoupon(ctx, connect.NewRequest(&svcpb.IssueCouponRequest{
+				CampaignId: campaignID,
+				UserId:     userID,
+			}))
+			require.NoError(t, err)
+			assert.True(t, issued.Msg.GetData().GetResult())
+
+			// CampaignService
+			availability, err := callUnary[structpb.ListValue, structpb.ListValue](
+				t, server.URL, CampaignServiceGetCampaignAvailabilityProcedure, protocol.opts,
+				stringListValue([]string{campaignID}),
+			)
+			require.NoError(t, err)
+			require.Len(t, availability.GetValues(), 1)
+			assert.Equal(t, float64(9), availability.GetValues()[0].GetStructValue().GetFields()["remaining"].GetNumberValue())
+
+			claimStatus, err := callUnary[structpb.Struct, structpb.Struct](
+				t, server.URL, CampaignServiceGetUserClaimStatusProcedure, protocol.opts,
+				newStruct(t, map[string]any{"campaign_id": campaignID, "user_id": userID}),
+			)
+			require.NoError(t, err)
+			assert.True(t, claimStatus.GetFields()["claimed"].GetBoolValue())
+			issuedCouponID := claimStatus.GetFields()["coupon"].GetStructValue().GetFields()["id"].GetStringValue()
+			require.NotEmpty(t, issuedCouponID)
+
+			claimStatuses, err := callUnary[structpb.Struct, structpb.ListValue](
+				t, server.URL, CampaignServiceGetUserClaimStatusesProcedure, protocol.opts,
+				newStruct(t, map[string]any{"campaign_ids": []any{campaignID}, "user_id": userID}),
+			)
+			require.NoError(t, err)
+			assert.Len(t, claimStatuses.GetValues(), 1)
+
+			watchCtx, cancelWatch := context.WithTimeout(ctx, 5*time.Second)
+			watch, err := connect.NewClient[wrapperspb.StringValue, structpb.Struct](
+				httpClient, server.URL+CampaignServiceWatchCampaignProcedure, protocol.opts...,
+			).CallServerStream(watchCtx, connect.NewRequest(wrapperspb.String(campaignID)))
+			require.NoError(t, err)
+			require.True(t, watch.Receive(), watch.Err())
+			assert.Equal(t, campaignID, watch.Msg().GetFields()["campaign_id"].GetStringValue())
+			cancelWatch()
+			_ = watch.Close()
+
+			// AdminService
+			revoked, err := callUnary[wrapperspb.StringValue, structpb.Struct](
+				t, server.URL, AdminServiceRevokeCouponProcedure, protocol.opts, wrapperspb.String(issuedCouponID),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, string(domain.IssuedCouponRevoked), revoked.GetFields()["status"].GetStringValue())
+
+			blockedUserID := uuid.New().String()
+			_, err = callUnary[wrapperspb.StringValue, emptypb.Empty](
+				t, server.URL, AdminServiceBlockUserProcedure, protocol.opts, wrapperspb.String(blockedUserID),
+			)
+			require.NoError(t, err)
+			blocked, err := callUnary[emptypb.Empty, structpb.ListValue](
+				t, server.URL, AdminServiceListBlockedUsersProcedure, protocol.opts, &emptypb.Empty{},
+			)
+			require.NoError(t, err)
+			assert.Contains(t, blocked.AsSlice(), blockedUserID)
+			_, err = callUnary[wrapperspb.StringValue, emptypb.Empty](
+				t, server.URL, AdminServiceUnblockUserProcedure, protocol.opts, wrapperspb.String(blockedUserID),
+			)
+			require.NoError(t, err)
+
+			campaignUsers := newStruct(t, map[string]any{"campaign_id": campaignID, "user_ids": []any{userID}})
+			_, err = callUnary[structpb.Struct, emptypb.Empty](
+				t, server.URL, AdminServiceAddCampaignUsersProcedure, protocol.opts, campaignUsers,
+			)
+			require.NoError(t, err)
+			listed, err := callUnary[wrapperspb.StringValue, structpb.ListValue](
+				t, server.URL, AdminServiceListCampaignUsersProcedure, protocol.opts, wrapperspb.String(campaignID),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, []any{userID}, listed.AsSlice())
+			_, err = callUnary[structpb.Struct, emptypb.Empty](
+				t, server.URL, AdminServiceRemoveCampaignUsersProcedure, protocol.opts, campaignUsers,
+			)
+			require.NoError(t, err)
+
+			poolRequest := newCampaignRequest("코드 풀 쿠폰")
+			poolRequest.Header().Set(CodeSourceHeader, string(domain.CodeSourcePool))
+			pool, err := greet.CreateCampaign(ctx, poolRequest)
+			require.NoError(t, err)
+			importer := connect.NewClient[wrapperspb.StringValue, structpb.Struct](
+				httpClient, server.URL+AdminServiceImportCodesProcedure, protocol.opts...,
+			).CallClientStream(ctx)
+			importer.RequestHeader().Set(CampaignIDHeader, pool.Msg.GetData().GetCampaign().GetId())
+			for _, code := range []string{fmt.Sprintf("POOL-%d-1", i), fmt.Sprintf("POOL-%d-2", i)} {
+				require.NoError(t, importer.Send(wrapperspb.String(code)))
+			}
+			imported, err := importer.CloseAndReceive()
+			require.NoError(t, err)
+			assert.Equal(t, float64(2), imported.Msg.GetFields()["imported"].GetNumberValue())
+
+			subscription, err := callUnary[structpb.Struct, structpb.Struct](
+				t, server.URL, AdminServiceCreateWebhookSubscriptionProcedure, protocol.opts,
+				newStruct(t, map[string]any{"campaign_id": campaignID, "url": "https://example.com/webhooks"}),
+			)
+			require.NoError(t, err)
+			subscriptionID := subscription.GetFields()["id"].GetStringValue()
+			subscriptions, err := callUnary[wrapperspb.StringValue, structpb.ListValue](
+				t, server.URL, AdminServiceListWebhookSubscriptionsProcedure, protocol.opts, wrapperspb.String(campaignID),
+			)
+			require.NoError(t, err)
+			assert.Len(t, subscriptions.GetValues(), 1)
+			_, err = callUnary[structpb.Struct, structpb.ListValue](
+				t, server.URL, AdminServiceListWebhookDeliveriesProcedure, protocol.opts,
+				newStruct(t, map[string]any{"campaign_id": campaignID}),
+			)
+			require.NoError(t, err)
+
+			// 에러 코드 헤더는 gRPC 에서 트레일러로 전달되므로 프로토콜과 관계없이 같아야 한다
+			_, err = callUnary[wrapperspb.StringValue, emptypb.Empty](
+				t, server.URL, AdminServiceRetryWebhookDeliveryProcedure, protocol.opts, wrapperspb.String(uuid.New().String()),
+			)
+			assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+			var connectErr *connect.Error
+			require.ErrorAs(t, err, &connectErr)
+			assert.Equal(t, "WEBHOOK_DELIVERY_NOT_FOUND", connectErr.Meta().Get(ErrorCodeHeader))
+
+			_, err = callUnary[wrapperspb.StringValue, emptypb.Empty](
+				t, server.URL, AdminServiceDeleteWebhookSubscriptionProcedure, protocol.opts, wrapperspb.String(subscriptionID),
+			)
+			require.NoError(t, err)
+
+			// RedemptionService
+			sharedCode := fmt.Sprintf("PROTOCOL%d", i)
+			sharedRequest := newCampaignRequest("공용 코드 쿠폰")
+			sharedRequest.Header().Set(CodeSourceHeader, string(domain.CodeSourceShared))
+			sharedRequest.Header().Set(SharedCodeHeader, sharedCode)
+			shared, err := greet.CreateCampaign(ctx, sharedRequest)
+			require.NoError(t, err)
+			redeemed, err := callUnary[structpb.Struct, structpb.Struct](
+				t, server.URL, RedemptionServiceRedeemProcedure, protocol.opts,
+				newStruct(t, map[string]any{"code": sharedCode, "user_id": userID}),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, shared.Msg.GetData().GetCampaign().GetId(), redeemed.GetFields()["campaign_id"].GetStringValue())
+
+			// grpc.health.v1
+			health, err := callUnary[healthpb.HealthCheckRequest, healthpb.HealthCheckResponse](
+				t, server.URL, healthCheckProcedure, protocol.opts, &healthpb.HealthCheckRequest{Service: serviceconnect.GreetServiceName},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// RedemptionServiceName 쿠폰 사용 RPC 를 제공하는 서비스
+// 인터페이스 모듈에 정의되지 않은 RPC 이므로 요청과 응답에는 protobuf well-known 타입을 사용한다
+const RedemptionServiceName = "io.coupon.service.RedemptionService"
+
+const RedemptionServiceRedeemProcedure = "/" + RedemptionServiceName + "/Redeem"
+
+// NewRedemptionServiceHTTPHandler serviceconnect 의 생성 코드와 같이 서비스 경로와 핸들러를 반환한다
+// 발급 요청과 같은 클라이언트 정보와 인증 정보를 사용하므로 GreetServiceHandler 의 메서드로 제공한다
+func NewRedemptionServiceHTTPHandler(svc *GreetServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle(RedemptionServiceRedeemProcedure, connect.NewUnaryHandler(RedemptionServiceRedeemProcedure, svc.Redeem, opts...))
+	return "/" + RedemptionServiceName + "/", mux
+}
+
+// Redeem 요청 값은 {"campaign_id": string, "code": string, "user_id": string} 형식이다
+// campaign_id 를 생략하면 code 를 공용 코드로 보고 해당 캠페인의 쿠폰을 발급받으면서 바로 사용 처리한다
+// 응답 값은 {"id", "campaign_id", "code", "status", "redeemed_at", "valid_from", "valid_until"} 형식이다
+func (s *GreetServiceHandler) Redeem(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	fields := req.Msg.GetFields()
+	campaignID := strings.TrimSpace(fields["campaign_id"].GetStringValue())
+	code := strings.TrimSpace(fields["code"].GetStringValue())
+	if code == "" {
+		apiErr := invalidArgument("INVALID_CODE", "code must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	userID, issueOpts := s.issueOptions(ctx, req.Peer(), req.Header(), fields["user_id"].GetStringValue())
+
+	issuedCoupon, err := s.couponService.Redeem(ctx, campaignID, code, userID, issueOpts...)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(issuedCouponValue(issuedCoupon)), nil
+}
+
+func issuedCouponValue(issuedCoupon *domain.IssuedCoupon) *structpb.Struct {
+	fields := map[string]*structpb.Value{
+		"id":          structpb.NewStringValue(issuedCoupon.ID),
+		"campaign_id": structpb.NewStringValue(issuedCoupon.CouponID),
+		"code":        structpb.NewStringValue(issuedCoupon.Code),
+		"status":      structpb.NewStringValue(string(issuedCoupon.Status)),
+		"valid_from":  structpb.NewStringValue(issuedCoupon.ValidFrom.UTC().Format(time.RFC3339)),
+	}
+	if issuedCoupon.RedeemedAt != nil {
+		fields["redeemed_at"] = structpb.NewStringValue(issuedCoupon.RedeemedAt.UTC().Format(time.RFC3339))
+	}
+	if issuedCoupon.ValidUntil != nil {
+		fields["valid_until"] = structpb.NewStringValue(issuedCoupon.ValidUntil.UTC().Format(time.RFC3339))
+	}
+	return &structpb.Struct{Fields: fields}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"coupon-service/internal/application"
+	"coupon-service/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	defaultWebhookDeliveryLimit = 100
+	maxWebhookDeliveryLimit     = 1000
+)
+
+// CreateWebhookSubscription 요청 값은 {"campaign_id": string, "url": string, "secret": string, "event_types": [string]} 형식이다
+// secret 을 생략하면 임의의 서명 키를 만들며, 서명 키는 이 응답에서만 확인할 수 있다
+// event_types 를 생략하면 캠페인의 모든 이벤트를 전달한다
+func (s *AdminServiceHandler) CreateWebhookSubscription(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.Struct], error) {
+	fields := req.Msg.GetFields()
+	campaignID := strings.TrimSpace(fields["campaign_id"].GetStringValue())
+	if campaignID == "" {
+		apiErr := invalidArgument("INVALID_CAMPAIGN_ID", "campaign_id must not be empty")
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	var eventTypes []domain.EventType
+	for _, value := range fields["event_types"].GetListValue().GetValues() {
+		eventTypes = append(eventTypes, domain.EventType(strings.TrimSpace(value.GetStringValue())))
+	}
+
+	subscription, err := s.couponService.CreateWebhookSubscription(
+		ctx,
+		campaignID,
+		strings.TrimSpace(fields["url"].GetStringValue()),
+		fields["secret"].GetStringValue(),
+		eventTypes,
+	)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	value := webhookSubscriptionValue(subscription)
+	value.Fields["secret"] = structpb.NewStringValue(subscription.Secret)
+	return connect.NewResponse(value), nil
+}
+
+// ListWebhookSubscriptions 요청 값은 캠페인 ID 이며, 응답에는 서명 키를 포함하지 않는다
+func (s *AdminServiceHandler) ListWebhookSubscriptions(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[structpb.ListValue], error) {
+	subscriptions, err := s.couponService.ListWebhookSubscriptions(ctx, strings.TrimSpace(req.Msg.GetValue()))
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	values := make([]*structpb.Value, len(subscriptions))
+	for i := range subscriptions {
+		values[i] = structpb.NewStructValue(webhookSubscriptionValue(&subscriptions[i]))
+	}
+	return connect.NewResponse(&structpb.ListValue{Values: values}), nil
+}
+
+// DeleteWebhookSubscription 요청 값은 구독 ID 이며, 아직 전달하지 못한 이벤트는 dead letter 로 옮긴다
+func (s *AdminServiceHandler) DeleteWebhookSubscription(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[emptypb.Empty], error) {
+	if err := s.couponService.DeleteWebhookSubscription(ctx, strings.TrimSpace(req.Msg.GetValue())); err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// ListWebhookDeliveries 요청 값은 {"subscription_id": string, "campaign_id": string, "status": string, "limit": number} 형식이며
+// 모든 필드는 생략할 수 있다. status 는 pending, succeeded, dead 중 하나이고 dead 로 조회하면 dead letter 목록이다
+// 응답 값은 최근에 만든 전달부터 전달 시도 기록(attempts)과 함께 반환한다
+func (s *AdminServiceHandler) ListWebhookDeliveries(
+	ctx context.Context,
+	req *connect.Request[structpb.Struct],
+) (*connect.Response[structpb.ListValue], error) {
+	filter, apiErr := webhookDeliveryFilter(req.Msg)
+	if apiErr != nil {
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	deliveries, err := s.couponService.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	values := make([]*structpb.Value, len(deliveries))
+	for i := range deliveries {
+		values[i] = structpb.NewStructValue(webhookDeliveryValue(&deliveries[i]))
+	}
+	return connect.NewResponse(&structpb.ListValue{Values: values}), nil
+}
+
+// RetryWebhookDelivery 요청 값은 dead letter 로 옮겨진 전달의 ID 이다
+func (s *AdminServiceHandler) RetryWebhookDelivery(
+	ctx context.Context,
+	req *connect.Request[wrapperspb.StringValue],
+) (*connect.Response[emptypb.Empty], error) {
+	if err := s.couponService.RetryWebhookDelivery(ctx, strings.TrimSpace(req.Msg.GetValue())); err != nil {
+		apiErr := toAPIError(err)
+		return nil, apiErr.connectError(apiErr.detail())
+	}
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+func webhookDeliveryFilter(msg *structpb.Struct) (application.WebhookDeliveryFilter, *apiError) {
+	fields := msg.GetFields()
+	filter := application.WebhookDeliveryFilter{
+		SubscriptionID: strings.TrimSpace(fields["subscription_id"].GetStringValue()),
+		CouponID:       strings.TrimSpace(fields["campaign_id"].GetStringValue()),
+		Status:         domain.WebhookDeliveryStatus(strings.TrimSpace(fields["status"].GetStringValue())),
+		Limit:          defaultWebhookDeliveryLimit,
+	}
+	switch filter.Status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryDead:
+	default:
+		apiErr := invalidArgument("INVALID_WEBHOOK_DELIVERY_STATUS", "status must be one of pending, succeeded, dead")
+		return filter, &apiErr
+	}
+	if limit, ok := fields["limit"]; ok {
+		value := limit.GetNumberValue()
+		if value < 1 || value > maxWebhookDeliveryLimit || value != float64(int(value)) {
+			apiErr := invalidArgument("INVALID_LIMIT", "limit must be an integer between 1 and 1000")
+			return filter, &apiErr
+		}
+		filter.Limit = int(value)
+	}
+	return filter, nil
+}
+
+func webhookSubscriptionValue(subscription *domain.WebhookSubscription) *structpb.Struct {
+	eventTypes := make([]string, len(subscription.EventTypes))
+	for i, eventType := range subscription.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+	return &structpb.Struct{Fields: map[string]*structpb.Value{
+		"id":          structpb.NewStringValue(subscription.ID),
+		"campaign_id": structpb.NewStringValue(subscription.CouponID),
+		"url":         structpb.NewStringValue(subscription.URL),
+		"event_types": structpb.NewListValue(stringListValue(eventTypes)),
+		"created_at":  structpb.NewStringValue(subscription.CreatedAt.UTC().Format(time.RFC3339)),
+	}}
+}
+
+func webhookDeliveryValue(delivery *domain.WebhookDelivery) *structpb.Struct {
+	attempts := make([]*structpb.Value, len(delivery.History))
+	for i, attempt := range delivery.History {
+		fields := map[string]*structpb.Value{
+			"attempt":      structpb.NewNumberValue(float64(attempt.Attempt)),
+			"status_code":  structpb.NewNumberValue(float64(attempt.StatusCode)),
+			"duration_ms":  structpb.NewNumberValue(float64(attempt.Duration.Milliseconds())),
+			"attempted_at": structpb.NewStringValue(attempt.AttemptedAt.UTC().Format(time.RFC3339)),
+		}
+		if attempt.Error != "" {
+			fields["error"] = structpb.NewStringValue(attempt.Error)
+		}
+		attempts[i] = structpb.NewStructValue(&structpb.Struct{Fields: fields})
+	}
+
+	fields := map[string]*structpb.Value{
+		"id":              structpb.NewStringValue(delivery.ID),
+		"subscription_id": structpb.NewStringValue(delivery.SubscriptionID),
+		"campaign_id":     structpb.NewStringValue(delivery.CouponID),
+		"event_id":        structpb.NewStringValue(delivery.EventID),
+		"event_type":      structpb.NewStringValue(string(delivery.EventType)),
+		"status":          structpb.NewStringValue(string(delivery.Status)),
+		"created_at":      structpb.NewStringValue(delivery.CreatedAt.UTC().Format(time.RFC3339)),
+		"attempts":        structpb.NewListValue(&structpb.ListValue{Values: attempts}),
+	}
+	if delivery.Status == domain.WebhookDeliveryPending {
+		fields["next_attempt_at"] = structpb.NewStringValue(delivery.NextAttemptAt.UTC().Format(time.RFC3339))
+	}
+	if delivery.DeliveredAt != nil {
+		fields["delivered_at"] = structpb.NewStringValue(delivery.DeliveredAt.UTC().Format(time.RFC3339))
+	}
+	return &structpb.Struct{Fields: fields}
+}
@@ -0,0 +1,259 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// LivenessPath 프로세스가 요청을 처리할 수 있는지만 확인한다. 의존성이 실패해도 재시작하지 않도록 외부 요청을 하지 않는다
+	LivenessPath = "/healthz"
+	// ReadinessPath 의존성을 확인해 트래픽을 받을 수 있는지 확인한다
+	ReadinessPath = "/readyz"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+	// StatusDegraded 실패해도 대체 경로가 있어 준비 상태에 영향을 주지 않는 의존성의 실패
+	StatusDegraded = "degraded"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+	StatusDraining = "draining"
+)
+
+const defaultCheckTimeout = 2 * time.Second
+
+// Check 의존성이 사용 가능하지 않으면 에러를 반환한다. ctx 에는 확인 시간 제한이 설정되어 있다
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// Readiness 의존성별 확인 결과로 트래픽을 받을 수 있는지 판단한다
+// 종료를 시작하면(Drain) 의존성과 관계없이 준비되지 않은 상태로 응답해 로드밸런서가 새 요청을 보내지 않도록 한다
+type Readiness struct {
+	checks   []namedCheck
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+type Option func(*Readiness)
+
+// WithCheck name 으로 응답에 표시할 의존성 확인을 추가한다
+func WithCheck(name string, check Check) Option {
+	return func(r *Readiness) {
+		r.checks = append(r.checks, namedCheck{name: name, check: check})
+	}
+}
+
+// WithOptionalCheck 실패하면 degraded 로 표시하지만 준비 상태는 실패시키지 않는 의존성 확인을 추가한다
+// 의존성이 실패해도 대체 경로로 요청을 처리할 수 있을 때 사용한다
+func WithOptionalCheck(name string, check Check) Option {
+	return func(r *Readiness) {
+		r.checks = append(r.checks, namedCheck{name: name, check: check, optional: true})
+	}
+}
+
+// WithCheckTimeout 의존성 하나를 확인하는 최대 시간. 기본값은 2초이다
+func WithCheckTimeout(timeout time.Duration) Option {
+	return func(r *Readiness) {
+		if timeout > 0 {
+			r.timeout = timeout
+		}
+	}
+}
+
+func NewReadiness(opts ...Option) *Readiness {
+	r := &Readiness{timeout: defaultCheckTimeout}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Drain 종료를 시작하면 호출한다. 이후의 준비 상태 확인은 모두 실패한다
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+// Draining 종료를 시작했는지 여부
+func (r *Readiness) Draining() bool {
+	return r.draining.Load()
+}
+
+// DependencyStatus 의존성 하나의 확인 결과
+type DependencyStatus struct {
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report 준비 상태 확인 결과. degraded 가 아닌 모든 의존성이 up 이고 종료 중이 아니어야 ready 이다
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Check 모든 의존성을 동시에 확인한다. 종료 중이어도 의존성의 상태는 함께 보고한다
+func (r *Readiness) Check(ctx context.Context) Report {
+	report := Report{Status: StatusReady, Dependencies: make(map[string]DependencyStatus, len(r.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := r.run(ctx, c.check)
+			if c.optional && status.Status == StatusDown {
+				status.Status = StatusDegraded
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[c.name] = status
+			if status.Status == StatusDown {
+				report.Status = StatusNotReady
+			}
+		}()
+	}
+	wg.Wait()
+
+	if r.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func (r *Readiness) run(ctx context.Context, check Check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runCheck(ctx, check)
+	status := DependencyStatus{Status: StatusUp, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// runCheck 시간 제한을 지키지 않는 확인도 제한 시간이 지나면 실패로 처리한다
+func runCheck(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
+
+// ServeHTTP 준비되었으면 200, 아니면 503 으로 의존성별 상태를 응답한다
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	report := r.Check(req.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// LivenessHandler 요청을 처리할 수 있으면 항상 200 으로 응답한다
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusUp})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// MySQLCheck 연결 풀에서 연결을 얻어 ping 한다
+func MySQLCheck(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		if db == nil {
+			return errors.New("database client is not configured")
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// RedisCheck 클러스터 모드에서는 연결된 노드 중 하나에 ping 한다
+func RedisCheck(client redis.UniversalClient) Check {
+	return func(ctx context.Context) error {
+		if client == nil {
+			return errors.New("redis client is not configured")
+		}
+		return client.Ping(ctx).Err()
+	}
+}
+
+// MigrationCheck entities 의 테이블과 컬럼이 모두 있는지 확인한다
+// 스키마는 실행 중에 이전 상태로 돌아가지 않으므로 한 번 확인되면 다시 조회하지 않는다
+func MigrationCheck(db *gorm.DB, entities ...any) Check {
+	var migrated atomic.Bool
+	return func(ctx context.Context) error {
+		if migrated.Load() {
+			return nil
+		}
+		// 연결할 수 없을 때 마이그레이션되지 않은 것으로 보고하지 않도록 먼저 확인한다
+		if err := MySQLCheck(db)(ctx); err != nil {
+			return err
+		}
+		if err := pendingMigration(db.WithContext(ctx), entities); err != nil {
+			return err
+		}
+		migrated.Store(true)
+		return nil
+	}
+}
+
+func pendingMigration(db *gorm.DB, entities []any) error {
+	migrator := db.Migrator()
+	for _, entity := range entities {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(entity); err != nil {
+			return err
+		}
+		if !migrator.HasTable(entity) {
+			return fmt.Errorf("table %s has not been migrated", stmt.Schema.Table)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !migrator.HasColumn(entity, field.DBName) {
+				return fmt.Errorf("column %s.%s has not been migrated", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+	return nil
+}
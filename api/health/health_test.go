@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/test"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(context.Context) error {
+	return nil
+}
+
+func serveReadiness(t *testing.T, readiness *Readiness) (int, Report) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	readiness.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var report Report
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	return recorder.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	t.Run("모든 의존성이 정상이면 200 과 의존성별 상태를 응답해야 한다", func(t *testing.T) {
+		readiness := NewReadiness(WithCheck("mysql", up), WithCheck("redis", up))
+
+		code, report := serveReadiness(t, readiness)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusReady, report.Status)
+		assert.Equal(t, StatusUp, report.Dependencies["mysql"].Status)
+		assert.Equal(t, StatusUp, report.Dependencies["redis"].Status)
+	})
+
+	t.Run("의존성 하나가 실패하면 503 과 실패한 의존성의 에러를 응답해야 한다", func(t *testing.T) {
+		readiness := NewReadiness(
+			WithCheck("mysql", up),
+			WithCheck("redis", func(context.Context) error { return errors.New("connection refused") }),
+		)
+
+		code, report := serveReadiness(t, readiness)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusNotReady, report.Status)
+		assert.Equal(t, StatusUp, report.Dependencies["mysql"].Status)
+		assert.Equal(t, StatusDown, report.Dependencies["redis"].Status)
+		assert.Equal(t, "connection refused", report.Dependencies["redis"].Error)
+	})
+
+	t.Run("선택 의존성이 실패하면 degraded 로 표시하고 200 으로 응답해야 한다", func(t *testing.T) {
+		readiness := NewReadiness(
+			WithCheck("mysql", up),
+			WithOptionalCheck("redis", func(context.Context) error { return errors.New("connection refused") }),
+		)
+
+		code, report := serveReadiness(t, readiness)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusReady, report.Status)
+		assert.Equal(t, StatusDegraded, report.Dependencies["redis"].Status)
+		assert.Equal(t, "connection refused", report.Dependencies["redis"].Error)
+	})
+
+	t.Run("제한 시간 안에 끝나지 않는 확인은 실패로 처리해야 한다", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		readiness := NewReadiness(
+			WithCheckTimeout(50*time.Millisecond),
+			WithCheck("mysql", func(context.Context) error {
+				<-block
+				return nil
+			}),
+		)
+
+		start := time.Now()
+		code, report := serveReadiness(t, readiness)
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDown, report.Dependencies["mysql"].Status)
+		assert.Contains(t, report.Dependencies["mysql"].Error, "timed out")
+	})
+
+	t.Run("종료를 시작하면 의존성이 정상이어도 503 으로 응답해야 한다", func(t *testing.T) {
+		readiness := NewReadiness(WithCheck("mysql", up))
+		readiness.Drain()
+
+		code, report := serveReadiness(t, readiness)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDraining, report.Status)
+		assert.Equal(t, StatusUp, report.Dependencies["mysql"].Status)
+	})
+}
+
+func TestLivenessHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"up"}`, recorder.Body.String())
+}
+
+func TestRedisCheck(t *testing.T) {
+	t.Run("연결할 수 없는 Redis 는 실패해야 한다", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+		defer client.Close()
+
+		assert.Error(t, RedisCheck(client)(context.Background()))
+	})
+
+	t.Run("클라이언트가 없으면 실패해야 한다", func(t *testing.T) {
+		assert.Error(t, RedisCheck(nil)(context.Background()))
+	})
+}
+
+func TestChecksWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+
+	t.Run("MySQL 과 Redis 에 연결할 수 있으면 성공해야 한다", func(t *testing.T) {
+		assert.NoError(t, MySQLCheck(mysqlContainer.DB)(ctx))
+		assert.NoError(t, RedisCheck(redisContainer.Client)(ctx))
+	})
+
+	t.Run("마이그레이션되지 않은 테이블이 있으면 실패하고, 마이그레이션 후에는 성공해야 한다", func(t *testing.T) {
+		check := MigrationCheck(mysqlContainer.DB, &entity.CouponEntity{}, &entity.WebhookAttemptEntity{})
+		require.NoError(t, mysqlContainer.MigrateEntities(&entity.CouponEntity{}))
+
+		err := check(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has not been migrated")
+
+		require.NoError(t, mysqlContainer.MigrateEntities(&entity.WebhookAttemptEntity{}))
+		assert.NoError(t, check(ctx))
+	})
+}
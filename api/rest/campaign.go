@@ -0,0 +1,223 @@
+package rest
+
+import (
+	"coupon-service/api/grpc/service"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sujin1135/coupon-service-interface/protobuf/entity"
+	svcpb "github.com/Sujin1135/coupon-service-interface/protobuf/service"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// createCampaignRequest POST /campaigns 의 요청 본문
+// RPC 에서 헤더로 전달하는 설정을 본문의 필드로 받으며, eligibility 와 usage_validity 는 헤더와 같은 JSON 형식이다
+type createCampaignRequest struct {
+	Name          string          `json:"name"`
+	Amount        int64           `json:"amount"`
+	IssuedAt      *time.Time      `json:"issued_at"`
+	ExpiresAt     *time.Time      `json:"expires_at"`
+	StockShards   int             `json:"stock_shards"`
+	CodeSource    string          `json:"code_source"`
+	SharedCode    string          `json:"shared_code"`
+	PerUserLimit  int             `json:"per_user_limit"`
+	Eligibility   json.RawMessage `json:"eligibility"`
+	UsageValidity json.RawMessage `json:"usage_validity"`
+}
+
+type issuedCouponResponse struct {
+	ID         string    `json:"id"`
+	Code       string    `json:"code"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type campaignResponse struct {
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	Amount        int64                  `json:"amount"`
+	IssuedAt      time.Time              `json:"issued_at"`
+	ExpiresAt     time.Time              `json:"expires_at"`
+	IssuedCoupons []issuedCouponResponse `json:"issued_coupons"`
+	CreatedAt     time.Time              `json:"created_at"`
+	ModifiedAt    time.Time              `json:"modified_at"`
+}
+
+// issueResponse POST /campaigns/{id}/issue 의 응답 본문. valid_until 은 기한이 없으면 생략한다
+type issueResponse struct {
+	CampaignID string `json:"campaign_id"`
+	ValidFrom  string `json:"valid_from"`
+	ValidUntil string `json:"valid_until,omitempty"`
+}
+
+type userRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type redeemRequest struct {
+	Code   string `json:"code"`
+	UserID string `json:"user_id"`
+}
+
+func (g *Gateway) createCampaign(w http.ResponseWriter, r *http.Request) {
+	var body createCampaignRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	msg := &svcpb.CreateCampaignRequest{Name: body.Name, Amount: body.Amount}
+	if body.IssuedAt != nil {
+		msg.IssuedAt = timestamppb.New(*body.IssuedAt)
+	}
+	if body.ExpiresAt != nil {
+		msg.ExpiresAt = timestamppb.New(*body.ExpiresAt)
+	}
+	req := newRequest(r, msg)
+	if body.StockShards != 0 {
+		req.Header().Set(service.StockShardsHeader, strconv.Itoa(body.StockShards))
+	}
+	if body.CodeSource != "" {
+		req.Header().Set(service.CodeSourceHeader, body.CodeSource)
+	}
+	if body.SharedCode != "" {
+		req.Header().Set(service.SharedCodeHeader, body.SharedCode)
+	}
+	if body.PerUserLimit != 0 {
+		req.Header().Set(service.PerUserLimitHeader, strconv.Itoa(body.PerUserLimit))
+	}
+	if len(body.Eligibility) > 0 {
+		req.Header().Set(service.EligibilityHeader, string(body.Eligibility))
+	}
+	if len(body.UsageValidity) > 0 {
+		req.Header().Set(service.UsageValidityHeader, string(body.UsageValidity))
+	}
+
+	res, err := g.greet.CreateCampaign(rpcContext(r), req)
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	campaign := toCampaignResponse(res.Msg.GetData().GetCampaign())
+	w.Header().Set("Location", "/campaigns/"+campaign.ID)
+	writeJSON(w, http.StatusCreated, campaign)
+}
+
+func (g *Gateway) getCampaign(w http.ResponseWriter, r *http.Request) {
+	res, err := g.greet.GetCampaign(rpcContext(r), newRequest(r, &svcpb.GetCampaignRequest{Id: r.PathValue("id")}))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toCampaignResponse(res.Msg.GetData().GetCampaign()))
+}
+
+// issueCoupon 요청 본문은 생략할 수 있으며, 일반 사용자 토큰으로 호출하면 user_id 대신 토큰의 subject 로 발급한다
+func (g *Gateway) issueCoupon(w http.ResponseWriter, r *http.Request) {
+	var body userRequest
+	if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+		return
+	}
+	campaignID := r.PathValue("id")
+	res, err := g.greet.IssueCoupon(rpcContext(r), newRequest(r, &svcpb.IssueCouponRequest{
+		CampaignId: campaignID,
+		UserId:     body.UserID,
+	}))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, issueResponse{
+		CampaignID: campaignID,
+		ValidFrom:  res.Header().Get(service.ValidFromHeader),
+		ValidUntil: res.Header().Get(service.ValidUntilHeader),
+	})
+}
+
+func (g *Gateway) redeemCoupon(w http.ResponseWriter, r *http.Request) {
+	var body redeemRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	res, err := g.redeem.CallUnary(rpcContext(r), newRequest(r, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"campaign_id": structpb.NewStringValue(r.PathValue("id")),
+		"code":        structpb.NewStringValue(body.Code),
+		"user_id":     structpb.NewStringValue(body.UserID),
+	}}))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Msg.AsMap())
+}
+
+// getCampaignAvailability ids 는 반복하거나 쉼표로 구분해 전달한다. RPC 와 같이 1초 동안 캐싱할 수 있다
+func (g *Gateway) getCampaignAvailability(w http.ResponseWriter, r *http.Request) {
+	res, err := g.availability.CallUnary(rpcContext(r), newRequest(r, stringList(splitQuery(r.URL.Query()["ids"]))))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	if value := res.Header().Get("Cache-Control"); value != "" {
+		w.Header().Set("Cache-Control", value)
+	}
+	writeJSON(w, http.StatusOK, res.Msg.AsSlice())
+}
+
+func (g *Gateway) getUserClaimStatus(w http.ResponseWriter, r *http.Request) {
+	res, err := g.claimStatus.CallUnary(rpcContext(r), newRequest(r, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"campaign_id": structpb.NewStringValue(r.PathValue("id")),
+		"user_id":     structpb.NewStringValue(r.URL.Query().Get("user_id")),
+	}}))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Msg.AsMap())
+}
+
+func (g *Gateway) getUserClaimStatuses(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	res, err := g.claimStatuses.CallUnary(rpcContext(r), newRequest(r, &structpb.Struct{Fields: map[string]*structpb.Value{
+		"campaign_ids": structpb.NewListValue(stringList(splitQuery(query["campaign_ids"]))),
+		"user_id":      structpb.NewStringValue(query.Get("user_id")),
+	}}))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Msg.AsSlice())
+}
+
+func (g *Gateway) revokeCoupon(w http.ResponseWriter, r *http.Request) {
+	res, err := g.revoke.CallUnary(rpcContext(r), newRequest(r, wrapperspb.String(r.PathValue("id"))))
+	if err != nil {
+		writeRPCError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res.Msg.AsMap())
+}
+
+func toCampaignResponse(campaign *entity.Campaign) campaignResponse {
+	issuedCoupons := make([]issuedCouponResponse, len(campaign.GetIssuedCoupons()))
+	for i, issuedCoupon := range campaign.GetIssuedCoupons() {
+		issuedCoupons[i] = issuedCouponResponse{
+			ID:         issuedCoupon.GetId(),
+			Code:       issuedCoupon.GetCode(),
+			CreatedAt:  issuedCoupon.GetCreatedAt().AsTime(),
+			ModifiedAt: issuedCoupon.GetModifiedAt().AsTime(),
+		}
+	}
+	return campaignResponse{
+		ID:            campaign.GetId(),
+		Name:          campaign.GetName(),
+		Amount:        campaign.GetIssueAmount(),
+		IssuedAt:      campaign.GetIssuedAt().AsTime(),
+		ExpiresAt:     campaign.GetExpiresAt().AsTime(),
+		IssuedCoupons: issuedCoupons,
+		CreatedAt:     campaign.GetCreatedAt().AsTime(),
+		ModifiedAt:    campaign.GetModifiedAt().AsTime(),
+	}
+}
@@ -0,0 +1,300 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"coupon-service/api/grpc/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// inProcessURL 같은 프로세스의 Connect 핸들러를 호출할 때 사용하는 주소. 실제로 네트워크를 사용하지 않는다
+const inProcessURL = "http://in-process"
+
+// maxRequestBodySize REST 요청 본문의 최대 크기
+const maxRequestBodySize = 1 << 20
+
+// Gateway Connect 나 gRPC 를 사용할 수 없는 클라이언트를 위한 리소스 형식의 REST/JSON API
+// 각 요청은 같은 프로세스의 Connect 핸들러를 호출하므로 인증, 요청 제한, 에러 코드가 RPC 와 같다
+type Gateway struct {
+	mux           *http.ServeMux
+	greet         serviceconnect.GreetServiceClient
+	redeem        *connect.Client[structpb.Struct, structpb.Struct]
+	availability  *connect.Client[structpb.ListValue, structpb.ListValue]
+	claimStatus   *connect.Client[structpb.Struct, structpb.Struct]
+	claimStatuses *connect.Client[structpb.Struct, structpb.ListValue]
+	revoke        *connect.Client[wrapperspb.StringValue, structpb.Struct]
+}
+
+// NewGateway rpc 는 Connect 핸들러가 등록된 핸들러이며 CORS 등의 미들웨어를 적용하기 전의 핸들러를 전달한다
+func NewGateway(rpc http.Handler) *Gateway {
+	client := &http.Client{Transport: inProcessTransport{handler: rpc}}
+	g := &Gateway{
+		mux:   http.NewServeMux(),
+		greet: serviceconnect.NewGreetServiceClient(client, inProcessURL),
+		redeem: connect.NewClient[structpb.Struct, structpb.Struct](
+			client, inProcessURL+service.RedemptionServiceRedeemProcedure,
+		),
+		availability: connect.NewClient[structpb.ListValue, structpb.ListValue](
+			client, inProcessURL+service.CampaignServiceGetCampaignAvailabilityProcedure,
+		),
+		claimStatus: connect.NewClient[structpb.Struct, structpb.Struct](
+			client, inProcessURL+service.CampaignServiceGetUserClaimStatusProcedure,
+		),
+		claimStatuses: connect.NewClient[structpb.Struct, structpb.ListValue](
+			client, inProcessURL+service.CampaignServiceGetUserClaimStatusesProcedure,
+		),
+		revoke: connect.NewClient[wrapperspb.StringValue, structpb.Struct](
+			client, inProcessURL+service.AdminServiceRevokeCouponProcedure,
+		),
+	}
+	for pattern, handler := range g.routes() {
+		g.mux.HandleFunc(pattern, handler)
+	}
+	return g
+}
+
+// routes 경로별 핸들러. openapi.json 의 paths 와 같은 경로를 유지한다
+func (g *Gateway) routes() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"POST /campaigns":                  g.createCampaign,
+		"GET /campaigns/availability":      g.getCampaignAvailability,
+		"GET /campaigns/{id}":              g.getCampaign,
+		"POST /campaigns/{id}/issue":       g.issueCoupon,
+		"POST /campaigns/{id}/redeem":      g.redeemCoupon,
+		"GET /campaigns/{id}/claim-status": g.getUserClaimStatus,
+		"GET /claim-statuses":              g.getUserClaimStatuses,
+		"POST /issued-coupons/{id}/revoke": g.revokeCoupon,
+		"GET /openapi.json":                serveOpenAPI,
+	}
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+type remoteAddrKey struct{}
+
+// inProcessTransport 요청을 네트워크 대신 Connect 핸들러로 바로 전달한다
+// 요청 제한과 어뷰징 검사가 REST 클라이언트의 주소를 사용하도록 원래 요청의 주소를 전달한다
+type inProcessTransport struct {
+	handler http.Handler
+}
+
+func (t inProcessTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrip 은 전달받은 요청을 변경하면 안 되므로 복사해서 서버 요청처럼 채운다
+	req = req.WithContext(req.Context())
+	if addr, ok := req.Context().Value(remoteAddrKey{}).(string); ok {
+		req.RemoteAddr = addr
+	}
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+	w := &responseBuffer{header: make(http.Header)}
+	t.handler.ServeHTTP(w, req)
+	return w.response(req), nil
+}
+
+// responseBuffer Connect 핸들러의 응답을 메모리에 모아 http.Response 로 돌려준다
+// 게이트웨이는 단항 RPC 만 호출하므로 응답 전체를 모은 뒤 반환한다
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseBuffer) Header() http.Header {
+	return w.header
+}
+
+func (w *responseBuffer) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseBuffer) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+func (w *responseBuffer) response(req *http.Request) *http.Response {
+	w.WriteHeader(http.StatusOK)
+	return &http.Response{
+		Status:        strconv.Itoa(w.status) + " " + http.StatusText(w.status),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.header,
+		Body:          io.NopCloser(&w.body),
+		ContentLength: int64(w.body.Len()),
+		Request:       req,
+	}
+}
+
+// rpcContext 원래 요청의 주소를 담은 컨텍스트
+func rpcContext(r *http.Request) context.Context {
+	return context.WithValue(r.Context(), remoteAddrKey{}, r.RemoteAddr)
+}
+
+// forwardHeaders 인증 정보와 발급에 사용하는 헤더를 RPC 요청에 전달한다
+// GreetService 는 실패를 응답 메시지 대신 connect 에러로 반환하도록 요청한다
+func forwardHeaders(from http.Header, to http.Header) {
+	for name, values := range from {
+		if name == "Authorization" || name == "X-Forwarded-For" || strings.HasPrefix(name, "Coupon-") {
+			to[name] = values
+		}
+	}
+	to.Set(service.ErrorModeHeader, "connect")
+}
+
+func newRequest[T any](r *http.Request, msg *T) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	forwardHeaders(r.Header, req.Header())
+	return req
+}
+
+// decodeBody 본문이 올바른 JSON 이 아니면 400 으로 응답하고 false 를 반환한다
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, errorBody{
+			Code:    "INVALID_REQUEST_BODY",
+			Message: "request body must be a JSON object: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// errorBody 실패한 요청의 응답 본문. code 는 RPC 의 Coupon-Error-Code 와 같은 애플리케이션 에러 코드이며
+// 애플리케이션 에러가 아니면(인증 실패 등) connect 에러 코드를 사용한다
+type errorBody struct {
+	Code            string           `json:"code"`
+	Message         string           `json:"message"`
+	Retryable       bool             `json:"retryable"`
+	FieldViolations []fieldViolation `json:"field_violations,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, body errorBody) {
+	writeJSON(w, status, body)
+}
+
+// passThroughErrorHeaders RPC 에러에서 REST 응답으로 전달하는 헤더
+var passThroughErrorHeaders = []string{
+	service.ErrorCodeHeader,
+	service.ErrorRetryableHeader,
+	"Retry-After",
+	"WWW-Authenticate",
+}
+
+// writeRPCError RPC 에러를 HTTP 상태 코드와 에러 본문으로 변환한다
+func writeRPCError(w http.ResponseWriter, err error) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		log.Println(err.Error())
+		writeError(w, http.StatusInternalServerError, errorBody{Code: connect.CodeInternal.String(), Message: "internal error occurred"})
+		return
+	}
+
+	for _, name := range passThroughErrorHeaders {
+		if value := connectErr.Meta().Get(name); value != "" {
+			w.Header().Set(name, value)
+		}
+	}
+	body := errorBody{
+		Code:    connectErr.Meta().Get(service.ErrorCodeHeader),
+		Message: connectErr.Message(),
+	}
+	if body.Code == "" {
+		body.Code = connectErr.Code().String()
+	}
+	body.Retryable, _ = strconv.ParseBool(connectErr.Meta().Get(service.ErrorRetryableHeader))
+	for _, detail := range connectErr.Details() {
+		value, detailErr := detail.Value()
+		if detailErr != nil {
+			continue
+		}
+		if badRequest, ok := value.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				body.FieldViolations = append(body.FieldViolations, fieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+	writeError(w, httpStatus(connectErr.Code()), body)
+}
+
+// httpStatus Connect 프로토콜의 에러 코드별 HTTP 상태 코드
+func httpStatus(code connect.Code) int {
+	switch code {
+	case connect.CodeCanceled, connect.CodeDeadlineExceeded:
+		return http.StatusRequestTimeout
+	case connect.CodeInvalidArgument, connect.CodeOutOfRange:
+		return http.StatusBadRequest
+	case connect.CodeNotFound, connect.CodeUnimplemented:
+		return http.StatusNotFound
+	case connect.CodeAlreadyExists, connect.CodeAborted:
+		return http.StatusConflict
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case connect.CodeFailedPrecondition:
+		return http.StatusPreconditionFailed
+	case connect.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case connect.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// splitQuery 반복하거나 쉼표로 구분한 쿼리 값에서 빈 값을 제외한 목록
+func splitQuery(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+func stringList(values []string) *structpb.ListValue {
+	list := &structpb.ListValue{Values: make([]*structpb.Value, len(values))}
+	for i, value := range values {
+		list.Values[i] = structpb.NewStringValue(value)
+	}
+	return list
+}
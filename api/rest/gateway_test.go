@@ -0,0 +1,225 @@
+package rest
+
+import (
+	"context"
+	"coupon-service/api/grpc/interceptor"
+	"coupon-service/api/grpc/service"
+	"coupon-service/internal/infrastructure/auth"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sujin1135/coupon-service-interface/protobuf/entity"
+	svcpb "github.com/Sujin1135/coupon-service-interface/protobuf/service"
+	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
+	"github.com/bufbuild/connect-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// stubGreetService 요청을 기록하고 미리 정한 응답을 반환한다
+type stubGreetService struct {
+	serviceconnect.UnimplementedGreetServiceHandler
+	issueHeader  http.Header
+	issueRequest *svcpb.IssueCouponRequest
+	issuePeer    string
+}
+
+func (s *stubGreetService) GetCampaign(
+	_ context.Context,
+	req *connect.Request[svcpb.GetCampaignRequest],
+) (*connect.Response[svcpb.GetCampaignResponse], error) {
+	if req.Msg.Id != "campaign-1" {
+		connectErr := connect.NewError(connect.CodeNotFound, errors.New("data key not found"))
+		connectErr.Meta().Set(service.ErrorCodeHeader, "COUPON_DATA_NOT_FOUND")
+		connectErr.Meta().Set(service.ErrorRetryableHeader, "false")
+		return nil, connectErr
+	}
+	at := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	return connect.NewResponse(&svcpb.GetCampaignResponse{
+		Value: &svcpb.GetCampaignResponse_Data_{Data: &svcpb.GetCampaignResponse_Data{Campaign: &entity.Campaign{
+			Id:          "campaign-1",
+			Name:        "봄맞이 쿠폰",
+			IssueAmount: 100,
+			IssuedAt:    timestamppb.New(at),
+			ExpiresAt:   timestamppb.New(at.Add(time.Hour)),
+			CreatedAt:   timestamppb.New(at),
+			ModifiedAt:  timestamppb.New(at),
+		}}},
+	}), nil
+}
+
+func (s *stubGreetService) IssueCoupon(
+	_ context.Context,
+	req *connect.Request[svcpb.IssueCouponRequest],
+) (*connect.Response[svcpb.IssueCouponResponse], error) {
+	s.issueHeader = req.Header()
+	s.issueRequest = req.Msg
+	s.issuePeer = req.Peer().Addr
+	res := connect.NewResponse(&svcpb.IssueCouponResponse{
+		Value: &svcpb.IssueCouponResponse_Data_{Data: &svcpb.IssueCouponResponse_Data{Result: true}},
+	})
+	res.Header().Set(service.ValidFromHeader, "2026-04-01T00:00:00Z")
+	return res, nil
+}
+
+type fakeVerifier map[string]*auth.Claims
+
+func (v fakeVerifier) Verify(token string) (*auth.Claims, error) {
+	if claims, ok := v[token]; ok {
+		return claims, nil
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func newGatewayTestServer(t *testing.T, opts ...connect.HandlerOption) (*stubGreetService, string) {
+	greet := &stubGreetService{}
+	mux := http.NewServeMux()
+	mux.Handle(serviceconnect.NewGreetServiceHandler(greet, opts...))
+	mux.Handle(service.NewCampaignServiceHTTPHandler(service.NewCampaignServiceHandler(nil), opts...))
+	mux.Handle("/", NewGateway(mux))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return greet, server.URL
+}
+
+func decodeResponse(t *testing.T, res *http.Response, v any) {
+	t.Helper()
+	defer res.Body.Close()
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(res.Body).Decode(v))
+}
+
+func TestGateway(t *testing.T) {
+	greet, baseURL := newGatewayTestServer(t)
+
+	t.Run("캠페인을 조회하면 REST 형식의 캠페인을 반환해야 한다", func(t *testing.T) {
+		res, err := http.Get(baseURL + "/campaigns/campaign-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		var campaign campaignResponse
+		decodeResponse(t, res, &campaign)
+		assert.Equal(t, "campaign-1", campaign.ID)
+		assert.Equal(t, "봄맞이 쿠폰", campaign.Name)
+		assert.Equal(t, int64(100), campaign.Amount)
+		assert.Equal(t, time.Date(2026, 4, 1, 1, 0, 0, 0, time.UTC), campaign.ExpiresAt)
+		assert.Empty(t, campaign.IssuedCoupons)
+	})
+
+	t.Run("RPC 에러는 HTTP 상태 코드와 애플리케이션 에러 코드로 변환되어야 한다", func(t *testing.T) {
+		res, err := http.Get(baseURL + "/campaigns/unknown")
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, "COUPON_DATA_NOT_FOUND", res.Header.Get(service.ErrorCodeHeader))
+		var body errorBody
+		decodeResponse(t, res, &body)
+		assert.Equal(t, errorBody{Code: "COUPON_DATA_NOT_FOUND", Message: "data key not found"}, body)
+	})
+
+	t.Run("발급 요청은 경로의 캠페인 ID 와 발급에 사용하는 헤더, 클라이언트 주소를 RPC 에 전달해야 한다", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, baseURL+"/campaigns/campaign-1/issue", strings.NewReader(`{"user_id":"user-1"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(service.DeviceIDHeader, "device-1")
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		var body issueResponse
+		decodeResponse(t, res, &body)
+		assert.Equal(t, issueResponse{CampaignID: "campaign-1", ValidFrom: "2026-04-01T00:00:00Z"}, body)
+		assert.Equal(t, "campaign-1", greet.issueRequest.CampaignId)
+		assert.Equal(t, "user-1", greet.issueRequest.UserId)
+		assert.Equal(t, "device-1", greet.issueHeader.Get(service.DeviceIDHeader))
+		assert.Equal(t, "connect", greet.issueHeader.Get(service.ErrorModeHeader))
+		assert.True(t, strings.HasPrefix(greet.issuePeer, "127.0.0.1:"))
+	})
+
+	t.Run("본문이 올바른 JSON 이 아니면 INVALID_REQUEST_BODY 로 거부되어야 한다", func(t *testing.T) {
+		res, err := http.Post(baseURL+"/campaigns", "application/json", strings.NewReader("{"))
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		var body errorBody
+		decodeResponse(t, res, &body)
+		assert.Equal(t, "INVALID_REQUEST_BODY", body.Code)
+	})
+
+	t.Run("RPC 의 요청 검증도 같은 에러 코드로 거부되어야 한다", func(t *testing.T) {
+		res, err := http.Get(baseURL + "/campaigns/availability")
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		var body errorBody
+		decodeResponse(t, res, &body)
+		assert.Equal(t, "INVALID_CAMPAIGN_IDS", body.Code)
+	})
+}
+
+func TestGatewayAuthentication(t *testing.T) {
+	verifier := fakeVerifier{"user": {Subject: "user-1"}}
+	_, baseURL := newGatewayTestServer(t, connect.WithInterceptors(interceptor.NewAuthInterceptor(
+		verifier, "coupon:admin", serviceconnect.GreetServiceIssueCouponProcedure,
+	)))
+
+	t.Run("토큰이 없으면 401 과 WWW-Authenticate 헤더로 응답해야 한다", func(t *testing.T) {
+		res, err := http.Post(baseURL+"/campaigns/campaign-1/issue", "application/json", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Equal(t, "Bearer", res.Header.Get("WWW-Authenticate"))
+		var body errorBody
+		decodeResponse(t, res, &body)
+		assert.Equal(t, connect.CodeUnauthenticated.String(), body.Code)
+	})
+
+	t.Run("일반 사용자 토큰으로 관리자 API 를 호출하면 403 으로 응답해야 한다", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, baseURL+"/campaigns/campaign-1", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer user")
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		var body errorBody
+		decodeResponse(t, res, &body)
+		assert.Equal(t, connect.CodePermissionDenied.String(), body.Code)
+	})
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	_, baseURL := newGatewayTestServer(t)
+	res, err := http.Get(baseURL + "/openapi.json")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var document struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	decodeResponse(t, res, &document)
+	assert.True(t, strings.HasPrefix(document.OpenAPI, "3."))
+
+	routes := (&Gateway{}).routes()
+	for pattern := range routes {
+		t.Run(pattern+" 경로가 문서에 있어야 한다", func(t *testing.T) {
+			method, path, _ := strings.Cut(pattern, " ")
+			require.Contains(t, document.Paths, path)
+			assert.Contains(t, document.Paths[path], strings.ToLower(method))
+		})
+	}
+	operations := 0
+	for _, methods := range document.Paths {
+		operations += len(methods)
+	}
+	assert.Equal(t, len(routes), operations, "문서에만 있는 경로가 없어야 한다")
+}
@@ -0,0 +1,16 @@
+package rest
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPIDocument REST API 의 OpenAPI 3 문서. 경로와 스키마가 routes, 요청/응답 타입과 같은지 테스트에서 확인한다
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPIDocument)
+}
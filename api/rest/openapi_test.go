@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPISchema 검증에 필요한 JSON Schema 의 일부
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Format     string                    `json:"format"`
+	Required   []string                  `json:"required"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	Items      *openAPISchema            `json:"items"`
+}
+
+type openAPIOperation struct {
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema openAPISchema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Ref     string `json:"$ref"`
+		Content map[string]struct {
+			Schema openAPISchema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+type openAPISpec struct {
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema openAPISchema `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// gatewayBodies 요청/응답 본문을 Go 타입으로 다루는 경로의 본문 타입. 성공 응답의 상태 코드와 함께 기록한다
+// structpb 로 응답하는 경로는 service 패키지의 테스트에서 검증한다
+var gatewayBodies = map[string]struct {
+	request  any
+	status   string
+	response any
+}{
+	"POST /campaigns":             {request: createCampaignRequest{}, status: "201", response: campaignResponse{}},
+	"GET /campaigns/{id}":         {status: "200", response: campaignResponse{}},
+	"POST /campaigns/{id}/issue":  {request: userRequest{}, status: "201", response: issueResponse{}},
+	"POST /campaigns/{id}/redeem": {request: redeemRequest{}},
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	var spec openAPISpec
+	require.NoError(t, json.Unmarshal(openAPIDocument, &spec))
+
+	for pattern, bodies := range gatewayBodies {
+		method, path, _ := strings.Cut(pattern, " ")
+		operation, ok := spec.Paths[path][strings.ToLower(method)]
+		require.True(t, ok, pattern)
+
+		if bodies.request != nil {
+			t.Run(pattern+" 요청 본문이 Go 타입과 같아야 한다", func(t *testing.T) {
+				require.NotNil(t, operation.RequestBody)
+				schema := operation.RequestBody.Content["application/json"].Schema
+				assertSchemaMatchesType(t, spec, &schema, reflect.TypeOf(bodies.request), false)
+			})
+		}
+		if bodies.response != nil {
+			t.Run(pattern+" 성공 응답 본문이 Go 타입과 같아야 한다", func(t *testing.T) {
+				schema := operation.Responses[bodies.status].Content["application/json"].Schema
+				assertSchemaMatchesType(t, spec, &schema, reflect.TypeOf(bodies.response), true)
+			})
+		}
+	}
+
+	t.Run("에러 응답 본문이 errorBody 와 같아야 한다", func(t *testing.T) {
+		schema := spec.Components.Responses["Error"].Content["application/json"].Schema
+		assertSchemaMatchesType(t, spec, &schema, reflect.TypeOf(errorBody{}), true)
+
+		for path, methods := range spec.Paths {
+			for method, operation := range methods {
+				for status, response := range operation.Responses {
+					if strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5") {
+						assert.Equal(t, "#/components/responses/Error", response.Ref, method+" "+path+" "+status)
+					}
+				}
+			}
+		}
+	})
+}
+
+// assertSchemaMatchesType 스키마의 속성과 형식이 typ 의 json 태그와 같은지 확인한다
+// 응답 타입은 omitempty 가 아닌 필드가 모두 required 여야 한다
+func assertSchemaMatchesType(t *testing.T, spec openAPISpec, schema *openAPISchema, typ reflect.Type, response bool) {
+	t.Helper()
+	if schema.Ref != "" {
+		resolved, ok := spec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		require.True(t, ok, schema.Ref)
+		schema = resolved
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == reflect.TypeOf(time.Time{}):
+		assert.Equal(t, "string", schema.Type)
+		assert.Equal(t, "date-time", schema.Format)
+	case typ == reflect.TypeOf(json.RawMessage{}):
+		// 그대로 RPC 헤더로 전달하는 JSON 이므로 객체인지만 확인한다
+		assert.Equal(t, "object", schema.Type)
+	case typ.Kind() == reflect.String:
+		assert.Equal(t, "string", schema.Type)
+	case typ.Kind() == reflect.Bool:
+		assert.Equal(t, "boolean", schema.Type)
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		assert.Equal(t, "integer", schema.Type)
+	case typ.Kind() == reflect.Slice:
+		assert.Equal(t, "array", schema.Type)
+		require.NotNil(t, schema.Items)
+		assertSchemaMatchesType(t, spec, schema.Items, typ.Elem(), response)
+	case typ.Kind() == reflect.Struct:
+		assert.Equal(t, "object", schema.Type)
+		var names, required []string
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			names = append(names, name)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+			property, ok := schema.Properties[name]
+			if assert.True(t, ok, "%s 속성이 스키마에 없다", name) {
+				assertSchemaMatchesType(t, spec, property, field.Type, response)
+			}
+		}
+		properties := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			properties = append(properties, name)
+		}
+		assert.ElementsMatch(t, names, properties, "%s 의 필드와 스키마 속성이 같아야 한다", typ.Name())
+		if response {
+			assert.ElementsMatch(t, required, schema.Required, "%s 의 required 가 필드와 같아야 한다", typ.Name())
+		} else {
+			assert.Subset(t, names, schema.Required)
+		}
+	default:
+		t.Fatalf("%s 타입의 스키마를 확인할 수 없다", typ)
+	}
+}
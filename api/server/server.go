@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Server h2c 로 HTTP/1.1 과 HTTP/2(gRPC) 요청을 함께 처리하고, 종료를 시작하면 처리 중인 요청을 기다린 뒤 반환한다
+// h2c 연결은 hijack 되어 http.Server.Shutdown 이 기다리지 않으므로 처리 중인 요청을 직접 센다
+type Server struct {
+	httpServer      *http.Server
+	requests        requestTracker
+	cancelRequests  context.CancelFunc
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	drainHooks      []func()
+}
+
+type Option func(*Server)
+
+// WithShutdownTimeout 종료를 시작한 뒤 처리 중인 요청을 기다리는 최대 시간. 기본값은 30초이다
+// 시간이 지나면 남은 요청의 context 를 취소하고 연결을 닫는다
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		if timeout > 0 {
+			s.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithDrainDelay 종료를 시작한 뒤 새 연결을 받지 않기 전까지 기다리는 시간. 기본값은 0 이다
+// 로드밸런서가 준비 상태 확인 실패를 보고 대상에서 제외하기까지의 시간으로 설정한다. 종료 제한 시간은 대기 후부터 센다
+func WithDrainDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.drainDelay = delay
+	}
+}
+
+// WithDrainHook 종료를 시작하면 처리 중인 요청을 기다리기 전에 등록한 순서대로 호출한다
+// 준비 상태를 실패로 바꾸거나, 클라이언트가 끊을 때까지 끝나지 않는 스트림을 끝낼 때 사용한다
+func WithDrainHook(hook func()) Option {
+	return func(s *Server) {
+		s.drainHooks = append(s.drainHooks, hook)
+	}
+}
+
+func New(addr string, handler http.Handler, opts ...Option) (*Server, error) {
+	baseCtx, cancel := context.WithCancel(context.Background())
+	s := &Server{cancelRequests: cancel, shutdownTimeout: defaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	h2s := &http2.Server{}
+	s.httpServer = &http.Server{
+		Addr:    addr,
+		Handler: h2c.NewHandler(s.track(handler), h2s),
+		// h2c 연결의 요청도 이 context 에서 파생되므로 취소하면 남은 요청이 모두 취소된다
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	// Shutdown 에서 h2c 연결에도 GOAWAY 를 보내 새 스트림을 받지 않도록 한다
+	if err := http2.ConfigureServer(s.httpServer, h2s); err != nil {
+		cancel()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.begin()
+		defer s.requests.end()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve ctx 가 끝나면 종료를 시작한다. 처리 중인 요청이 모두 끝나면 nil 을,
+// 종료 제한 시간 안에 끝나지 않아 남은 요청을 취소했으면 에러를 반환한다
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- s.httpServer.Serve(listener)
+	}()
+	select {
+	case err := <-served:
+		s.cancelRequests()
+		return err
+	case <-ctx.Done():
+	}
+	return s.shutdown()
+}
+
+func (s *Server) shutdown() error {
+	defer s.cancelRequests()
+	for _, hook := range s.drainHooks {
+		hook()
+	}
+	if s.drainDelay > 0 {
+		time.Sleep(s.drainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	// 리스너를 닫고 HTTP/1.1 연결의 요청을 기다린 뒤, h2c 연결의 요청을 기다린다
+	err := s.httpServer.Shutdown(ctx)
+	if err == nil {
+		err = s.requests.wait(ctx)
+	}
+	if err != nil {
+		s.cancelRequests()
+		_ = s.httpServer.Close()
+		return fmt.Errorf("failed to drain in-flight requests: %w", err)
+	}
+	return nil
+}
+
+// requestTracker 처리 중인 요청 수. 요청이 모두 끝나면 idle 을 닫는다
+type requestTracker struct {
+	mu     sync.Mutex
+	active int
+	idle   chan struct{}
+}
+
+func (t *requestTracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active++
+}
+
+func (t *requestTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+func (t *requestTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.active == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return fmt.Errorf("%d requests are still in flight: %w", t.active, ctx.Err())
+	}
+}
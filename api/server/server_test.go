@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+func newH2CClient() *http.Client {
+	return &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+// startServer 서버를 실행하고 종료를 시작하는 함수와 Serve 의 결과를 받을 채널을 반환한다
+func startServer(t *testing.T, handler http.Handler, opts ...Option) (string, context.CancelFunc, <-chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server, err := New(listener.Addr().String(), handler, opts...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(ctx, listener)
+	}()
+	return "http://" + listener.Addr().String(), cancel, served
+}
+
+func TestServer(t *testing.T) {
+	clients := []struct {
+		name   string
+		client *http.Client
+	}{
+		{name: "HTTP/1.1", client: &http.Client{}},
+		{name: "h2c", client: newH2CClient()},
+	}
+
+	for _, c := range clients {
+		t.Run(c.name+" 요청은 종료를 시작해도 끝날 때까지 기다려야 한다", func(t *testing.T) {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			var drained atomic.Bool
+			url, shutdown, served := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-release
+				_, _ = io.WriteString(w, "issued")
+			}), WithDrainHook(func() { drained.Store(true) }))
+
+			responses := make(chan *http.Response, 1)
+			go func() {
+				res, err := c.client.Get(url)
+				assert.NoError(t, err)
+				responses <- res
+			}()
+			<-started
+			shutdown()
+
+			select {
+			case <-served:
+				t.Fatal("server returned before the request finished")
+			case <-time.After(100 * time.Millisecond):
+			}
+			assert.True(t, drained.Load())
+
+			close(release)
+			res := <-responses
+			require.NotNil(t, res)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "issued", string(body))
+			assert.NoError(t, <-served)
+
+			_, err = (&http.Client{}).Get(url)
+			assert.Error(t, err)
+		})
+
+		t.Run(c.name+" 요청이 종료 제한 시간 안에 끝나지 않으면 취소하고 에러를 반환해야 한다", func(t *testing.T) {
+			started := make(chan struct{})
+			cancelled := make(chan struct{})
+			url, shutdown, served := startServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-r.Context().Done()
+				close(cancelled)
+			}), WithShutdownTimeout(100*time.Millisecond))
+
+			go func() {
+				res, err := c.client.Get(url)
+				if err == nil {
+					_ = res.Body.Close()
+				}
+			}()
+			<-started
+			shutdown()
+
+			select {
+			case err := <-served:
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			case <-time.After(5 * time.Second):
+				t.Fatal("server did not return after the shutdown timeout")
+			}
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Fatal("request context was not cancelled")
+			}
+		})
+	}
+
+	t.Run("종료 훅은 등록한 순서대로 대기 전에 호출되어야 한다", func(t *testing.T) {
+		var calls []string
+		_, shutdown, served := startServer(t, http.NotFoundHandler(),
+			WithDrainHook(func() { calls = append(calls, "readiness") }),
+			WithDrainHook(func() { calls = append(calls, "watch") }),
+			WithDrainDelay(50*time.Millisecond),
+		)
+
+		start := time.Now()
+		shutdown()
+		require.NoError(t, <-served)
+
+		assert.Equal(t, []string{"readiness", "watch"}, calls)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+}
@@ -1,19 +1,31 @@
 package main
 
 import (
+	"context"
 	"coupon-service/internal/config"
 	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/ratelimit"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/bufbuild/connect-go"
+	"github.com/bufbuild/connect-grpchealth-go"
+	"github.com/bufbuild/connect-grpcreflect-go"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/net/http2"
-	"golang.org/x/net/http2/h2c"
 	"gorm.io/gorm"
 
+	"coupon-service/api/grpc/interceptor"
 	"coupon-service/api/grpc/service"
+	"coupon-service/api/health"
+	"coupon-service/api/rest"
+	"coupon-service/api/server"
 	"coupon-service/internal/application"
 	"coupon-service/internal/infrastructure/repository"
 	"github.com/Sujin1135/coupon-service-interface/protobuf/service/serviceconnect"
@@ -29,69 +41,370 @@ func main() {
 		log.Fatalf("failed to migrate this project's database: %v", err)
 	}
 
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     os.Getenv("REDIS_ADDR"),
-		Password: os.Getenv("REDIS_PASSWORD"),
-		DB:       0,
-	})
-
 	couponRepo := repository.NewCouponRepository(config.DBClient)
 	issuedCouponRepo := repository.NewIssuedCouponRepository(config.DBClient)
 
+	strategy := application.IssuanceStrategyType(config.IssuanceStrategy())
+	serviceOpts := []application.Option{
+		application.WithCacheCircuitBreaker(config.CacheCircuitBreaker()),
+		application.WithIssuanceStrategy(strategy),
+	}
+	if config.IssuanceDBFallback() {
+		serviceOpts = append(serviceOpts, application.WithDBFallback())
+	}
+	verifier, err := config.AuthVerifier()
+	if err != nil {
+		log.Fatalf("failed to load auth config: %v", err)
+	}
+	// 가입 일시는 토큰의 클레임으로만 받으므로 인증을 사용할 때만 신규 사용자 조건을 허용한다
+	if verifier != nil {
+		serviceOpts = append(serviceOpts, application.WithAccountClaims())
+	}
+	maxUsersPerIP, maxUsersPerDevice := config.AbuseMaxUsers()
+	newAccountAge, rushWindow := config.AbuseNewAccountRush()
+	serviceOpts = append(serviceOpts, application.WithAbuseCheckConfig(application.AbuseCheckConfig{
+		MaxUsersPerIP:     maxUsersPerIP,
+		MaxUsersPerDevice: maxUsersPerDevice,
+		NewAccountAge:     newAccountAge,
+		RushWindow:        rushWindow,
+	}))
+	sweepInterval, sweepBatchSize, expiryNoticeBefore := config.ExpirySweep()
+	serviceOpts = append(serviceOpts, application.WithExpirySweepConfig(application.ExpirySweepConfig{
+		Interval:     sweepInterval,
+		BatchSize:    sweepBatchSize,
+		NoticeBefore: expiryNoticeBefore,
+	}))
+
+	watchMaxUpdates, watchHeartbeat := config.CampaignWatch()
+	serviceOpts = append(serviceOpts, application.WithWatchConfig(application.WatchConfig{
+		MaxUpdatesPerSecond: watchMaxUpdates,
+		Heartbeat:           watchHeartbeat,
+	}))
+
+	// MySQL 전략은 Redis 없이 동작한다
+	var cacheClient redis.UniversalClient = config.CacheClient
+	if strategy == application.MySQLIssuance {
+		cacheClient = nil
+	}
+
+	eventPublisher, err := config.EventPublisher()
+	if err != nil {
+		log.Fatalf("failed to load event publisher config: %v", err)
+	}
+	if eventPublisher != nil {
+		serviceOpts = append(serviceOpts, application.WithEventPublisher(eventPublisher))
+	}
+
+	webhooksEnabled, webhookInterval, webhookBatchSize, webhookConcurrency, webhookTimeout := config.Webhooks()
+	if webhooksEnabled {
+		webhookMaxAttempts, webhookBackoffBase, webhookBackoffMax := config.WebhookRetry()
+		serviceOpts = append(serviceOpts, application.WithWebhooks(
+			repository.NewWebhookRepository(config.DBClient),
+			application.WebhookConfig{
+				Interval:    webhookInterval,
+				BatchSize:   webhookBatchSize,
+				Concurrency: webhookConcurrency,
+				MaxAttempts: webhookMaxAttempts,
+				BackoffBase: webhookBackoffBase,
+				BackoffMax:  webhookBackoffMax,
+				Timeout:     webhookTimeout,
+			},
+		))
+	}
+
+	// 이벤트는 발행 대기열에 저장하고 RunEventRelay 가 발행한다
+	if eventPublisher != nil || webhooksEnabled {
+		serviceOpts = append(serviceOpts, application.WithEventOutbox(repository.NewEventOutboxRepository(config.DBClient)))
+	}
+
 	couponService := application.NewCouponService(
-		redisClient,
+		cacheClient,
 		couponRepo,
 		issuedCouponRepo,
+		serviceOpts...,
 	)
 
-	grpcService := service.NewGreetServiceHandler(couponService)
+	// 해시 태그를 사용하기 전의 캠페인 키가 남아 있으면 요청을 받기 전에 옮긴다
+	if moved, err := couponService.MigrateLegacyKeys(context.Background()); err != nil {
+		log.Fatalf("failed to migrate legacy campaign keys: %v", err)
+	} else if moved > 0 {
+		log.Printf("migrated %d legacy campaign keys", moved)
+	}
 
-	prefix, connectHandler := serviceconnect.NewGreetServiceHandler(grpcService)
+	// 백그라운드 작업은 서버가 처리 중인 요청을 모두 기다린 뒤 중지한다
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	for _, run := range []func(context.Context){
+		couponService.ListenCouponInvalidation,
+		couponService.ListenStockChanges,
+		couponService.RunExpirySweeper,
+		couponService.RunCampaignOpener,
+		couponService.RunEventRelay,
+		couponService.RunWebhookDispatcher,
+		couponService.RunDegradedIssuanceSync,
+	} {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run(backgroundCtx)
+		}()
+	}
+
+	var handlerOpts []service.HandlerOption
+	if config.ConnectErrorCodes() {
+		handlerOpts = append(handlerOpts, service.WithConnectErrors())
+	}
+	if config.TrustForwardedFor() {
+		handlerOpts = append(handlerOpts, service.WithTrustForwardedFor())
+	}
+	grpcService := service.NewGreetServiceHandler(couponService, handlerOpts...)
+
+	var connectOpts []connect.HandlerOption
+	if verifier != nil {
+		connectOpts = append(connectOpts, connect.WithInterceptors(interceptor.NewAuthInterceptor(
+			verifier,
+			config.AuthAdminScope(),
+			service.UserProcedures()...,
+		)))
+	} else {
+		log.Println("AUTH_JWT_SECRET, AUTH_JWKS_FILE 이 설정되지 않아 인증 없이 모든 RPC 를 허용합니다.")
+	}
+
+	rateLimitRules, err := config.RateLimitRules()
+	if err != nil {
+		log.Fatalf("failed to load rate limit config: %v", err)
+	}
+	if len(rateLimitRules) > 0 {
+		// 인증 이후에 실행되어야 토큰의 사용자 ID 를 기준으로 한도를 적용할 수 있다
+		connectOpts = append(connectOpts, connect.WithInterceptors(interceptor.NewRateLimitInterceptor(
+			ratelimit.NewRedisLimiter(config.CacheClient),
+			rateLimitRules,
+			config.TrustForwardedFor(),
+		)))
+	}
+
+	prefix, connectHandler := serviceconnect.NewGreetServiceHandler(grpcService, connectOpts...)
+
+	adminPrefix, adminHandler := service.NewAdminServiceHTTPHandler(
+		service.NewAdminServiceHandler(couponService),
+		connectOpts...,
+	)
+	redemptionPrefix, redemptionHandler := service.NewRedemptionServiceHTTPHandler(grpcService, connectOpts...)
+	campaignPrefix, campaignHandler := service.NewCampaignServiceHTTPHandler(
+		service.NewCampaignServiceHandler(couponService),
+		connectOpts...,
+	)
+
+	// 헬스 체크와 리플렉션은 프로브와 grpcurl 이 토큰 없이 호출하므로 인터셉터를 적용하지 않는다
+	healthChecker := service.NewHealthChecker(service.ServiceNames()...)
+	healthPrefix, healthHandler := grpchealth.NewHandler(healthChecker)
+	reflector := grpcreflect.NewStaticReflector(service.ReflectionServiceNames()...)
+
+	readinessOpts := []health.Option{
+		health.WithCheckTimeout(config.ReadinessCheckTimeout()),
+		health.WithCheck("mysql", health.MySQLCheck(config.DBClient)),
+		health.WithCheck("migrations", health.MigrationCheck(config.DBClient, migratedEntities...)),
+	}
+	// MySQL 전략이어도 요청 한도는 Redis 를 사용한다
+	// DB 대체 발급을 사용하거나 Redis 를 요청 한도에만 사용하면 Redis 장애 중에도 요청을 처리하므로 준비 상태를 실패시키지 않는다
+	if cacheClient != nil && !config.IssuanceDBFallback() {
+		readinessOpts = append(readinessOpts, health.WithCheck("redis", health.RedisCheck(config.CacheClient)))
+	} else if cacheClient != nil || len(rateLimitRules) > 0 {
+		readinessOpts = append(readinessOpts, health.WithOptionalCheck("redis", health.RedisCheck(config.CacheClient)))
+	}
+	readiness := health.NewReadiness(readinessOpts...)
 
 	mux := http.NewServeMux()
 
+	mux.Handle(health.LivenessPath, health.LivenessHandler())
+	mux.Handle(health.ReadinessPath, readiness)
 	mux.Handle(prefix, connectHandler)
+	mux.Handle(adminPrefix, adminHandler)
+	mux.Handle(redemptionPrefix, redemptionHandler)
+	mux.Handle(campaignPrefix, campaignHandler)
+	mux.Handle(healthPrefix, healthHandler)
+	mux.Handle(grpcreflect.NewHandlerV1(reflector))
+	mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector))
+	// REST 요청은 같은 mux 의 Connect 핸들러로 전달되므로 인증과 요청 제한이 RPC 와 같다
+	mux.Handle("/", rest.NewGateway(mux))
 
 	wrappedHandler := addMiddleware(mux)
 
 	addr := fmt.Sprintf(":%s", port)
+	shutdownTimeout, drainDelay := config.Shutdown()
+	srv, err := server.New(addr, wrappedHandler,
+		server.WithShutdownTimeout(shutdownTimeout),
+		server.WithDrainDelay(drainDelay),
+		// 로드밸런서와 프로브가 먼저 제외하도록 준비 상태를 실패로 바꾸고, 클라이언트가 끊을 때까지 끝나지 않는 스트림을 끝낸다
+		server.WithDrainHook(readiness.Drain),
+		server.WithDrainHook(healthChecker.Shutdown),
+		server.WithDrainHook(couponService.StopWatches),
+	)
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
 	log.Printf("Starting ConnectRPC server on %s", addr)
-	log.Printf("Service available at: %s", prefix)
+	log.Printf("Service available at: %s (Connect, gRPC, gRPC-Web)", prefix)
+	log.Printf("gRPC health and reflection available at: %s", healthPrefix)
+	log.Printf("Liveness and readiness available at: %s, %s", health.LivenessPath, health.ReadinessPath)
+	log.Printf("REST API available at: /campaigns (OpenAPI: /openapi.json)")
 
-	if err := http.ListenAndServe(
-		addr,
-		h2c.NewHandler(wrappedHandler, &http2.Server{}),
-	); err != nil {
-		log.Fatalf("Server error: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err = srv.ListenAndServe(ctx); err != nil {
+		if ctx.Err() == nil {
+			log.Fatalf("Server error: %v", err)
+		}
+		log.Printf("Server shutdown error: %v", err)
+	}
+	log.Println("Server stopped accepting requests, shutting down")
+
+	stopBackground()
+	if !waitTimeout(&background, shutdownTimeout) {
+		log.Printf("background workers did not stop within %s", shutdownTimeout)
+	}
+	// Redis 에서 재고를 차감한 발급이 DB 에 저장되거나 원복된 뒤에 연결을 닫는다
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err = couponService.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to drain issuances: %v", err)
+	}
+	// 릴레이가 멈춘 뒤에 Kafka 프로듀서처럼 연결을 가진 발행자를 닫는다
+	if closer, ok := eventPublisher.(io.Closer); ok {
+		if err = closer.Close(); err != nil {
+			log.Printf("failed to close event publisher: %v", err)
+		}
+	}
+	if err = config.CacheClient.Close(); err != nil {
+		log.Printf("failed to close redis client: %v", err)
 	}
+	if sqlDB, err2 := config.DBClient.DB(); err2 == nil {
+		if err = sqlDB.Close(); err != nil {
+			log.Printf("failed to close database: %v", err)
+		}
+	}
+	log.Println("Server stopped")
+}
+
+// waitTimeout wg 가 timeout 안에 끝나면 true 를 반환한다
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// migratedEntities 자동 마이그레이션 대상. 준비 상태 확인에서도 이 목록의 테이블과 컬럼이 있는지 확인한다
+var migratedEntities = []any{
+	&entity.CouponEntity{},
+	&entity.IssuedCouponEntity{},
+	&entity.DegradedIssuanceEntity{},
+	&entity.CouponCodeEntity{},
+	&entity.WebhookSubscriptionEntity{},
+	&entity.WebhookDeliveryEntity{},
+	&entity.WebhookAttemptEntity{},
+	&entity.EventOutboxEntity{},
 }
 
 func autoMigrate(db *gorm.DB) error {
 	couponTableExists := db.Migrator().HasTable(&entity.CouponEntity{})
 	issuedCouponTableExists := db.Migrator().HasTable(&entity.IssuedCouponEntity{})
 
+	var remainingExists bool
 	if couponTableExists && issuedCouponTableExists {
-		log.Println("데이터베이스 테이블이 이미 존재합니다. 마이그레이션을 건너뜁니다.")
-		return nil
+		log.Println("데이터베이스 테이블이 이미 존재합니다. 추가된 컬럼과 인덱스만 반영합니다.")
+		remainingExists = db.Migrator().HasColumn(&entity.CouponEntity{}, "Remaining")
+		if err := prepareCouponUserIndex(db); err != nil {
+			return fmt.Errorf("자동 마이그레이션 실패: %w", err)
+		}
+	} else {
+		log.Println("데이터베이스 마이그레이션을 실행합니다...")
+		remainingExists = true
 	}
 
-	log.Println("데이터베이스 마이그레이션을 실행합니다...")
-
-	if err := db.AutoMigrate(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}); err != nil {
+	if err := db.AutoMigrate(migratedEntities...); err != nil {
+		return fmt.Errorf("자동 마이그레이션 실패: %w", err)
+	}
+	if err := dropLegacyCouponUserIndex(db); err != nil {
 		return fmt.Errorf("자동 마이그레이션 실패: %w", err)
 	}
+	if !remainingExists {
+		if err := backfillCouponRemaining(db); err != nil {
+			return fmt.Errorf("자동 마이그레이션 실패: %w", err)
+		}
+	}
 
 	log.Println("데이터베이스 마이그레이션이 성공적으로 완료되었습니다.")
 	return nil
 }
 
+// prepareCouponUserIndex (coupon_id, user_id, sequence) 유니크 인덱스를 만들기 전에
+// 사용자 ID 컬럼을 먼저 추가하고, 사용자 ID 없이 저장된 기존 발급 내역을 고유한 값으로 채운 뒤 이전의 일반 인덱스를 제거한다
+func prepareCouponUserIndex(db *gorm.DB) error {
+	migrator := db.Migrator()
+	issuedCoupon := &entity.IssuedCouponEntity{}
+	if migrator.HasIndex(issuedCoupon, "uk_coupon_user") || migrator.HasIndex(issuedCoupon, "uk_coupon_user_seq") {
+		return nil
+	}
+	// 사용자 ID 가 추가되기 전의 테이블은 AutoMigrate 가 컬럼과 유니크 인덱스를 함께 만들면서 기본값 '' 이 중복되어 실패한다
+	if !migrator.HasColumn(issuedCoupon, "UserID") {
+		if err := migrator.AddColumn(issuedCoupon, "UserID"); err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec(
+		"UPDATE issued_coupons SET user_id = CONCAT('legacy:', id) WHERE user_id = ''",
+	).Error; err != nil {
+		return err
+	}
+	if migrator.HasIndex(issuedCoupon, "idx_coupon_user") {
+		return migrator.DropIndex(issuedCoupon, "idx_coupon_user")
+	}
+	return nil
+}
+
+// backfillCouponRemaining coupons.remaining 이 추가되기 전에 만든 캠페인의 남은 재고를 발급 내역으로 채운다
+func backfillCouponRemaining(db *gorm.DB) error {
+	return db.Exec(
+		"UPDATE coupons SET remaining = GREATEST(issue_amount - (" +
+			"SELECT COUNT(*) FROM issued_coupons WHERE issued_coupons.coupon_id = coupons.id AND issued_coupons.deleted_at IS NULL" +
+			"), 0)",
+	).Error
+}
+
+// dropLegacyCouponUserIndex 공용 코드 캠페인은 한 사용자에게 여러 번 발급할 수 있으므로
+// (coupon_id, user_id) 유니크 인덱스를 (coupon_id, user_id, sequence) 인덱스로 대체한다
+func dropLegacyCouponUserIndex(db *gorm.DB) error {
+	migrator := db.Migrator()
+	issuedCoupon := &entity.IssuedCouponEntity{}
+	if !migrator.HasIndex(issuedCoupon, "uk_coupon_user") {
+		return nil
+	}
+	return migrator.DropIndex(issuedCoupon, "uk_coupon_user")
+}
+
 func addMiddleware(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("%s %s", r.Method, r.URL.Path)
 
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, Connect-Protocol-Version")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, Connect-Protocol-Version, Connect-Timeout-Ms, "+
+			"Grpc-Timeout, X-Grpc-Web, X-User-Agent, "+
+			service.StockShardsHeader+", "+service.ErrorModeHeader+", "+service.DeviceIDHeader+", "+
+			service.EligibilityHeader+", "+service.UserSegmentsHeader+", "+service.UserRegionHeader+", "+
+			service.CodeSourceHeader+", "+service.CampaignIDHeader+", "+
+			service.SharedCodeHeader+", "+service.PerUserLimitHeader+", "+service.UsageValidityHeader)
+		w.Header().Set("Access-Control-Expose-Headers", service.ErrorCodeHeader+", "+service.ErrorRetryableHeader+", Retry-After, "+
+			service.ValidFromHeader+", "+service.ValidUntilHeader+", Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
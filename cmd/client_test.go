@@ -0,0 +1,90 @@
+package main
+
+import (
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// baselineCouponEntity 발급 방식과 사용자 ID 가 추가되기 전의 coupons 테이블
+type baselineCouponEntity struct {
+	ID          string     `gorm:"primary_key;type:varchar(36);not null"`
+	Name        string     `gorm:"type:varchar(20);not null"`
+	IssueAmount int64      `gorm:"type:bigint(20);not null"`
+	IssuedAt    time.Time  `gorm:"type:timestamp;not null"`
+	ExpiresAt   time.Time  `gorm:"type:timestamp;not null"`
+	CreatedAt   time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt  time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+	DeletedAt   *time.Time `gorm:"type:timestamp"`
+}
+
+func (baselineCouponEntity) TableName() string {
+	return "coupons"
+}
+
+// baselineIssuedCouponEntity 사용자 ID 가 추가되기 전의 issued_coupons 테이블
+type baselineIssuedCouponEntity struct {
+	ID         string     `gorm:"primary_key;type:varchar(36)"`
+	CouponID   string     `gorm:"type:varchar(36);not null;index:idx_coupon_code,unique"`
+	Code       string     `gorm:"type:varchar(10);not null;index:idx_coupon_code,unique"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+	DeletedAt  *time.Time `gorm:"type:timestamp"`
+}
+
+func (baselineIssuedCouponEntity) TableName() string {
+	return "issued_coupons"
+}
+
+func TestAutoMigrateFromBaselineSchemaWithContainer(t *testing.T) {
+	mysqlContainer, _ := test.SetupMySQLForTest(t)
+	db := mysqlContainer.DB
+	require.NoError(t, db.AutoMigrate(&baselineCouponEntity{}, &baselineIssuedCouponEntity{}))
+
+	now := time.Now()
+	coupon := baselineCouponEntity{
+		ID:          uuid.New().String(),
+		Name:        "기존 캠페인",
+		IssueAmount: 10,
+		IssuedAt:    now.Add(-time.Hour),
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	require.NoError(t, db.Create(&coupon).Error)
+	issuedIds := []string{uuid.New().String(), uuid.New().String(), uuid.New().String()}
+	for i, id := range issuedIds {
+		issued := baselineIssuedCouponEntity{ID: id, CouponID: coupon.ID, Code: uuid.New().String()[:10]}
+		if i == len(issuedIds)-1 {
+			issued.DeletedAt = &now
+		}
+		require.NoError(t, db.Create(&issued).Error)
+	}
+
+	require.NoError(t, autoMigrate(db))
+
+	t.Run("기존 발급 내역의 사용자 ID 는 고유한 값으로 채워지고 유니크 인덱스가 만들어져야 한다", func(t *testing.T) {
+		var issuedCoupons []entity.IssuedCouponEntity
+		require.NoError(t, db.Where("coupon_id = ?", coupon.ID).Find(&issuedCoupons).Error)
+		require.Len(t, issuedCoupons, len(issuedIds))
+		for _, issued := range issuedCoupons {
+			assert.Equal(t, "legacy:"+issued.ID, issued.UserID)
+		}
+		assert.True(t, db.Migrator().HasIndex(&entity.IssuedCouponEntity{}, "uk_coupon_user_seq"))
+	})
+
+	t.Run("기존 캠페인의 남은 재고는 삭제되지 않은 발급 내역을 뺀 값이고 Redis 전략으로 발급해야 한다", func(t *testing.T) {
+		var couponEntity entity.CouponEntity
+		require.NoError(t, db.Where("id = ?", coupon.ID).First(&couponEntity).Error)
+
+		assert.Equal(t, int64(8), couponEntity.Remaining)
+		assert.Equal(t, "redis", couponEntity.IssuanceStrategy)
+	})
+
+	t.Run("다시 실행해도 에러 없이 끝나야 한다", func(t *testing.T) {
+		assert.NoError(t, autoMigrate(db))
+	})
+}
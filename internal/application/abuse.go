@@ -0,0 +1,265 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/cache"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const abuseBlocklistKey = "abuse:blocklist"
+
+// AbuseDecision 발급 요청에 대한 어뷰징 판정. 값이 클수록 강한 조치이다
+type AbuseDecision int
+
+const (
+	AbuseAllow AbuseDecision = iota
+	// AbuseChallenge 추가 인증(CAPTCHA 등)을 통과해야 발급받을 수 있다
+	AbuseChallenge
+	AbuseDeny
+)
+
+func (d AbuseDecision) String() string {
+	switch d {
+	case AbuseChallenge:
+		return "challenge"
+	case AbuseDeny:
+		return "deny"
+	default:
+		return "allow"
+	}
+}
+
+// AbuseCheckRequest 어뷰징 판정에 사용하는 발급 요청 정보
+type AbuseCheckRequest struct {
+	IssueRequest
+	Coupon      *domain.Coupon
+	UserID      string
+	RequestedAt time.Time
+}
+
+// AbuseVerdict Reason 은 로그에만 남기고 사용자에게 노출하지 않는다
+type AbuseVerdict struct {
+	Decision AbuseDecision
+	Reason   string
+}
+
+// AbuseChecker 재고를 차감하기 전에 발급 요청을 검사한다
+// 판정에 실패하면 에러를 반환하며, 이때 해당 검사는 허용으로 취급한다
+type AbuseChecker interface {
+	Name() string
+	Check(ctx context.Context, req AbuseCheckRequest) (AbuseVerdict, error)
+}
+
+// AbuseCheckConfig 기본 제공 검사의 설정. 0 이면 해당 검사를 사용하지 않으며 차단 목록 검사는 항상 사용한다
+//   - MaxUsersPerIP, MaxUsersPerDevice: 한 캠페인에서 같은 IP, 기기로 요청한 사용자 수가 이 값을 넘으면 challenge, 2배를 넘으면 deny
+//   - NewAccountAge, RushWindow: 가입한 지 NewAccountAge 가 지나지 않은 계정이 발급 시작 후 RushWindow 이내에 요청하면 challenge
+type AbuseCheckConfig struct {
+	MaxUsersPerIP     int
+	MaxUsersPerDevice int
+	NewAccountAge     time.Duration
+	RushWindow        time.Duration
+}
+
+// WithAbuseCheckers 기본 제공 검사 이후에 실행할 검사를 추가한다
+func WithAbuseCheckers(checkers ...AbuseChecker) Option {
+	return func(c *CouponService) {
+		c.customAbuseCheckers = append(c.customAbuseCheckers, checkers...)
+	}
+}
+
+// WithAbuseCheckConfig 기본 제공 검사의 설정을 변경한다
+func WithAbuseCheckConfig(config AbuseCheckConfig) Option {
+	return func(c *CouponService) {
+		c.abuseConfig = config
+	}
+}
+
+// newAbuseCheckers Redis 를 사용하는 검사는 캐시가 있을 때만 등록한다
+func newAbuseCheckers(c *CouponService) []AbuseChecker {
+	var checkers []AbuseChecker
+	if c.cache != nil {
+		checkers = append(checkers, &blocklistChecker{cache: c.cache})
+		if c.abuseConfig.MaxUsersPerIP > 0 {
+			checkers = append(checkers, &sharedClientChecker{
+				cache: c.cache, kind: "ip", maxUsers: c.abuseConfig.MaxUsersPerIP,
+				value: func(req AbuseCheckRequest) string { return req.ClientIP },
+			})
+		}
+		if c.abuseConfig.MaxUsersPerDevice > 0 {
+			checkers = append(checkers, &sharedClientChecker{
+				cache: c.cache, kind: "device", maxUsers: c.abuseConfig.MaxUsersPerDevice,
+				value: func(req AbuseCheckRequest) string { return req.DeviceID },
+			})
+		}
+	}
+	if c.abuseConfig.NewAccountAge > 0 && c.abuseConfig.RushWindow > 0 {
+		checkers = append(checkers, &newAccountRushChecker{
+			accountAge: c.abuseConfig.NewAccountAge, window: c.abuseConfig.RushWindow,
+		})
+	}
+	return append(checkers, c.customAbuseCheckers...)
+}
+
+// checkAbuse 모든 검사를 실행해 가장 강한 판정을 반환하며, 판정 결과는 항상 로그로 남긴다
+// deny 판정이 나오면 이후 검사는 실행하지 않는다
+func (c *CouponService) checkAbuse(ctx context.Context, req AbuseCheckRequest) error {
+	decision := AbuseAllow
+	var reasons []string
+	for _, checker := range c.abuseCheckers {
+		verdict, err := checker.Check(ctx, req)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: check failed: %v", checker.Name(), err))
+			continue
+		}
+		if verdict.Decision == AbuseAllow {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %s", checker.Name(), verdict.Reason))
+		if verdict.Decision > decision {
+			decision = verdict.Decision
+		}
+		if decision == AbuseDeny {
+			break
+		}
+	}
+
+	log.Printf(
+		"abuse decision=%s coupon=%s user=%s ip=%s device=%s reasons=[%s]",
+		decision, req.Coupon.ID, req.UserID, req.ClientIP, req.DeviceID, strings.Join(reasons, "; "),
+	)
+	switch decision {
+	case AbuseDeny:
+		return AbuseDeniedError
+	case AbuseChallenge:
+		return AbuseChallengeError
+	default:
+		return nil
+	}
+}
+
+// BlockUser 사용자를 차단 목록에 추가한다. 차단된 사용자는 모든 캠페인에서 발급받을 수 없다
+func (c *CouponService) BlockUser(ctx context.Context, userId string) error {
+	if c.cache == nil {
+		return CacheUnavailableError
+	}
+	if _, err := c.cache.SetAdd(ctx, abuseBlocklistKey, userId); err != nil {
+		return BlocklistUpdateError.Wrap(err)
+	}
+	log.Printf("abuse blocklist add user=%s", userId)
+	return nil
+}
+
+// UnblockUser 사용자를 차단 목록에서 제거한다
+func (c *CouponService) UnblockUser(ctx context.Context, userId string) error {
+	if c.cache == nil {
+		return CacheUnavailableError
+	}
+	if _, err := c.cache.SetDel(ctx, abuseBlocklistKey, userId); err != nil {
+		return BlocklistUpdateError.Wrap(err)
+	}
+	log.Printf("abuse blocklist remove user=%s", userId)
+	return nil
+}
+
+// ListBlockedUsers 차단된 사용자 목록
+func (c *CouponService) ListBlockedUsers(ctx context.Context) ([]string, error) {
+	if c.cache == nil {
+		return nil, CacheUnavailableError
+	}
+	users, err := c.cache.SetMembers(ctx, abuseBlocklistKey)
+	if err != nil {
+		return nil, BlocklistLookupError.Wrap(err)
+	}
+	return users, nil
+}
+
+type blocklistChecker struct {
+	cache cache.Cache
+}
+
+func (b *blocklistChecker) Name() string { return "blocklist" }
+
+func (b *blocklistChecker) Check(ctx context.Context, req AbuseCheckRequest) (AbuseVerdict, error) {
+	blocked, err := b.cache.SetIsMember(ctx, abuseBlocklistKey, req.UserID)
+	if err != nil {
+		return AbuseVerdict{}, err
+	}
+	if blocked {
+		return AbuseVerdict{Decision: AbuseDeny, Reason: "user is blocklisted"}, nil
+	}
+	return AbuseVerdict{}, nil
+}
+
+// sharedClientChecker 한 캠페인에서 같은 IP 또는 기기로 요청한 사용자 ID 를 집합에 모아 개수를 확인한다
+// 집합은 캠페인 종료 시 만료된다
+type sharedClientChecker struct {
+	cache    cache.Cache
+	kind     string
+	maxUsers int
+	value    func(AbuseCheckRequest) string
+}
+
+func (s *sharedClientChecker) Name() string { return "shared_" + s.kind }
+
+func (s *sharedClientChecker) Check(ctx context.Context, req AbuseCheckRequest) (AbuseVerdict, error) {
+	value := s.value(req)
+	if value == "" {
+		return AbuseVerdict{}, nil
+	}
+
+	key := genCouponClientKey(req.Coupon.ID, s.kind, value)
+	added, err := s.cache.SetAdd(ctx, key, req.UserID)
+	if err != nil {
+		return AbuseVerdict{}, err
+	}
+	if added {
+		if _, err2 := s.cache.ExpireAt(ctx, key, req.Coupon.ExpiresAt); err2 != nil {
+			log.Println(err2.Error())
+		}
+	}
+	count, err := s.cache.SetCard(ctx, key)
+	if err != nil {
+		return AbuseVerdict{}, err
+	}
+
+	reason := fmt.Sprintf("%d users from %s %s", count, s.kind, value)
+	switch {
+	case count > int64(2*s.maxUsers):
+		return AbuseVerdict{Decision: AbuseDeny, Reason: reason}, nil
+	case count > int64(s.maxUsers):
+		return AbuseVerdict{Decision: AbuseChallenge, Reason: reason}, nil
+	default:
+		return AbuseVerdict{}, nil
+	}
+}
+
+// newAccountRushChecker 발급 시작 직후의 짧은 구간에 새로 가입한 계정의 요청은 스크립트일 가능성이 높다
+type newAccountRushChecker struct {
+	accountAge time.Duration
+	window     time.Duration
+}
+
+func (n *newAccountRushChecker) Name() string { return "new_account_rush" }
+
+func (n *newAccountRushChecker) Check(_ context.Context, req AbuseCheckRequest) (AbuseVerdict, error) {
+	if req.AccountCreatedAt.IsZero() {
+		return AbuseVerdict{}, nil
+	}
+	sinceStart := req.RequestedAt.Sub(req.Coupon.IssuedAt)
+	accountAge := req.RequestedAt.Sub(req.AccountCreatedAt)
+	if sinceStart >= 0 && sinceStart < n.window && accountAge < n.accountAge {
+		return AbuseVerdict{
+			Decision: AbuseChallenge,
+			Reason:   fmt.Sprintf("account created %s ago claimed %s after start", accountAge, sinceStart),
+		}, nil
+	}
+	return AbuseVerdict{}, nil
+}
+
+func genCouponClientKey(couponID string, kind string, value string) string {
+	return fmt.Sprintf("coupon:{%s}:%s:%s:users", couponID, kind, value)
+}
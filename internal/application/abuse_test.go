@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type stubAbuseChecker struct {
+	verdict AbuseVerdict
+	err     error
+	called  bool
+}
+
+func (s *stubAbuseChecker) Name() string { return "stub" }
+
+func (s *stubAbuseChecker) Check(context.Context, AbuseCheckRequest) (AbuseVerdict, error) {
+	s.called = true
+	return s.verdict, s.err
+}
+
+func TestCheckAbuse(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-50 * time.Millisecond)}
+	newService := func(checkers ...AbuseChecker) *CouponService {
+		return NewCouponService(nil, nil, nil,
+			WithIssuanceStrategy(MySQLIssuance),
+			WithAbuseCheckConfig(AbuseCheckConfig{NewAccountAge: 24 * time.Hour, RushWindow: 200 * time.Millisecond}),
+			WithAbuseCheckers(checkers...),
+		)
+	}
+
+	t.Run("가장 강한 판정이 반환되고 deny 이후의 검사는 실행되지 않아야 한다", func(t *testing.T) {
+		challenge := &stubAbuseChecker{verdict: AbuseVerdict{Decision: AbuseChallenge}}
+		deny := &stubAbuseChecker{verdict: AbuseVerdict{Decision: AbuseDeny}}
+		after := &stubAbuseChecker{}
+
+		err := newService(challenge, deny, after).checkAbuse(ctx, AbuseCheckRequest{Coupon: coupon, RequestedAt: now})
+
+		assert.ErrorIs(t, err, AbuseDeniedError)
+		assert.False(t, after.called)
+	})
+
+	t.Run("검사에 실패하면 허용되어야 한다", func(t *testing.T) {
+		failed := &stubAbuseChecker{err: errors.New("unavailable")}
+
+		err := newService(failed).checkAbuse(ctx, AbuseCheckRequest{Coupon: coupon, RequestedAt: now})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("발급 시작 직후 새로 가입한 계정의 요청은 추가 인증이 필요해야 한다", func(t *testing.T) {
+		err := newService().checkAbuse(ctx, AbuseCheckRequest{
+			Coupon: coupon, RequestedAt: now, IssueRequest: IssueRequest{AccountCreatedAt: now.Add(-time.Hour)},
+		})
+
+		assert.ErrorIs(t, err, AbuseChallengeError)
+	})
+
+	t.Run("오래된 계정이거나 발급 시작 후 충분히 지난 요청은 허용되어야 한다", func(t *testing.T) {
+		service := newService()
+
+		assert.NoError(t, service.checkAbuse(ctx, AbuseCheckRequest{
+			Coupon: coupon, RequestedAt: now, IssueRequest: IssueRequest{AccountCreatedAt: now.Add(-48 * time.Hour)},
+		}))
+		assert.NoError(t, service.checkAbuse(ctx, AbuseCheckRequest{
+			Coupon: coupon, RequestedAt: now.Add(time.Second), IssueRequest: IssueRequest{AccountCreatedAt: now.Add(-time.Hour)},
+		}))
+	})
+}
+
+func TestAbuseCheckWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponService := NewCouponService(
+		redisContainer.Client,
+		repository.NewCouponRepository(mysqlContainer.DB),
+		repository.NewIssuedCouponRepository(mysqlContainer.DB),
+		WithAbuseCheckConfig(AbuseCheckConfig{MaxUsersPerIP: 2}),
+	)
+	now := time.Now()
+	coupon, err := couponService.CreateCoupon(ctx, "어뷰징 테스트", 100, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	t.Run("차단된 사용자는 발급받을 수 없어야 한다", func(t *testing.T) {
+		userID := uuid.New().String()
+		require.NoError(t, couponService.BlockUser(ctx, userID))
+
+		blocked, err := couponService.ListBlockedUsers(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, blocked, userID)
+		assert.ErrorIs(t, couponService.IssueCoupon(ctx, coupon.ID, userID), AbuseDeniedError)
+
+		require.NoError(t, couponService.UnblockUser(ctx, userID))
+		assert.NoError(t, couponService.IssueCoupon(ctx, coupon.ID, userID))
+	})
+
+	t.Run("같은 IP 에서 요청한 사용자 수가 한도를 넘으면 추가 인증, 2배를 넘으면 거부되어야 한다", func(t *testing.T) {
+		var errs []error
+		for i := 0; i < 5; i++ {
+			errs = append(errs, couponService.IssueCoupon(
+				ctx, coupon.ID, fmt.Sprintf("ip-user-%d", i), WithClient("10.0.0.1", ""),
+			))
+		}
+
+		assert.NoError(t, errs[0])
+		assert.NoError(t, errs[1])
+		assert.ErrorIs(t, errs[2], AbuseChallengeError)
+		assert.ErrorIs(t, errs[3], AbuseChallengeError)
+		assert.ErrorIs(t, errs[4], AbuseDeniedError)
+	})
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/cache"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// CampaignAvailability 캠페인 페이지에 노출하는 재고와 발급 상태
+// Found 가 false 이면 Redis 에 캠페인 데이터가 없는 것이며 나머지 값은 의미가 없다
+type CampaignAvailability struct {
+	CampaignID string
+	Found      bool
+	Remaining  int64
+	Status     domain.CampaignStatus
+}
+
+// GetCampaignAvailability 여러 캠페인의 남은 재고와 발급 상태를 MySQL 을 거치지 않고 Redis 에서만 조회한다
+// 로컬 캐시에 캠페인 데이터가 있거나 샤딩되지 않은 캠페인은 한 번의 파이프라인으로 조회하며
+// 응답은 요청한 순서를 따르고 중복된 ID 는 한 번만 포함한다
+// MySQLIssuance 전략은 Redis 의 재고 카운터를 차감하지 않으므로 조회할 수 없다
+func (c *CouponService) GetCampaignAvailability(ctx context.Context, couponIds []string) ([]CampaignAvailability, error) {
+	if c.cache == nil || c.strategyType == MySQLIssuance {
+		return nil, AvailabilityUnavailableError
+	}
+
+	ids := make([]string, 0, len(couponIds))
+	seen := make(map[string]struct{}, len(couponIds))
+	for _, id := range couponIds {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	// 캠페인 데이터를 모르면 데이터와 함께 샤딩되지 않은 재고 카운터, 코드 풀을 미리 읽어 둔다
+	coupons := make([]*domain.Coupon, len(ids))
+	offsets := make([]int, len(ids))
+	var reads []cache.BatchRead
+	for i, id := range ids {
+		offsets[i] = len(reads)
+		if coupon, ok := c.coupons.Get(id); ok {
+			coupons[i] = coupon
+			reads = append(reads, stockReads(coupon)...)
+			continue
+		}
+		reads = append(reads,
+			cache.BatchRead{Key: genCouponDataKey(id)},
+			cache.BatchRead{Key: genCouponAmountKey(id)},
+			cache.BatchRead{Key: genCouponCodePoolKey(id), Len: true},
+		)
+	}
+	results, err := c.cache.Batch(ctx, reads)
+	if err != nil {
+		return nil, AvailabilityUnavailableError.Wrap(err)
+	}
+
+	availabilities := make([]CampaignAvailability, len(ids))
+	var sharded []int
+	for i, id := range ids {
+		availabilities[i].CampaignID = id
+		result := results[offsets[i]:]
+		if coupons[i] != nil {
+			availabilities[i].Remaining = sumStock(result[:len(stockReads(coupons[i]))])
+			continue
+		}
+		if !result[0].Found {
+			continue
+		}
+		var coupon domain.Coupon
+		if err2 := json.Unmarshal(result[0].Value, &coupon); err2 != nil {
+			return nil, ValidateJsonUnmarshalError.Wrap(err2)
+		}
+		c.coupons.Set(id, &coupon)
+		coupons[i] = &coupon
+		switch {
+		case coupon.UsesCodePool():
+			availabilities[i].Remaining = sumStock(result[2:3])
+		case coupon.IsSharded():
+			sharded = append(sharded, i)
+		default:
+			availabilities[i].Remaining = sumStock(result[1:2])
+		}
+	}
+
+	// 샤딩된 캠페인은 데이터를 읽은 뒤에야 하위 카운터의 수를 알 수 있어 한 번 더 조회한다
+	if len(sharded) > 0 {
+		reads = reads[:0]
+		for _, i := range sharded {
+			reads = append(reads, stockReads(coupons[i])...)
+		}
+		results, err = c.cache.Batch(ctx, reads)
+		if err != nil {
+			return nil, AvailabilityUnavailableError.Wrap(err)
+		}
+		for _, i := range sharded {
+			n := len(stockReads(coupons[i]))
+			availabilities[i].Remaining = sumStock(results[:n])
+			results = results[n:]
+		}
+	}
+
+	now := time.Now()
+	for i := range availabilities {
+		if coupons[i] == nil {
+			continue
+		}
+		availabilities[i].Found = true
+		availabilities[i].Status = coupons[i].Status(now, availabilities[i].Remaining)
+	}
+	return availabilities, nil
+}
+
+func stockReads(coupon *domain.Coupon) []cache.BatchRead {
+	if coupon.UsesCodePool() {
+		return []cache.BatchRead{{Key: genCouponCodePoolKey(coupon.ID), Len: true}}
+	}
+	keys := genCouponStockKeys(coupon)
+	reads := make([]cache.BatchRead, len(keys))
+	for i, key := range keys {
+		reads[i] = cache.BatchRead{Key: key}
+	}
+	return reads
+}
+
+// sumStock 다른 요청이 감소 후 원복하기 전이라 음수인 카운터는 0 으로 계산한다
+func sumStock(results []cache.BatchResult) int64 {
+	var total int64
+	for _, result := range results {
+		count := result.Len
+		if !result.Found {
+			continue
+		}
+		if result.Value != nil {
+			count, _ = strconv.ParseInt(string(result.Value), 10, 64)
+		}
+		if count > 0 {
+			total += count
+		}
+	}
+	return total
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCampaignAvailabilityRequiresCache(t *testing.T) {
+	service := NewCouponService(nil, nil, nil, WithIssuanceStrategy(MySQLIssuance))
+
+	_, err := service.GetCampaignAvailability(context.Background(), []string{"campaign"})
+
+	assert.ErrorIs(t, err, AvailabilityUnavailableError)
+}
+
+func TestGetCampaignAvailabilityWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.CouponCodeEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	issuer := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+	now := time.Now()
+
+	single, err := issuer.CreateCoupon(ctx, "일반 쿠폰", 3, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	sharded, err := issuer.CreateCoupon(ctx, "샤딩 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour), domain.WithStockShards(4))
+	require.NoError(t, err)
+	pool, err := issuer.CreateCoupon(ctx, "제휴 코드", 0, now.Add(-time.Hour), now.Add(time.Hour), domain.WithCodePool())
+	require.NoError(t, err)
+	_, err = issuer.ImportCodes(ctx, pool.ID, []string{"PIN-1", "PIN-2"})
+	require.NoError(t, err)
+	scheduled, err := issuer.CreateCoupon(ctx, "예정 쿠폰", 1, now.Add(time.Hour), now.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	for _, id := range []string{single.ID, sharded.ID, pool.ID} {
+		_, err = issuer.Issue(ctx, id, uuid.New().String())
+		require.NoError(t, err)
+	}
+	_, err = issuer.Issue(ctx, single.ID, uuid.New().String())
+	require.NoError(t, err)
+	_, err = issuer.Issue(ctx, single.ID, uuid.New().String())
+	require.NoError(t, err)
+
+	ids := []string{single.ID, sharded.ID, pool.ID, scheduled.ID, "unknown", sharded.ID}
+	expected := []CampaignAvailability{
+		{CampaignID: single.ID, Found: true, Remaining: 1, Status: domain.CampaignStatusOpen},
+		{CampaignID: sharded.ID, Found: true, Remaining: 9, Status: domain.CampaignStatusOpen},
+		{CampaignID: pool.ID, Found: true, Remaining: 1, Status: domain.CampaignStatusOpen},
+		{CampaignID: scheduled.ID, Found: true, Remaining: 1, Status: domain.CampaignStatusScheduled},
+		{CampaignID: "unknown"},
+	}
+
+	t.Run("캠페인 데이터를 캐싱하지 않은 인스턴스도 Redis 에서만 재고와 상태를 조회해야 한다", func(t *testing.T) {
+		reader := NewCouponService(redisContainer.Client, nil, nil)
+
+		availabilities, err := reader.GetCampaignAvailability(ctx, ids)
+		require.NoError(t, err)
+		assert.Equal(t, expected, availabilities)
+
+		// 로컬 캐시에 적재한 캠페인 데이터로 다시 조회해도 결과가 같아야 한다
+		availabilities, err = reader.GetCampaignAvailability(ctx, ids)
+		require.NoError(t, err)
+		assert.Equal(t, expected, availabilities)
+	})
+
+	t.Run("재고를 모두 발급하면 sold_out 이어야 한다", func(t *testing.T) {
+		_, err = issuer.Issue(ctx, single.ID, uuid.New().String())
+		require.NoError(t, err)
+
+		availabilities, err := issuer.GetCampaignAvailability(ctx, []string{single.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []CampaignAvailability{
+			{CampaignID: single.ID, Found: true, Remaining: 0, Status: domain.CampaignStatusSoldOut},
+		}, availabilities)
+	})
+}
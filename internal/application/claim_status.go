@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/cache"
+	"log"
+)
+
+// ClaimStatus 사용자가 캠페인의 쿠폰을 발급받았는지 여부
+// Coupon 은 가장 최근에 발급된 쿠폰이며, 발급 중이라 아직 DB 에 저장되지 않았다면 Claimed 이더라도 nil 이다
+type ClaimStatus struct {
+	CampaignID string
+	Claimed    bool
+	Coupon     *domain.IssuedCoupon
+}
+
+// GetUserClaimStatus 사용자가 캠페인의 쿠폰을 발급받았는지 조회한다
+func (c *CouponService) GetUserClaimStatus(ctx context.Context, couponId string, userId string) (ClaimStatus, error) {
+	statuses, err := c.GetUserClaimStatuses(ctx, []string{couponId}, userId)
+	if err != nil {
+		return ClaimStatus{}, err
+	}
+	return statuses[0], nil
+}
+
+// GetUserClaimStatuses 여러 캠페인에 대해 사용자가 쿠폰을 발급받았는지 조회한다
+// 사용자 집합을 한 번의 파이프라인으로 확인하고, 발급받은 캠페인만 DB 에서 발급된 코드와 상태를 조회한다
+// Redis 를 사용하지 않는 전략이거나 Redis 를 사용할 수 없으면 모든 캠페인을 DB 에서 조회한다
+// 응답은 요청한 순서를 따르고 중복된 ID 는 한 번만 포함한다
+func (c *CouponService) GetUserClaimStatuses(ctx context.Context, couponIds []string, userId string) ([]ClaimStatus, error) {
+	ids := make([]string, 0, len(couponIds))
+	seen := make(map[string]struct{}, len(couponIds))
+	for _, id := range couponIds {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	// 첫 번째 발급의 구성원은 사용자 ID 이므로 사용자 ID 만으로 발급 여부를 알 수 있다
+	claimedInCache := c.claimedInCache(ctx, ids, userId)
+	candidates := ids
+	if claimedInCache != nil {
+		candidates = make([]string, 0, len(ids))
+		for _, id := range ids {
+			if claimedInCache[id] {
+				candidates = append(candidates, id)
+			}
+		}
+	}
+
+	issuedCoupons := make(map[string]*domain.IssuedCoupon, len(candidates))
+	if len(candidates) > 0 {
+		found, err := c.issuedCouponRepository.FindByUser(userId, candidates)
+		if err != nil {
+			return nil, ClaimStatusLookupError.Wrap(err)
+		}
+		for i := range found {
+			issuedCoupons[found[i].CouponID] = &found[i]
+		}
+	}
+
+	statuses := make([]ClaimStatus, len(ids))
+	for i, id := range ids {
+		statuses[i] = ClaimStatus{
+			CampaignID: id,
+			Claimed:    claimedInCache[id] || issuedCoupons[id] != nil,
+			Coupon:     issuedCoupons[id],
+		}
+	}
+	return statuses, nil
+}
+
+// claimedInCache 사용자 집합에 포함된 캠페인을 반환한다. 사용자 집합을 사용할 수 없으면 nil 을 반환한다
+func (c *CouponService) claimedInCache(ctx context.Context, couponIds []string, userId string) map[string]bool {
+	if c.cache == nil || c.strategyType == MySQLIssuance {
+		return nil
+	}
+
+	reads := make([]cache.BatchRead, len(couponIds))
+	for i, id := range couponIds {
+		reads[i] = cache.BatchRead{Key: genCouponUserKey(id), Member: userId}
+	}
+	results, err := c.cache.Batch(ctx, reads)
+	if err != nil {
+		log.Println(err.Error())
+		return nil
+	}
+
+	claimed := make(map[string]bool, len(couponIds))
+	for i, id := range couponIds {
+		claimed[id] = results[i].Found
+	}
+	return claimed
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserClaimStatusWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	now := time.Now()
+
+	for _, strategy := range []IssuanceStrategyType{RedisIssuance, MySQLIssuance, HybridIssuance} {
+		t.Run(string(strategy)+" 전략은 발급받은 캠페인만 코드와 상태를 함께 반환해야 한다", func(t *testing.T) {
+			couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo, WithIssuanceStrategy(strategy))
+			claimed, err := couponService.CreateCoupon(ctx, "발급 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+			require.NoError(t, err)
+			revoked, err := couponService.CreateCoupon(ctx, "취소 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+			require.NoError(t, err)
+			notClaimed, err := couponService.CreateCoupon(ctx, "미발급 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+			require.NoError(t, err)
+			userId := uuid.New().String()
+
+			issued, err := couponService.Issue(ctx, claimed.ID, userId)
+			require.NoError(t, err)
+			toRevoke, err := couponService.Issue(ctx, revoked.ID, userId)
+			require.NoError(t, err)
+			_, err = couponService.RevokeCoupon(ctx, toRevoke.ID)
+			require.NoError(t, err)
+			_, err = couponService.Issue(ctx, notClaimed.ID, uuid.New().String())
+			require.NoError(t, err)
+
+			statuses, err := couponService.GetUserClaimStatuses(ctx, []string{claimed.ID, revoked.ID, notClaimed.ID, claimed.ID}, userId)
+			require.NoError(t, err)
+			require.Len(t, statuses, 3)
+			assert.True(t, statuses[0].Claimed)
+			require.NotNil(t, statuses[0].Coupon)
+			assert.Equal(t, issued.Code, statuses[0].Coupon.Code)
+			assert.Equal(t, domain.IssuedCouponIssued, statuses[0].Coupon.Status)
+			assert.True(t, statuses[1].Claimed)
+			require.NotNil(t, statuses[1].Coupon)
+			assert.Equal(t, domain.IssuedCouponRevoked, statuses[1].Coupon.Status)
+			assert.Equal(t, ClaimStatus{CampaignID: notClaimed.ID}, statuses[2])
+
+			status, err := couponService.GetUserClaimStatus(ctx, notClaimed.ID, userId)
+			require.NoError(t, err)
+			assert.False(t, status.Claimed)
+		})
+	}
+
+	t.Run("사용자 집합에만 있고 아직 저장되지 않은 발급은 코드 없이 발급받은 것으로 반환해야 한다", func(t *testing.T) {
+		couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+		coupon, err := couponService.CreateCoupon(ctx, "발급 중 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		userId := uuid.New().String()
+		_, err = redisContainer.Client.SAdd(ctx, genCouponUserKey(coupon.ID), userId).Result()
+		require.NoError(t, err)
+
+		status, err := couponService.GetUserClaimStatus(ctx, coupon.ID, userId)
+		require.NoError(t, err)
+		assert.Equal(t, ClaimStatus{CampaignID: coupon.ID, Claimed: true}, status)
+	})
+}
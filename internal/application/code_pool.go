@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/repository"
+	"errors"
+	"log"
+)
+
+// ImportCodes 코드 풀 캠페인에 코드를 등록하고 새로 등록된 코드 수를 반환한다. 이미 등록된 코드는 무시한다
+// 코드는 MySQL 에 저장한 후 Redis 의 코드 풀 리스트에 추가하며, 리스트에 추가하지 못하면 저장한 코드를 다시 제거한다
+func (c *CouponService) ImportCodes(ctx context.Context, couponId string, codes []string) (int, error) {
+	normalized, err := domain.NormalizePoolCodes(codes)
+	if err != nil {
+		return 0, InvalidRequestError.Wrap(err)
+	}
+
+	coupon, err := c.couponRepository.FindOne(couponId)
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		return 0, CouponNotFoundError.Wrap(err)
+	}
+	if err != nil {
+		return 0, CouponLookupError.Wrap(err)
+	}
+	if !coupon.UsesCodePool() {
+		return 0, CodePoolNotEnabledError
+	}
+	if len(normalized) == 0 {
+		return 0, nil
+	}
+
+	imported, err := c.couponRepository.ImportCodes(couponId, normalized)
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		return 0, CouponNotFoundError.Wrap(err)
+	}
+	if err != nil {
+		return 0, CodeImportError.Wrap(err)
+	}
+	if len(imported) > 0 && c.cache != nil {
+		key := genCouponCodePoolKey(couponId)
+		if _, err2 := c.cache.ListPush(ctx, key, imported...); err2 != nil {
+			if rmErr := c.couponRepository.RemoveCodes(couponId, imported); rmErr != nil {
+				log.Printf("failed to remove imported codes coupon=%s: %v", couponId, rmErr)
+			}
+			return 0, CodePoolCacheError.Wrap(err2)
+		}
+		if _, err2 := c.cache.ExpireAt(ctx, key, coupon.ExpiresAt); err2 != nil {
+			log.Println(err2.Error())
+		}
+	}
+
+	log.Printf("code pool import coupon=%s imported=%d duplicates=%d", couponId, len(imported), len(normalized)-len(imported))
+	if len(imported) > 0 {
+		c.notifyStockChanged(ctx, couponId)
+	}
+	return len(imported), nil
+}
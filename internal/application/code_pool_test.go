@@ -0,0 +1,62 @@
+package application
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCodePoolWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.CouponCodeEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	now := time.Now()
+
+	for _, strategy := range []IssuanceStrategyType{RedisIssuance, MySQLIssuance, HybridIssuance} {
+		t.Run(string(strategy)+" 전략은 등록한 코드로만 발급하고 코드가 소진되면 매진되어야 한다", func(t *testing.T) {
+			couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo, WithIssuanceStrategy(strategy))
+			coupon, err := couponService.CreateCoupon(ctx, "제휴 코드", 0, now.Add(-time.Hour), now.Add(time.Hour), domain.WithCodePool())
+			require.NoError(t, err)
+
+			imported, err := couponService.ImportCodes(ctx, coupon.ID, []string{"PIN-1", "PIN-2", "PIN-1"})
+			require.NoError(t, err)
+			assert.Equal(t, 2, imported)
+			imported, err = couponService.ImportCodes(ctx, coupon.ID, []string{"PIN-2", "PIN-3"})
+			require.NoError(t, err)
+			assert.Equal(t, 1, imported)
+
+			remaining, err := couponService.GetRemainingStock(ctx, coupon.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), remaining)
+
+			for i := 0; i < 3; i++ {
+				require.NoError(t, couponService.IssueCoupon(ctx, coupon.ID, uuid.New().String()))
+			}
+			assert.ErrorIs(t, couponService.IssueCoupon(ctx, coupon.ID, uuid.New().String()), AllCouponIssuedError)
+
+			var codes []string
+			for _, issued := range issuedCouponRepo.FindByCouponId(coupon.ID) {
+				codes = append(codes, issued.Code)
+			}
+			assert.ElementsMatch(t, []string{"PIN-1", "PIN-2", "PIN-3"}, codes)
+		})
+	}
+
+	t.Run("코드 풀을 사용하지 않는 캠페인에는 코드를 등록할 수 없어야 한다", func(t *testing.T) {
+		couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+		coupon, err := couponService.CreateCoupon(ctx, "일반 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+
+		_, err = couponService.ImportCodes(ctx, coupon.ID, []string{"PIN-1"})
+
+		assert.ErrorIs(t, err, CodePoolNotEnabledError)
+	})
+}
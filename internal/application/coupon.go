@@ -5,94 +5,258 @@ import (
 	"coupon-service/internal/domain"
 	"coupon-service/internal/infrastructure/cache"
 	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/infrastructure/webhook"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"log"
 	"time"
 )
 
+const (
+	couponInvalidationChannel = "coupon:invalidate"
+	localCouponCacheSize      = 1024
+	localCouponCacheTTL       = 30 * time.Second
+)
+
 type CouponService struct {
 	cache                  cache.Cache
+	breaker                *cache.CircuitBreaker
+	breakerConfig          cache.CircuitBreakerConfig
+	dbFallback             bool
+	accountClaims          bool
+	strategyType           IssuanceStrategyType
+	strategy               IssuanceStrategy
+	abuseConfig            AbuseCheckConfig
+	customAbuseCheckers    []AbuseChecker
+	abuseCheckers          []AbuseChecker
+	coupons                *cache.LocalCache[*domain.Coupon]
+	sharedCodes            *cache.LocalCache[string]
+	events                 domain.EventPublisher
+	eventOutboxRepository  *repository.EventOutboxRepository
+	soldOut                *cache.LocalCache[bool]
+	expiryConfig           ExpirySweepConfig
+	watchConfig            WatchConfig
+	stockWatchers          *stockWatchers
+	webhookConfig          WebhookConfig
+	webhookRepository      *repository.WebhookRepository
+	webhookSender          *webhook.Sender
+	webhookSubscriptions   *cache.LocalCache[[]domain.WebhookSubscription]
 	couponRepository       *repository.CouponRepository
 	issuedCouponRepository *repository.IssuedCouponRepository
+	issuances              issuanceTracker
+}
+
+type Option func(*CouponService)
+
+// WithCacheCircuitBreaker Redis 호출을 감싸는 circuit breaker 의 설정을 변경한다
+func WithCacheCircuitBreaker(config cache.CircuitBreakerConfig) Option {
+	return func(c *CouponService) {
+		c.breakerConfig = config
+	}
+}
+
+// WithDBFallback Redis 를 사용할 수 없는 동안 캠페인 row 잠금을 이용해 DB 만으로 발급한다
+// 처리량은 낮지만 발행량과 사용자 중복 제한은 유지된다
+func WithDBFallback() Option {
+	return func(c *CouponService) {
+		c.dbFallback = true
+	}
+}
+
+// WithIssuanceStrategy 재고 차감 방식을 지정한다. 기본값은 RedisIssuance 이다
+func WithIssuanceStrategy(strategyType IssuanceStrategyType) Option {
+	return func(c *CouponService) {
+		c.strategyType = strategyType
+	}
 }
 
+// NewCouponService MySQLIssuance 전략은 Redis 없이 동작하므로 cacheClient 로 nil 을 전달할 수 있다
 func NewCouponService(
-	cacheClient *redis.Client,
+	cacheClient redis.UniversalClient,
 	couponRepository *repository.CouponRepository,
 	issuedCouponRepository *repository.IssuedCouponRepository,
+	opts ...Option,
 ) *CouponService {
-	return &CouponService{
-		cache:                  cache.NewCacheClient(cacheClient),
+	service := &CouponService{
+		breakerConfig:          cache.DefaultCircuitBreakerConfig,
+		strategyType:           RedisIssuance,
+		coupons:                cache.NewLocalCache[*domain.Coupon](localCouponCacheSize, localCouponCacheTTL),
+		sharedCodes:            cache.NewLocalCache[string](localCouponCacheSize, localCouponCacheTTL),
+		soldOut:                cache.NewLocalCache[bool](localCouponCacheSize, localCouponCacheTTL),
+		expiryConfig:           DefaultExpirySweepConfig,
+		watchConfig:            DefaultWatchConfig,
+		stockWatchers:          newStockWatchers(),
+		webhookConfig:          DefaultWebhookConfig,
+		webhookSubscriptions:   cache.NewLocalCache[[]domain.WebhookSubscription](localCouponCacheSize, localCouponCacheTTL),
 		couponRepository:       couponRepository,
 		issuedCouponRepository: issuedCouponRepository,
 	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	if cacheClient != nil {
+		service.breaker = cache.NewCircuitBreaker(service.breakerConfig)
+		service.cache = cache.NewCircuitBreakerCache(cache.NewCacheClient(cacheClient), service.breaker)
+	}
+	if service.webhookRepository != nil {
+		service.webhookSender = webhook.NewSender(service.webhookConfig.Timeout)
+	}
+	service.strategy = newIssuanceStrategy(service)
+	service.abuseCheckers = newAbuseCheckers(service)
+	return service
 }
 
-const (
-	ValidateJsonUnmarshalError = IssueCouponError("ValidateJsonUnmarshalError")
-	CouponNotStartedError      = IssueCouponError("coupon issuance has not started yet")
-	CouponExpiredError         = IssueCouponError("the coupon issuance period has expired")
-	DuplicatedCouponUserError  = IssueCouponError("coupon already issued to this user")
-	CouponAmountRecoveryError  = IssueCouponError("failed to increment coupon amount for recover")
-	DeleteRecoveryError        = IssueCouponError("failed to delete coupon for recover")
-	AllCouponIssuedError       = IssueCouponError("all coupons has been issued")
-	CacheAddUserError          = IssueCouponError("failed to add coupon")
-	CouponDecrError            = IssueCouponError("failed to decrement coupon")
-	DataKeyNotFoundError       = IssueCouponError("data key not found")
-	IssuedCouponCreationError  = IssueCouponError("failed to create coupon code")
-)
+// IssueRequest 발급 대상 조건과 어뷰징 검사에 사용하는 발급 요청의 부가 정보
+type IssueRequest struct {
+	ClientIP         string
+	DeviceID         string
+	AccountCreatedAt time.Time
+	Segments         []string
+	Region           string
+}
 
-const (
-	FailedSaveCouponError        = CreateCouponError("failed to save coupon")
-	CouponDataRecoveryError      = CreateCouponError("failed to recover coupon")
-	CouponCacheDataRecoveryError = CreateCouponError("failed to recover coupon caching data")
-	CouponCacheError             = CreateCouponError("failed to cache coupon data")
-)
+// IssueOption 발급 요청의 부가 정보
+type IssueOption func(*IssueRequest)
 
-const (
-	CouponNotFoundError = GetCouponError("coupon not found")
-)
+// WithClient 요청한 클라이언트의 IP 와 기기 식별값
+func WithClient(ip string, deviceID string) IssueOption {
+	return func(r *IssueRequest) {
+		r.ClientIP = ip
+		r.DeviceID = deviceID
+	}
+}
 
-type IssueCouponError string
+// WithAccountCreatedAt 요청한 사용자의 가입 일시
+func WithAccountCreatedAt(createdAt time.Time) IssueOption {
+	return func(r *IssueRequest) {
+		r.AccountCreatedAt = createdAt
+	}
+}
 
-func (e IssueCouponError) Error() string { return string(e) }
+// WithSegments 요청한 사용자가 속한 세그먼트
+func WithSegments(segments ...string) IssueOption {
+	return func(r *IssueRequest) {
+		r.Segments = segments
+	}
+}
 
-type CreateCouponError string
+// WithRegion 요청한 사용자의 지역
+func WithRegion(region string) IssueOption {
+	return func(r *IssueRequest) {
+		r.Region = region
+	}
+}
 
-func (e CreateCouponError) Error() string { return string(e) }
+// IssueCoupon 발급 기간과 발급 대상 조건을 확인하고 어뷰징 검사를 통과한 요청만 재고를 차감해 발급한다
+// couponId 대신 공용 코드 캠페인의 공용 코드를 전달할 수 있다
+func (c *CouponService) IssueCoupon(
+	ctx context.Context,
+	couponId string,
+	userId string,
+	opts ...IssueOption,
+) error {
+	_, err := c.Issue(ctx, couponId, userId, opts...)
+	return err
+}
 
-type GetCouponError string
+// Issue IssueCoupon 과 같으며 발급된 쿠폰을 반환한다
+func (c *CouponService) Issue(
+	ctx context.Context,
+	couponId string,
+	userId string,
+	opts ...IssueOption,
+) (*domain.IssuedCoupon, error) {
+	if sharedCouponId, ok := c.sharedCodes.Get(domain.NormalizeSharedCode(couponId)); ok {
+		couponId = sharedCouponId
+	}
+	issuedCoupon, err := c.issue(ctx, couponId, userId, false, opts...)
+	if !errors.Is(err, DataKeyNotFoundError) || isCouponID(couponId) {
+		return issuedCoupon, err
+	}
 
-func (e GetCouponError) Error() string { return string(e) }
+	// 캠페인 ID 가 아니면 공용 코드로 조회하며, 공용 코드 캠페인도 없으면 원래의 에러를 반환한다
+	sharedCouponId, lookupErr := c.findSharedCodeCouponID(couponId)
+	if lookupErr != nil {
+		if !errors.Is(lookupErr, CouponNotFoundError) {
+			log.Println(lookupErr.Error())
+		}
+		return nil, err
+	}
+	return c.issue(ctx, sharedCouponId, userId, false, opts...)
+}
 
-func (c *CouponService) IssueCoupon(
+// issue redeem 이면 발급과 동시에 사용 처리된 쿠폰을 저장한다
+func (c *CouponService) issue(
 	ctx context.Context,
 	couponId string,
 	userId string,
-) error {
-	dataKey := "coupon:" + couponId + ":data"
-	userStoreKey := "coupon:" + couponId + ":users"
-	couponKey := "coupon:" + couponId + ":remaining"
+	redeem bool,
+	opts ...IssueOption,
+) (*domain.IssuedCoupon, error) {
+	if !c.issuances.begin() {
+		return nil, ServiceShuttingDownError
+	}
+	defer c.issuances.end()
 	now := time.Now()
 
-	err := c.validateCouponEvent(ctx, dataKey, now)
+	issuedCoupon, err := domain.NewIssuedCoupon(couponId, userId, now)
 	if err != nil {
-		return err
+		return nil, InvalidRequestError.Wrap(err)
+	}
+	coupon, err := c.validateCouponEvent(ctx, couponId, now)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.checkIssuanceStrategy(coupon); err != nil {
+		return nil, err
+	}
+	issuedCoupon.ApplyValidity(coupon.Validity)
+	if redeem {
+		if !issuedCoupon.UsableAt(now) {
+			return nil, CouponNotUsableError
+		}
+		issuedCoupon.Redeem(now)
+	}
+	// 코드 풀 캠페인은 발급 전략이 코드 풀에서 꺼낸 코드를 채운다
+	if coupon.UsesCodePool() {
+		issuedCoupon.Code = ""
 	}
 
-	err2 := c.controlConcurrent(ctx, userStoreKey, userId, couponKey)
-	if err2 != nil {
-		return err2
+	var issueReq IssueRequest
+	for _, opt := range opts {
+		opt(&issueReq)
+	}
+	if err2 := c.checkEligibility(ctx, coupon, userId, issueReq, now); err2 != nil {
+		return nil, err2
+	}
+	if err2 := c.checkAbuse(ctx, AbuseCheckRequest{
+		IssueRequest: issueReq, Coupon: coupon, UserID: userId, RequestedAt: now,
+	}); err2 != nil {
+		return nil, err2
 	}
 
-	err3 := c.issuedCouponRepository.Save(domain.NewIssuedCoupon(couponId, now))
-	if err3 != nil {
-		return IssuedCouponCreationError
+	// Redis 에서 재고를 차감한 뒤 요청이 취소되어도 저장이나 원복까지 끝내도록 취소를 전파하지 않는다
+	soldOut, err2 := c.strategy.Issue(context.WithoutCancel(ctx), coupon, issuedCoupon)
+	if err2 != nil {
+		// 마지막 재고를 발급한 요청이 매진을 기록하지 못했더라도 이후 거절된 요청에서 기록한다
+		if errors.Is(err2, AllCouponIssuedError) {
+			c.markSoldOut(ctx, coupon, now)
+		}
+		return nil, err2
 	}
+	c.notifyStockChanged(ctx, coupon.ID)
 
-	return nil
+	events := []domain.Event{domain.NewCouponEvent(domain.CouponIssued, issuedCoupon, now)}
+	if redeem {
+		events = append(events, domain.NewCouponEvent(domain.CouponRedeemed, issuedCoupon, now))
+	}
+	c.publish(ctx, events...)
+	if soldOut {
+		c.markSoldOut(ctx, coupon, now)
+	}
+	return issuedCoupon, nil
 }
 
 func (c *CouponService) CreateCoupon(
@@ -101,127 +265,202 @@ func (c *CouponService) CreateCoupon(
 	amount int64,
 	issuedAt time.Time,
 	expiresAt time.Time,
+	opts ...domain.CouponOption,
 ) (*domain.Coupon, error) {
-	coupon := domain.NewCoupon(name, amount, issuedAt, expiresAt)
-	err := c.couponRepository.Save(coupon)
+	coupon, err := domain.NewCoupon(name, amount, issuedAt, expiresAt, opts...)
 	if err != nil {
-		fmt.Println(err.Error())
-		return nil, FailedSaveCouponError
+		return nil, InvalidRequestError.Wrap(err)
 	}
-	if err2 := c.cacheCouponData(ctx, coupon); err2 != nil {
-		return nil, err2
+	if err = c.checkEligibilitySupported(coupon.Eligibility); err != nil {
+		return nil, err
+	}
+	coupon.IssuanceStrategy = string(c.strategyType)
+	err = c.couponRepository.Save(coupon)
+	if errors.Is(err, repository.ErrSharedCodeExists) {
+		return nil, SharedCodeConflictError.Wrap(err)
+	}
+	if err != nil {
+		return nil, FailedSaveCouponError.Wrap(err)
+	}
+	if c.cache != nil {
+		if err2 := c.cacheCouponData(ctx, coupon); err2 != nil {
+			return nil, err2
+		}
 	}
-	if err3 := c.cacheCouponCount(ctx, coupon); err3 != nil {
-		return nil, err3
+	if err3 := c.strategy.Prepare(ctx, coupon); err3 != nil {
+		return nil, c.recoverCouponData(ctx, coupon, err3)
 	}
 
+	c.publish(ctx, domain.NewCampaignEvent(domain.CampaignCreated, coupon, coupon.CreatedAt))
 	return coupon, nil
 }
 
 func (c *CouponService) GetCoupon(id string) (*domain.Coupon, error) {
 	coupon, err := c.couponRepository.FindOne(id)
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		return nil, CouponNotFoundError.Wrap(err)
+	}
 	if err != nil {
-		fmt.Println(err.Error())
-		return nil, CouponNotFoundError
+		return nil, CouponLookupError.Wrap(err)
 	}
 	coupon.IssuedCoupons = c.issuedCouponRepository.FindByCouponId(id)
 	return coupon, nil
 }
 
-func (c *CouponService) validateCouponEvent(ctx context.Context, dataKey string, now time.Time) error {
-	var coupon domain.Coupon
-	data, err := c.cache.Get(ctx, dataKey)
+// ListenCouponInvalidation 다른 인스턴스에서 캠페인 데이터가 변경되면 로컬 캐시에서 제거하고
+// 캠페인 상태 스트림이 변경된 데이터로 다시 보내도록 알린다. ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) ListenCouponInvalidation(ctx context.Context) {
+	if c.cache == nil {
+		return
+	}
+	for couponId := range c.cache.Subscribe(ctx, couponInvalidationChannel) {
+		c.coupons.Delete(couponId)
+		c.stockWatchers.notify(couponId)
+	}
+}
+
+// GetRemainingStock 남은 재고를 조회한다. 샤딩된 캠페인은 하위 카운터의 합계를 반환한다
+func (c *CouponService) GetRemainingStock(ctx context.Context, couponId string) (int64, error) {
+	coupon, err := c.loadCouponData(ctx, couponId)
 	if err != nil {
-		return DataKeyNotFoundError
+		return 0, err
 	}
-	if err2 := json.Unmarshal(data, &coupon); err2 != nil {
-		return ValidateJsonUnmarshalError
+	if err = c.checkIssuanceStrategy(coupon); err != nil {
+		return 0, err
+	}
+
+	return c.strategy.Remaining(ctx, coupon)
+}
+
+func (c *CouponService) validateCouponEvent(ctx context.Context, couponId string, now time.Time) (*domain.Coupon, error) {
+	coupon, err := c.loadCouponData(ctx, couponId)
+	if err != nil {
+		return nil, err
 	}
 	if coupon.IssuedAt.After(now) {
-		return CouponNotStartedError
+		return nil, CouponNotStartedError
 	}
 	if coupon.ExpiresAt.Before(now) {
-		return CouponExpiredError
+		return nil, CouponExpiredError
 	}
-	return nil
+	if coupon.Paused() {
+		return nil, CampaignPausedError
+	}
+	return coupon, nil
 }
 
-func (c *CouponService) controlConcurrent(
-	ctx context.Context,
-	userStoreKey string,
-	userId string,
-	couponKey string,
-) error {
-	added, err := c.cache.SetAdd(ctx, userStoreKey, userId)
-	if err != nil {
-		fmt.Println(err.Error())
-		return CacheAddUserError
+func (c *CouponService) loadCouponData(ctx context.Context, couponId string) (*domain.Coupon, error) {
+	if coupon, ok := c.coupons.Get(couponId); ok {
+		return coupon, nil
 	}
-	if added == false {
-		return DuplicatedCouponUserError
+	if c.cache == nil {
+		return c.loadCouponDataFromDB(couponId)
 	}
 
-	count, err := c.cache.Decr(ctx, couponKey)
+	var coupon domain.Coupon
+	data, err := c.cache.Get(ctx, genCouponDataKey(couponId))
+	if errors.Is(err, cache.ErrKeyNotFound) {
+		return nil, DataKeyNotFoundError
+	}
 	if err != nil {
-		fmt.Println(err.Error())
-		return CouponDecrError
+		// Redis 장애 중에도 발급 기간 검증은 가능하도록 DB 에서 조회한다
+		return c.loadCouponDataFromDB(couponId)
+	}
+	if err2 := json.Unmarshal(data, &coupon); err2 != nil {
+		return nil, ValidateJsonUnmarshalError.Wrap(err2)
 	}
+	c.coupons.Set(couponId, &coupon)
+	return &coupon, nil
+}
 
-	if count < 0 {
-		_, incrErr := c.cache.Incr(ctx, couponKey)
-		if incrErr != nil {
-			log.Println(incrErr)
-			return CouponAmountRecoveryError
-		}
+func (c *CouponService) loadCouponDataFromDB(couponId string) (*domain.Coupon, error) {
+	coupon, err := c.couponRepository.FindOne(couponId)
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		return nil, DataKeyNotFoundError.Wrap(err)
+	}
+	if err != nil {
+		return nil, CouponLookupError.Wrap(err)
+	}
+	c.coupons.Set(couponId, coupon)
+	return coupon, nil
+}
 
-		_, delErr := c.cache.SetDel(ctx, userStoreKey, userId)
-		if delErr != nil {
-			log.Println(delErr)
-			return DeleteRecoveryError
-		}
-		return AllCouponIssuedError
+// invalidateCouponData Redis 를 사용하지 않으면 이 인스턴스의 로컬 캐시와 스트림에만 반영한다
+func (c *CouponService) invalidateCouponData(ctx context.Context, couponId string) {
+	c.coupons.Delete(couponId)
+	if c.cache == nil {
+		c.stockWatchers.notify(couponId)
+		return
+	}
+	if err := c.cache.Publish(ctx, couponInvalidationChannel, couponId); err != nil {
+		log.Println(err.Error())
 	}
-	return nil
 }
 
 func (c *CouponService) cacheCouponData(ctx context.Context, coupon *domain.Coupon) error {
 	err := c.cache.Set(ctx, genCouponDataKey(coupon.ID), coupon)
 	if err != nil {
-		fmt.Println(err.Error())
-
 		if err3 := c.couponRepository.Delete(coupon.ID); err3 != nil {
 			log.Println(err3.Error())
-			return CouponDataRecoveryError
+			return CouponDataRecoveryError.Wrap(err)
 		}
-		return CouponCacheError
+		return CouponCacheError.Wrap(err)
 	}
+	c.invalidateCouponData(ctx, coupon.ID)
 	return nil
 }
 
-func (c *CouponService) cacheCouponCount(ctx context.Context, coupon *domain.Coupon) error {
-	err := c.cache.Set(ctx, genCouponAmountKey(coupon.ID), coupon.IssueAmount)
-	if err != nil {
-		fmt.Println(err.Error())
-
-		if err2 := c.couponRepository.Delete(coupon.ID); err2 != nil {
-			log.Println(err2.Error())
-			return CouponDataRecoveryError
-		}
-
-		if err3 := c.cache.Del(ctx, genCouponDataKey(coupon.ID)); err3 != nil {
-			log.Println(err3.Error())
-			return CouponCacheDataRecoveryError
-		}
+// recoverCouponData 캠페인 생성 중 재고 준비에 실패하면 저장한 캠페인과 캐싱 데이터를 삭제한다
+// cause 는 재고 준비에 실패한 원인이며 반환하는 에러에 담는다
+func (c *CouponService) recoverCouponData(ctx context.Context, coupon *domain.Coupon, cause error) error {
+	if err := c.couponRepository.Delete(coupon.ID); err != nil {
+		log.Println(err.Error())
+		return CouponDataRecoveryError.Wrap(cause)
+	}
+	if c.cache == nil {
+		return CouponCacheError.Wrap(cause)
+	}
 
-		return CouponCacheError
+	if err := c.cache.Del(ctx, genCouponDataKey(coupon.ID)); err != nil {
+		log.Println(err.Error())
+		return CouponCacheDataRecoveryError.Wrap(cause)
 	}
-	return nil
+	c.invalidateCouponData(ctx, coupon.ID)
+
+	return CouponCacheError.Wrap(cause)
 }
 
+// genCouponDataKey 캠페인 ID 를 해시 태그로 사용해 한 캠페인의 키가 클러스터의 같은 슬롯에 위치하도록 한다
 func genCouponDataKey(couponID string) string {
-	return fmt.Sprintf("coupon:%s:data", couponID)
+	return fmt.Sprintf("coupon:{%s}:data", couponID)
+}
+
+func genCouponCodePoolKey(couponID string) string {
+	return fmt.Sprintf("coupon:{%s}:codes", couponID)
+}
+
+func genCouponUserKey(couponID string) string {
+	return fmt.Sprintf("coupon:{%s}:users", couponID)
 }
 
 func genCouponAmountKey(couponID string) string {
-	return fmt.Sprintf("coupon:%s:remaining", couponID)
+	return fmt.Sprintf("coupon:{%s}:remaining", couponID)
+}
+
+// genCouponShardKey 하위 카운터는 부하 분산이 목적이므로 예외적으로 샤드마다 다른 해시 태그를 사용해
+// 클러스터의 여러 슬롯으로 분산시킨다
+func genCouponShardKey(couponID string, shard int) string {
+	return fmt.Sprintf("coupon:{%s:%d}:remaining", couponID, shard)
+}
+
+func genCouponStockKeys(coupon *domain.Coupon) []string {
+	if !coupon.IsSharded() {
+		return []string{genCouponAmountKey(coupon.ID)}
+	}
+
+	keys := make([]string, coupon.StockShards)
+	for i := range keys {
+		keys[i] = genCouponShardKey(coupon.ID, i)
+	}
+	return keys
 }
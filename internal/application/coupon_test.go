@@ -3,12 +3,14 @@ package application
 import (
 	"context"
 	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/cache"
 	"coupon-service/internal/infrastructure/entity"
 	"coupon-service/internal/infrastructure/repository"
 	"coupon-service/internal/test"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"sync"
@@ -20,7 +22,7 @@ func TestCouponIssueConcurrencyWithContainer(t *testing.T) {
 	redisContainer, ctx := test.SetupRedisForTest(t)
 	mysqlContainer, ctx := test.SetupMySQLForTest(t)
 	couponID := uuid.New().String()
-	userStoreKey := "coupon:" + couponID + ":users"
+	userStoreKey := genCouponUserKey(couponID)
 	userID := uuid.New().String()
 	couponService := NewCouponService(
 		redisContainer.Client,
@@ -28,7 +30,7 @@ func TestCouponIssueConcurrencyWithContainer(t *testing.T) {
 		repository.NewIssuedCouponRepository(mysqlContainer.DB),
 	)
 
-	mysqlContainer.MigrateEntities(&entity.IssuedCouponEntity{})
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
 
 	t.Run("동일 사용자 중복 요청 시 false와 에러가 반환 되어야 함", func(t *testing.T) {
 		initCache(t, redisContainer, ctx, couponID, 10)
@@ -42,6 +44,8 @@ func TestCouponIssueConcurrencyWithContainer(t *testing.T) {
 
 	t.Run("동일 사용자 중복 요청 시 한개의 쿠폰만 소진 되어야 한다", func(t *testing.T) {
 		initCache(t, redisContainer, ctx, couponID, 10)
+		// 이전 테스트에서 발급된 사용자는 DB 의 (coupon_id, user_id) 유니크 제약에 걸리므로 새로운 사용자로 요청한다
+		userID := uuid.New().String()
 
 		_ = couponService.IssueCoupon(ctx, couponID, userID)
 		err := couponService.IssueCoupon(ctx, couponID, userID)
@@ -132,7 +136,7 @@ func TestCouponIssueWithContainer(t *testing.T) {
 		repository.NewIssuedCouponRepository(mysqlContainer.DB),
 	)
 
-	mysqlContainer.MigrateEntities(&entity.IssuedCouponEntity{})
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
 
 	t.Run("존재하지 않은 쿠폰 발급 요청 시 에러가 발생한다", func(t *testing.T) {
 		initCache(t, redisContainer, ctx, couponID, 10)
@@ -157,6 +161,7 @@ func TestCouponIssueWithContainer(t *testing.T) {
 
 		initCache(t, redisContainer, ctx, couponID, 10)
 		createCouponCache(ctx, redisContainer, couponID, coupon)
+		couponService.coupons.Purge()
 
 		err := couponService.IssueCoupon(ctx, couponID, userID)
 
@@ -178,6 +183,7 @@ func TestCouponIssueWithContainer(t *testing.T) {
 
 		initCache(t, redisContainer, ctx, couponID, 10)
 		createCouponCache(ctx, redisContainer, couponID, coupon)
+		couponService.coupons.Purge()
 
 		err := couponService.IssueCoupon(ctx, couponID, userID)
 
@@ -187,6 +193,7 @@ func TestCouponIssueWithContainer(t *testing.T) {
 
 	t.Run("쿠폰 발급 후 발급된 쿠폰이 정상적으로 조회 되어야 한다", func(t *testing.T) {
 		initCache(t, redisContainer, ctx, couponID, 10)
+		couponService.coupons.Purge()
 
 		_ = couponService.IssueCoupon(ctx, couponID, userID)
 
@@ -195,6 +202,371 @@ func TestCouponIssueWithContainer(t *testing.T) {
 	})
 }
 
+func TestShardedCouponIssueWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	couponService := NewCouponService(
+		redisContainer.Client,
+		repository.NewCouponRepository(mysqlContainer.DB),
+		repository.NewIssuedCouponRepository(mysqlContainer.DB),
+	)
+
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+
+	t.Run("샤딩된 재고 카운터는 발행량을 하위 카운터에 나누어 저장해야 한다", func(t *testing.T) {
+		now := time.Now()
+		coupon, err := couponService.CreateCoupon(
+			ctx,
+			"샤딩 쿠폰 테스트",
+			10,
+			now.Add(time.Duration(-5)*time.Hour),
+			now.Add(time.Duration(5)*time.Hour),
+			domain.WithStockShards(4),
+		)
+		require.NoError(t, err)
+
+		var total int64
+		for i := 0; i < 4; i++ {
+			amount, err2 := redisContainer.Client.Get(ctx, genCouponShardKey(coupon.ID, i)).Int64()
+			assert.NoError(t, err2)
+			total += amount
+		}
+		assert.Equal(t, coupon.IssueAmount, total)
+	})
+
+	t.Run("샤딩된 캠페인에 100명의 유저가 요청하면 발행량만큼만 발급되고 남은 재고는 0이어야 한다", func(t *testing.T) {
+		now := time.Now()
+		coupon, err := couponService.CreateCoupon(
+			ctx,
+			"샤딩 쿠폰 테스트",
+			10,
+			now.Add(time.Duration(-5)*time.Hour),
+			now.Add(time.Duration(5)*time.Hour),
+			domain.WithStockShards(4),
+		)
+		require.NoError(t, err)
+
+		_, successCount := addIssuedCouponsByUserCount(100, couponService, ctx, coupon.ID)
+		assert.Equal(t, int(coupon.IssueAmount), successCount)
+
+		remaining, err2 := couponService.GetRemainingStock(ctx, coupon.ID)
+		assert.NoError(t, err2)
+		assert.Equal(t, int64(0), remaining)
+	})
+}
+
+func TestCouponIssueWithoutCacheWithContainer(t *testing.T) {
+	mysqlContainer, _ := test.SetupMySQLForTest(t)
+	ctx := context.Background()
+	couponRepository := repository.NewCouponRepository(mysqlContainer.DB)
+	unreachableCache := redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1})
+	breakerConfig := cache.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute}
+
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.DegradedIssuanceEntity{})
+
+	now := time.Now()
+	coupon, err := domain.NewCoupon(
+		"DB 발급 테스트",
+		5,
+		now.Add(time.Duration(-5)*time.Hour),
+		now.Add(time.Duration(5)*time.Hour),
+	)
+	require.NoError(t, err)
+	require.NoError(t, couponRepository.Save(coupon))
+
+	t.Run("Redis 를 사용할 수 없고 DB fallback 이 비활성화되어 있으면 재시도 가능한 에러가 반환되어야 한다", func(t *testing.T) {
+		couponService := NewCouponService(
+			unreachableCache,
+			couponRepository,
+			repository.NewIssuedCouponRepository(mysqlContainer.DB),
+			WithCacheCircuitBreaker(breakerConfig),
+		)
+
+		err := couponService.IssueCoupon(ctx, coupon.ID, uuid.New().String())
+
+		assert.ErrorIs(t, err, CacheUnavailableError)
+	})
+
+	t.Run("Redis 를 사용할 수 없으면 DB 만으로 발행량만큼 발급되어야 한다", func(t *testing.T) {
+		couponService := NewCouponService(
+			unreachableCache,
+			couponRepository,
+			repository.NewIssuedCouponRepository(mysqlContainer.DB),
+			WithCacheCircuitBreaker(breakerConfig),
+			WithDBFallback(),
+		)
+
+		_, successCount := addIssuedCouponsByUserCount(50, couponService, ctx, coupon.ID)
+		assert.Equal(t, int(coupon.IssueAmount), successCount)
+
+		issuedCoupons := repository.NewIssuedCouponRepository(mysqlContainer.DB).FindByCouponId(coupon.ID)
+		assert.Equal(t, int(coupon.IssueAmount), len(issuedCoupons))
+	})
+
+	t.Run("Redis 를 사용할 수 없는 동안에도 동일 사용자 중복 발급이 차단되어야 한다", func(t *testing.T) {
+		other, err := domain.NewCoupon(
+			"DB 발급 테스트",
+			5,
+			now.Add(time.Duration(-5)*time.Hour),
+			now.Add(time.Duration(5)*time.Hour),
+		)
+		require.NoError(t, err)
+		require.NoError(t, couponRepository.Save(other))
+		couponService := NewCouponService(
+			unreachableCache,
+			couponRepository,
+			repository.NewIssuedCouponRepository(mysqlContainer.DB),
+			WithCacheCircuitBreaker(breakerConfig),
+			WithDBFallback(),
+		)
+
+		const userID = "degraded-user-1"
+		_ = couponService.IssueCoupon(ctx, other.ID, userID)
+		err = couponService.IssueCoupon(ctx, other.ID, userID)
+
+		assert.ErrorIs(t, err, DuplicatedCouponUserError)
+	})
+}
+
+func TestDegradedIssuanceSyncWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	unreachableCache := redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1})
+	breakerConfig := cache.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute}
+
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.DegradedIssuanceEntity{})
+
+	couponRepository := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepository := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	// 같은 캠페인을 Redis 장애를 겪는 인스턴스와 Redis 를 사용할 수 있는 인스턴스가 함께 발급한다
+	degradedService := NewCouponService(
+		unreachableCache,
+		couponRepository,
+		issuedCouponRepository,
+		WithCacheCircuitBreaker(breakerConfig),
+		WithDBFallback(),
+	)
+	healthyService := NewCouponService(redisContainer.Client, couponRepository, issuedCouponRepository)
+
+	degradedAt := func(t *testing.T, couponId string) *time.Time {
+		var couponEntity entity.CouponEntity
+		require.NoError(t, mysqlContainer.DB.Where("id = ?", couponId).First(&couponEntity).Error)
+		return couponEntity.DegradedAt
+	}
+	now := time.Now()
+
+	t.Run("DB 로 발급된 내역이 반영되기 전에는 Redis 를 사용할 수 있는 인스턴스도 발행량을 넘지 않아야 한다", func(t *testing.T) {
+		coupon, err := healthyService.CreateCoupon(ctx, "DB 발급 반영 테스트", 5, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+
+		_, degradedCount := addIssuedCouponsByUserCount(3, degradedService, ctx, coupon.ID)
+		require.Equal(t, 3, degradedCount)
+		assert.NotNil(t, degradedAt(t, coupon.ID))
+
+		_, healthyCount := addIssuedCouponsByUserCount(10, healthyService, ctx, coupon.ID)
+
+		assert.Equal(t, 2, healthyCount)
+		assert.Len(t, issuedCouponRepository.FindByCouponId(coupon.ID), 5)
+	})
+
+	t.Run("반영이 끝나면 Redis 의 재고와 사용자 집합이 DB 와 같아지고 Redis 로 발급해야 한다", func(t *testing.T) {
+		coupon, err := healthyService.CreateCoupon(ctx, "DB 발급 반영 테스트", 5, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		const userID = "degraded-sync-user"
+		require.NoError(t, degradedService.IssueCoupon(ctx, coupon.ID, userID))
+		require.NoError(t, degradedService.IssueCoupon(ctx, coupon.ID, uuid.New().String()))
+
+		count, err := healthyService.SyncDegradedIssuances(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Nil(t, degradedAt(t, coupon.ID))
+		remaining, err := redisContainer.Client.Get(ctx, genCouponAmountKey(coupon.ID)).Int64()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), remaining)
+		assert.ErrorIs(t, healthyService.IssueCoupon(ctx, coupon.ID, userID), DuplicatedCouponUserError)
+
+		count, err = healthyService.SyncDegradedIssuances(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count, "반영한 내역은 다시 반영하지 않아야 한다")
+	})
+
+	t.Run("Redis 의 재고가 반영할 내역보다 적으면 초과 발급 에러를 반환하고 DB 기준 발급을 유지해야 한다", func(t *testing.T) {
+		coupon, err := healthyService.CreateCoupon(ctx, "DB 발급 반영 테스트", 2, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, degradedService.IssueCoupon(ctx, coupon.ID, uuid.New().String()))
+		require.NoError(t, degradedService.IssueCoupon(ctx, coupon.ID, uuid.New().String()))
+		require.NoError(t, redisContainer.Client.Set(ctx, genCouponAmountKey(coupon.ID), 1, 0).Err())
+
+		count, err := healthyService.SyncDegradedIssuances(ctx)
+
+		assert.ErrorIs(t, err, DegradedIssuanceOversoldError)
+		assert.Equal(t, 1, count)
+		assert.NotNil(t, degradedAt(t, coupon.ID))
+		assert.ErrorIs(t, healthyService.IssueCoupon(ctx, coupon.ID, uuid.New().String()), AllCouponIssuedError)
+	})
+}
+
+func TestIssuanceStrategiesWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+
+	strategies := map[IssuanceStrategyType]redis.UniversalClient{
+		RedisIssuance:  redisContainer.Client,
+		MySQLIssuance:  nil,
+		HybridIssuance: redisContainer.Client,
+	}
+
+	for strategyType, cacheClient := range strategies {
+		couponService := NewCouponService(
+			cacheClient,
+			repository.NewCouponRepository(mysqlContainer.DB),
+			repository.NewIssuedCouponRepository(mysqlContainer.DB),
+			WithIssuanceStrategy(strategyType),
+		)
+
+		t.Run(fmt.Sprintf("%s 전략으로 100명의 유저가 요청하면 발행량만큼만 발급되어야 한다", strategyType), func(t *testing.T) {
+			now := time.Now()
+			coupon, err := couponService.CreateCoupon(
+				ctx,
+				"전략 테스트",
+				10,
+				now.Add(time.Duration(-5)*time.Hour),
+				now.Add(time.Duration(5)*time.Hour),
+			)
+			require.NoError(t, err)
+
+			_, successCount := addIssuedCouponsByUserCount(100, couponService, ctx, coupon.ID)
+			assert.Equal(t, int(coupon.IssueAmount), successCount)
+
+			issuedCoupons := repository.NewIssuedCouponRepository(mysqlContainer.DB).FindByCouponId(coupon.ID)
+			assert.Equal(t, int(coupon.IssueAmount), len(issuedCoupons))
+
+			remaining, err2 := couponService.GetRemainingStock(ctx, coupon.ID)
+			assert.NoError(t, err2)
+			assert.Equal(t, int64(0), remaining)
+		})
+
+		t.Run(fmt.Sprintf("%s 전략으로 동일 사용자가 중복 요청하면 에러가 반환되어야 한다", strategyType), func(t *testing.T) {
+			now := time.Now()
+			coupon, err := couponService.CreateCoupon(
+				ctx,
+				"전략 테스트",
+				10,
+				now.Add(time.Duration(-5)*time.Hour),
+				now.Add(time.Duration(5)*time.Hour),
+			)
+			require.NoError(t, err)
+
+			const userID = "strategy-user-1"
+			_ = couponService.IssueCoupon(ctx, coupon.ID, userID)
+			err2 := couponService.IssueCoupon(ctx, coupon.ID, userID)
+
+			assert.ErrorIs(t, err2, DuplicatedCouponUserError)
+			remaining, err3 := couponService.GetRemainingStock(ctx, coupon.ID)
+			assert.NoError(t, err3)
+			assert.Equal(t, coupon.IssueAmount-1, remaining)
+		})
+
+		t.Run(fmt.Sprintf("%s 전략으로 동일 사용자가 동시에 요청하면 사용자당 횟수까지 발급되어야 한다", strategyType), func(t *testing.T) {
+			now := time.Now()
+			code := "SAME-USER-" + string(strategyType)
+			coupon, err := couponService.CreateCoupon(
+				ctx,
+				"전략 테스트",
+				10,
+				now.Add(time.Duration(-5)*time.Hour),
+				now.Add(time.Duration(5)*time.Hour),
+				domain.WithSharedCode(code, 3),
+			)
+			require.NoError(t, err)
+
+			const userID = "strategy-user-2"
+			errs := runConcurrently(6, func() error {
+				return couponService.IssueCoupon(ctx, code, userID)
+			})
+
+			var successCount int
+			for _, err2 := range errs {
+				if err2 == nil {
+					successCount++
+					continue
+				}
+				assert.ErrorIs(t, err2, DuplicatedCouponUserError)
+			}
+			assert.Equal(t, 3, successCount)
+			assert.Len(t, repository.NewIssuedCouponRepository(mysqlContainer.DB).FindByCouponId(coupon.ID), 3)
+			remaining, err3 := couponService.GetRemainingStock(ctx, coupon.ID)
+			assert.NoError(t, err3)
+			assert.Equal(t, coupon.IssueAmount-3, remaining)
+		})
+	}
+}
+
+// runConcurrently fn 을 n 개의 고루틴에서 동시에 실행하고 각 실행의 에러를 반환한다
+func runConcurrently(n int, fn func() error) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn()
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// BenchmarkIssueCouponStrategies 전략별 발급 처리량 비교
+//
+//	go test ./internal/application/ -run '^$' -bench IssueCouponStrategies
+func BenchmarkIssueCouponStrategies(b *testing.B) {
+	redisContainer, ctx := test.SetupRedisForTest(b)
+	mysqlContainer, ctx := test.SetupMySQLForTest(b)
+
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+
+	strategies := []struct {
+		strategyType IssuanceStrategyType
+		cacheClient  redis.UniversalClient
+	}{
+		{RedisIssuance, redisContainer.Client},
+		{MySQLIssuance, nil},
+		{HybridIssuance, redisContainer.Client},
+	}
+
+	for _, strategy := range strategies {
+		couponService := NewCouponService(
+			strategy.cacheClient,
+			repository.NewCouponRepository(mysqlContainer.DB),
+			repository.NewIssuedCouponRepository(mysqlContainer.DB),
+			WithIssuanceStrategy(strategy.strategyType),
+		)
+
+		b.Run(string(strategy.strategyType), func(b *testing.B) {
+			now := time.Now()
+			coupon, err := couponService.CreateCoupon(
+				ctx,
+				"벤치마크",
+				int64(b.N),
+				now.Add(time.Duration(-5)*time.Hour),
+				now.Add(time.Duration(5)*time.Hour),
+			)
+			require.NoError(b, err)
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = couponService.IssueCoupon(ctx, coupon.ID, uuid.New().String())
+				}
+			})
+		})
+	}
+}
+
 func TestCreateCouponWithContainer(t *testing.T) {
 	redisContainer, ctx := test.SetupRedisForTest(t)
 	mysqlContainer, ctx := test.SetupMySQLForTest(t)
@@ -219,6 +591,22 @@ func TestCreateCouponWithContainer(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("발행 수량이 0 이하이거나 만료 일시가 시작 일시보다 앞서면 검증 에러가 반환되어야 한다", func(t *testing.T) {
+		now := time.Now()
+		_, err := couponService.CreateCoupon(
+			ctx,
+			"쿠폰발급 테스트",
+			0,
+			now.Add(time.Duration(5)*time.Hour),
+			now.Add(time.Duration(-5)*time.Hour),
+		)
+
+		assert.ErrorIs(t, err, InvalidRequestError)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Len(t, validationErr.Violations, 2)
+	})
+
 	t.Run("쿠폰 생성 후 캐싱 데이터가 조회 되어야 한다", func(t *testing.T) {
 		now := time.Now()
 		data, _ := couponService.CreateCoupon(
@@ -302,7 +690,7 @@ func TestGetCouponWithContainer(t *testing.T) {
 	t.Run("존재하지 않는 ID로 쿠폰 조회 요청 시 쿠폰을 찾을 수 없다는 에러가 발생한다", func(t *testing.T) {
 		_, err := couponService.GetCoupon(uuid.New().String())
 
-		assert.Equal(t, err, CouponNotFoundError)
+		assert.ErrorIs(t, err, CouponNotFoundError)
 	})
 }
 
@@ -340,16 +728,16 @@ func createCouponCache(
 	data domain.Coupon,
 ) {
 	jsonData, err := json.Marshal(data)
-	err = redisContainer.Client.Set(ctx, "coupon:"+couponId+":data", jsonData, 0).Err()
+	err = redisContainer.Client.Set(ctx, genCouponCacheKey(couponId), jsonData, 0).Err()
 	if err != nil {
 		fmt.Println(err)
 	}
 }
 
 func genCouponIdKey(couponID string) string {
-	return fmt.Sprintf("coupon:%s:remaining", couponID)
+	return fmt.Sprintf("coupon:{%s}:remaining", couponID)
 }
 
 func genCouponCacheKey(couponID string) string {
-	return fmt.Sprintf("coupon:%s:data", couponID)
+	return fmt.Sprintf("coupon:{%s}:data", couponID)
 }
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"log"
+	"time"
+)
+
+const (
+	// degradedSyncInterval Redis 장애 중 DB 로 발급된 내역을 Redis 에 반영하는 간격
+	degradedSyncInterval = time.Second
+	// degradedSyncBatchSize 한 번의 실행에서 반영하는 캠페인 수
+	degradedSyncBatchSize = 100
+)
+
+// RunDegradedIssuanceSync Redis 장애 중 DB 로 발급된 내역을 설정한 간격마다 Redis 에 반영한다
+// RedisIssuance 전략이 아니면 실행하지 않으며, ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) RunDegradedIssuanceSync(ctx context.Context) {
+	if _, ok := c.strategy.(*redisIssuanceStrategy); !ok {
+		return
+	}
+	ticker := time.NewTicker(degradedSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := c.SyncDegradedIssuances(ctx)
+			if err != nil {
+				log.Println(err.Error())
+			}
+			if count > 0 {
+				log.Printf("degraded issuance sync synced=%d", count)
+			}
+		}
+	}
+}
+
+// SyncDegradedIssuances Redis 장애 중 DB 로 발급되어 MySQL 에 남긴 내역을 사용자 집합과 재고 카운터에 반영하고 반영한 수를 반환한다
+// 모든 내역을 반영한 캠페인은 다시 Redis 의 재고 카운터로 발급하며, 반영하지 못한 캠페인은 계속 DB 기준으로 발급한다
+// Redis 의 재고가 DB 발급 내역보다 적으면 DegradedIssuanceOversoldError 를 반환한다
+func (c *CouponService) SyncDegradedIssuances(ctx context.Context) (int, error) {
+	strategy, ok := c.strategy.(*redisIssuanceStrategy)
+	if !ok {
+		return 0, nil
+	}
+
+	coupons := make(map[string]*domain.Coupon)
+	return c.issuedCouponRepository.SyncDegradedIssuances(degradedSyncBatchSize, func(issued domain.IssuedCoupon) error {
+		coupon, ok := coupons[issued.CouponID]
+		if !ok {
+			var err error
+			if coupon, err = c.loadCouponDataFromDB(issued.CouponID); err != nil {
+				return err
+			}
+			coupons[issued.CouponID] = coupon
+		}
+		return strategy.syncDegradedIssuance(ctx, coupon, &issued)
+	})
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"fmt"
+	"log"
+	"time"
+)
+
+// WithAccountClaims 발급 요청의 가입 일시를 인증 토큰의 클레임으로 받는다
+// 지정하지 않으면 가입 일시를 알 수 없으므로 신규 사용자 조건을 사용하는 캠페인을 만들 수 없다
+func WithAccountClaims() Option {
+	return func(c *CouponService) {
+		c.accountClaims = true
+	}
+}
+
+// checkEligibilitySupported 확인에 필요한 사용자 정보를 받을 수 없는 조건이면 검증 에러를 반환한다
+// 그대로 만들면 모든 요청이 대상이 아닌 것으로 판단되어 발급되지 않는다
+func (c *CouponService) checkEligibilitySupported(rules domain.EligibilityRules) error {
+	if rules.NewUsersOnly && !c.accountClaims {
+		return InvalidRequestError.Wrap(&domain.ValidationError{Violations: []domain.FieldViolation{{
+			Field:       "eligibility.new_users_only",
+			Description: "requires authentication that provides the account_created_at claim",
+		}}})
+	}
+	return nil
+}
+
+// checkEligibility 캠페인의 발급 대상 조건을 확인한다
+// 사유는 차단 목록에 있는지 등을 드러내므로 응답하지 않고 원인 에러로만 남긴다
+// 사용자 목록을 확인할 수 없으면 대상이 아닌 사용자에게 발급되지 않도록 재시도 가능한 에러를 반환한다
+func (c *CouponService) checkEligibility(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	userId string,
+	req IssueRequest,
+	now time.Time,
+) error {
+	rules := coupon.Eligibility
+	eligibility := domain.Eligibility{
+		Segments:         req.Segments,
+		Region:           req.Region,
+		AccountCreatedAt: req.AccountCreatedAt,
+		RequestedAt:      now,
+	}
+	if rules.HasUserList() {
+		if c.cache == nil {
+			return CacheUnavailableError
+		}
+		inList, err := c.cache.SetIsMember(ctx, genCouponUserListKey(coupon.ID), userId)
+		if err != nil {
+			return EligibilityLookupError.Wrap(err)
+		}
+		eligibility.InUserList = inList
+	}
+
+	if err := rules.Check(eligibility); err != nil {
+		return IneligibleUserError.Wrap(err)
+	}
+	return nil
+}
+
+// AddCampaignUsers 캠페인의 사용자 목록에 사용자를 추가한다. 목록은 캠페인 종료 시 만료된다
+func (c *CouponService) AddCampaignUsers(ctx context.Context, couponId string, userIds ...string) error {
+	if c.cache == nil {
+		return CacheUnavailableError
+	}
+	coupon, err := c.loadCouponData(ctx, couponId)
+	if err != nil {
+		return err
+	}
+
+	key := genCouponUserListKey(couponId)
+	for _, userId := range userIds {
+		if _, err2 := c.cache.SetAdd(ctx, key, userId); err2 != nil {
+			return CampaignUserListUpdateError.Wrap(err2)
+		}
+	}
+	if _, err = c.cache.ExpireAt(ctx, key, coupon.ExpiresAt); err != nil {
+		log.Println(err.Error())
+	}
+	log.Printf("campaign user list add coupon=%s users=%d", couponId, len(userIds))
+	return nil
+}
+
+// RemoveCampaignUsers 캠페인의 사용자 목록에서 사용자를 제거한다
+func (c *CouponService) RemoveCampaignUsers(ctx context.Context, couponId string, userIds ...string) error {
+	if c.cache == nil {
+		return CacheUnavailableError
+	}
+	key := genCouponUserListKey(couponId)
+	for _, userId := range userIds {
+		if _, err := c.cache.SetDel(ctx, key, userId); err != nil {
+			return CampaignUserListUpdateError.Wrap(err)
+		}
+	}
+	log.Printf("campaign user list remove coupon=%s users=%d", couponId, len(userIds))
+	return nil
+}
+
+// ListCampaignUsers 캠페인의 사용자 목록
+func (c *CouponService) ListCampaignUsers(ctx context.Context, couponId string) ([]string, error) {
+	if c.cache == nil {
+		return nil, CacheUnavailableError
+	}
+	users, err := c.cache.SetMembers(ctx, genCouponUserListKey(couponId))
+	if err != nil {
+		return nil, CampaignUserListLookupError.Wrap(err)
+	}
+	return users, nil
+}
+
+func genCouponUserListKey(couponID string) string {
+	return fmt.Sprintf("coupon:{%s}:userlist", couponID)
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCheckEligibility(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	couponService := NewCouponService(nil, nil, nil, WithIssuanceStrategy(MySQLIssuance))
+
+	t.Run("조건을 만족하지 않으면 IneligibleUserError 를 반환해야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), Eligibility: domain.EligibilityRules{
+			Segments: []string{"vip"}, Regions: []string{"KR"},
+		}}
+
+		assert.NoError(t, couponService.checkEligibility(ctx, coupon, "user", IssueRequest{
+			Segments: []string{"vip"}, Region: "KR",
+		}, now))
+		assert.ErrorIs(t, couponService.checkEligibility(ctx, coupon, "user", IssueRequest{
+			Segments: []string{"basic"}, Region: "KR",
+		}, now), IneligibleUserError)
+	})
+
+	t.Run("사용자 목록을 확인할 수 없으면 발급하지 않아야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), Eligibility: domain.EligibilityRules{
+			UserListMode: domain.UserListDeny,
+		}}
+
+		assert.ErrorIs(t, couponService.checkEligibility(ctx, coupon, "user", IssueRequest{}, now), CacheUnavailableError)
+	})
+}
+
+func TestCheckEligibilitySupported(t *testing.T) {
+	rules := domain.EligibilityRules{NewUsersOnly: true}
+
+	t.Run("가입 일시를 받을 수 없으면 신규 사용자 조건은 검증 에러를 반환해야 한다", func(t *testing.T) {
+		couponService := NewCouponService(nil, nil, nil, WithIssuanceStrategy(MySQLIssuance))
+
+		err := couponService.checkEligibilitySupported(rules)
+
+		assert.ErrorIs(t, err, InvalidRequestError)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "eligibility.new_users_only", validationErr.Violations[0].Field)
+	})
+
+	t.Run("인증 토큰으로 가입 일시를 받으면 신규 사용자 조건을 허용해야 한다", func(t *testing.T) {
+		couponService := NewCouponService(nil, nil, nil, WithIssuanceStrategy(MySQLIssuance), WithAccountClaims())
+
+		assert.NoError(t, couponService.checkEligibilitySupported(rules))
+	})
+}
+
+func TestEligibilityWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponService := NewCouponService(
+		redisContainer.Client,
+		repository.NewCouponRepository(mysqlContainer.DB),
+		repository.NewIssuedCouponRepository(mysqlContainer.DB),
+		WithAccountClaims(),
+	)
+	now := time.Now()
+
+	t.Run("허용 목록에 추가된 사용자만 발급받을 수 있어야 한다", func(t *testing.T) {
+		coupon, err := couponService.CreateCoupon(ctx, "허용 목록", 100, now.Add(-time.Hour), now.Add(time.Hour),
+			domain.WithEligibility(domain.EligibilityRules{UserListMode: domain.UserListAllow}),
+		)
+		require.NoError(t, err)
+		allowed, denied := uuid.New().String(), uuid.New().String()
+		require.NoError(t, couponService.AddCampaignUsers(ctx, coupon.ID, allowed))
+
+		users, err := couponService.ListCampaignUsers(ctx, coupon.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{allowed}, users)
+		assert.NoError(t, couponService.IssueCoupon(ctx, coupon.ID, allowed))
+		assert.ErrorIs(t, couponService.IssueCoupon(ctx, coupon.ID, denied), IneligibleUserError)
+
+		remaining, err := couponService.GetRemainingStock(ctx, coupon.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(99), remaining)
+	})
+
+	t.Run("발급 대상 조건은 저장 후 다시 조회해도 유지되어야 한다", func(t *testing.T) {
+		rules := domain.EligibilityRules{NewUsersOnly: true, Regions: []string{"KR"}}
+		coupon, err := couponService.CreateCoupon(ctx, "신규 가입", 100, now.Add(-time.Hour), now.Add(time.Hour),
+			domain.WithEligibility(rules),
+		)
+		require.NoError(t, err)
+
+		found, err := couponService.GetCoupon(coupon.ID)
+		require.NoError(t, err)
+		assert.Equal(t, rules, found.Eligibility)
+		assert.ErrorIs(t, couponService.IssueCoupon(ctx, coupon.ID, uuid.New().String(),
+			WithRegion("KR"), WithAccountCreatedAt(now.Add(-60*24*time.Hour)),
+		), IneligibleUserError)
+		assert.NoError(t, couponService.IssueCoupon(ctx, coupon.ID, uuid.New().String(),
+			WithRegion("KR"), WithAccountCreatedAt(now.Add(-time.Hour)),
+		))
+	})
+}
@@ -0,0 +1,141 @@
+package application
+
+// ErrorKind 에러의 분류. API 레이어는 이 값으로 응답 코드를 결정한다
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindInvalidArgument
+	KindFailedPrecondition
+	KindAlreadyExists
+	KindResourceExhausted
+	KindUnavailable
+	KindPermissionDenied
+)
+
+// Error 애플리케이션 레이어가 반환하는 에러
+//   - Code: 클라이언트가 분기 처리에 사용할 수 있는 고정된 식별자
+//   - Message: 사용자에게 노출해도 되는 메시지
+//   - Retryable: 같은 요청을 다시 시도하면 성공할 수 있는지 여부
+//   - Cause: 원인 에러. 사용자에게 노출하지 않는다
+//
+// errors.Is 는 Code 로 비교하므로 Wrap 으로 원인을 담은 에러도 원래의 에러와 일치한다
+type Error struct {
+	Code      string
+	Kind      ErrorKind
+	Message   string
+	Retryable bool
+	Cause     error
+}
+
+func newError(code string, kind ErrorKind, message string, retryable bool) *Error {
+	return &Error{Code: code, Kind: kind, Message: message, Retryable: retryable}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && e.Code == t.Code
+}
+
+// Wrap 원인 에러를 담은 복사본을 반환한다
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Cause = cause
+	return &wrapped
+}
+
+var (
+	// 요청 검증. InvalidRequestError 는 원인으로 위반한 필드 목록(domain.ValidationError)을 담는다
+	InvalidRequestError = newError("INVALID_REQUEST", KindInvalidArgument, "invalid request", false)
+
+	// 발급
+	ValidateJsonUnmarshalError = newError("COUPON_DATA_DECODE_FAILED", KindInternal, "failed to decode coupon data", false)
+	CouponNotStartedError      = newError("COUPON_NOT_STARTED", KindFailedPrecondition, "coupon issuance has not started yet", false)
+	CouponExpiredError         = newError("COUPON_EXPIRED", KindFailedPrecondition, "the coupon issuance period has expired", false)
+	DuplicatedCouponUserError  = newError("COUPON_ALREADY_ISSUED", KindAlreadyExists, "coupon already issued to this user", false)
+	CouponAmountRecoveryError  = newError("STOCK_RECOVERY_FAILED", KindInternal, "failed to increment coupon amount for recover", false)
+	DeleteRecoveryError        = newError("USER_RECOVERY_FAILED", KindInternal, "failed to delete coupon for recover", false)
+	AllCouponIssuedError       = newError("COUPON_SOLD_OUT", KindResourceExhausted, "all coupons has been issued", false)
+	CacheAddUserError          = newError("CACHE_ADD_USER_FAILED", KindUnavailable, "failed to add coupon", true)
+	CouponDecrError            = newError("STOCK_DECREMENT_FAILED", KindUnavailable, "failed to decrement coupon", true)
+	DataKeyNotFoundError       = newError("COUPON_DATA_NOT_FOUND", KindNotFound, "data key not found", false)
+	IssuedCouponCreationError  = newError("ISSUED_COUPON_SAVE_FAILED", KindInternal, "failed to create coupon code", true)
+	CacheUnavailableError      = newError("CACHE_UNAVAILABLE", KindUnavailable, "coupon issuance is temporarily unavailable, please retry", true)
+
+	// 캠페인 저장과 캐시
+	FailedSaveCouponError        = newError("COUPON_SAVE_FAILED", KindInternal, "failed to save coupon", true)
+	CouponDataRecoveryError      = newError("COUPON_RECOVERY_FAILED", KindInternal, "failed to recover coupon", false)
+	CouponCacheDataRecoveryError = newError("COUPON_CACHE_RECOVERY_FAILED", KindInternal, "failed to recover coupon caching data", false)
+	CouponCacheError             = newError("COUPON_CACHE_FAILED", KindUnavailable, "failed to cache coupon data", true)
+
+	// 어뷰징 검사
+	AbuseDeniedError     = newError("ABUSE_DENIED", KindPermissionDenied, "coupon issuance is not allowed for this request", false)
+	AbuseChallengeError  = newError("ABUSE_CHALLENGE_REQUIRED", KindFailedPrecondition, "additional verification is required", false)
+	BlocklistUpdateError = newError("BLOCKLIST_UPDATE_FAILED", KindUnavailable, "failed to update blocklist", true)
+	BlocklistLookupError = newError("BLOCKLIST_LOOKUP_FAILED", KindUnavailable, "failed to get blocklist", true)
+
+	// 발급 대상 조건
+	IneligibleUserError         = newError("NOT_ELIGIBLE", KindPermissionDenied, "user is not eligible for this campaign", false)
+	EligibilityLookupError      = newError("ELIGIBILITY_LOOKUP_FAILED", KindUnavailable, "failed to check eligibility, please retry", true)
+	CampaignUserListUpdateError = newError("CAMPAIGN_USER_LIST_UPDATE_FAILED", KindUnavailable, "failed to update campaign user list", true)
+	CampaignUserListLookupError = newError("CAMPAIGN_USER_LIST_LOOKUP_FAILED", KindUnavailable, "failed to get campaign user list", true)
+
+	// 코드 풀
+	CodePoolNotEnabledError = newError("CODE_POOL_NOT_ENABLED", KindFailedPrecondition, "the campaign does not use a code pool", false)
+	CodeImportError         = newError("CODE_IMPORT_FAILED", KindInternal, "failed to import codes", true)
+	CodePoolCacheError      = newError("CODE_POOL_CACHE_FAILED", KindUnavailable, "failed to load codes into the code pool", true)
+
+	// 공용 코드, 쿠폰 사용과 만료
+	SharedCodeConflictError    = newError("SHARED_CODE_ALREADY_EXISTS", KindAlreadyExists, "the shared code is already used by another campaign", false)
+	IssuedCouponNotFoundError  = newError("ISSUED_COUPON_NOT_FOUND", KindNotFound, "issued coupon not found", false)
+	CouponAlreadyRedeemedError = newError("COUPON_ALREADY_REDEEMED", KindFailedPrecondition, "the coupon has already been redeemed", false)
+	CouponNotUsableError       = newError("COUPON_NOT_USABLE", KindFailedPrecondition, "the coupon is outside its validity window", false)
+	RedeemError                = newError("REDEEM_FAILED", KindInternal, "failed to redeem coupon", true)
+	ExpirySweepError           = newError("EXPIRY_SWEEP_FAILED", KindInternal, "failed to sweep expired coupons", true)
+
+	// 캠페인 시작, 이벤트 발행, 일시 중지, 쿠폰 취소
+	CampaignOpenError       = newError("CAMPAIGN_OPEN_FAILED", KindInternal, "failed to publish opened campaigns", true)
+	EventRelayError         = newError("EVENT_RELAY_FAILED", KindInternal, "failed to relay outbox events", true)
+	CampaignPausedError     = newError("CAMPAIGN_PAUSED", KindFailedPrecondition, "the campaign is paused", false)
+	CampaignPauseError      = newError("CAMPAIGN_PAUSE_FAILED", KindInternal, "failed to pause or resume the campaign", true)
+	CouponNotRevocableError = newError("COUPON_NOT_REVOCABLE", KindFailedPrecondition, "only unused coupons can be revoked", false)
+	RevokeError             = newError("REVOKE_FAILED", KindInternal, "failed to revoke coupon", true)
+
+	// 웹훅
+	WebhooksDisabledError            = newError("WEBHOOKS_DISABLED", KindFailedPrecondition, "webhooks are not enabled", false)
+	WebhookSubscriptionNotFoundError = newError("WEBHOOK_SUBSCRIPTION_NOT_FOUND", KindNotFound, "webhook subscription not found", false)
+	WebhookDeliveryNotFoundError     = newError("WEBHOOK_DELIVERY_NOT_FOUND", KindNotFound, "webhook delivery not found", false)
+	WebhookDeliveryNotRetryableError = newError("WEBHOOK_DELIVERY_NOT_RETRYABLE", KindFailedPrecondition, "only dead-lettered deliveries can be retried", false)
+	WebhookUpdateError               = newError("WEBHOOK_UPDATE_FAILED", KindInternal, "failed to update webhook", true)
+	WebhookLookupError               = newError("WEBHOOK_LOOKUP_FAILED", KindInternal, "failed to get webhooks", true)
+	WebhookDispatchError             = newError("WEBHOOK_DISPATCH_FAILED", KindInternal, "failed to dispatch webhooks", true)
+
+	// 캠페인 조회와 발급 방식
+	CouponNotFoundError           = newError("COUPON_NOT_FOUND", KindNotFound, "coupon not found", false)
+	CouponLookupError             = newError("COUPON_LOOKUP_FAILED", KindInternal, "failed to find coupon", true)
+	RemainingStockError           = newError("REMAINING_STOCK_LOOKUP_FAILED", KindUnavailable, "failed to get remaining stock", true)
+	IssuanceStrategyMismatchError = newError("ISSUANCE_STRATEGY_MISMATCH", KindFailedPrecondition, "the campaign was created with a different issuance strategy", false)
+
+	// 캠페인 발급 가능 여부 조회
+	AvailabilityUnavailableError = newError("AVAILABILITY_UNAVAILABLE", KindUnavailable, "campaign availability is temporarily unavailable, please retry", true)
+
+	// 사용자 발급 여부 조회
+	ClaimStatusLookupError = newError("CLAIM_STATUS_LOOKUP_FAILED", KindInternal, "failed to get claim status", true)
+
+	// 서버 종료
+	ServiceShuttingDownError = newError("SERVICE_SHUTTING_DOWN", KindUnavailable, "the service is shutting down, please retry", true)
+
+	// Redis 장애 중 DB 로 발급한 내역의 동기화
+	DegradedIssuanceSyncError     = newError("DEGRADED_ISSUANCE_SYNC_FAILED", KindUnavailable, "failed to sync degraded issuances to cache", true)
+	DegradedIssuanceOversoldError = newError("DEGRADED_ISSUANCE_OVERSOLD", KindInternal, "degraded issuances exceed the remaining stock in cache", false)
+)
@@ -0,0 +1,32 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrorWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	t.Run("감싼 에러는 원래 에러와 일치하고 원인 에러를 유지해야 한다", func(t *testing.T) {
+		err := fmt.Errorf("issue coupon: %w", CacheUnavailableError.Wrap(cause))
+
+		assert.ErrorIs(t, err, CacheUnavailableError)
+		assert.ErrorIs(t, err, cause)
+		assert.NotErrorIs(t, err, AllCouponIssuedError)
+
+		var appErr *Error
+		assert.True(t, errors.As(err, &appErr))
+		assert.Equal(t, KindUnavailable, appErr.Kind)
+		assert.True(t, appErr.Retryable)
+	})
+
+	t.Run("감싸도 원래 에러는 바뀌지 않아야 한다", func(t *testing.T) {
+		_ = CouponLookupError.Wrap(cause)
+
+		assert.Nil(t, CouponLookupError.Cause)
+		assert.Equal(t, "failed to find coupon", CouponLookupError.Error())
+	})
+}
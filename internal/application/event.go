@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/repository"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	eventRelayLeaseName = "event_relay"
+	// eventRelayInterval 발행 대기열에 저장한 이벤트를 확인하는 간격
+	eventRelayInterval  = time.Second
+	eventRelayBatchSize = 100
+
+	campaignOpenerLeaseName = "campaign_opener"
+	// campaignOpenInterval 발급 시작 시각이 지난 캠페인을 확인하는 간격
+	campaignOpenInterval  = 10 * time.Second
+	campaignOpenBatchSize = 100
+)
+
+// WithEventPublisher 도메인 이벤트를 발행할 메시지 버스를 지정한다. 지정하지 않으면 이벤트를 발행하지 않는다
+func WithEventPublisher(publisher domain.EventPublisher) Option {
+	return func(c *CouponService) {
+		c.events = publisher
+	}
+}
+
+// WithEventOutbox 이벤트를 요청 처리 중에 발행하지 않고 DB 의 발행 대기열에 저장한다
+// RunEventRelay 가 저장한 순서대로 발행하며, 발행에 실패하면 다음 실행에서 다시 발행한다
+func WithEventOutbox(eventOutboxRepository *repository.EventOutboxRepository) Option {
+	return func(c *CouponService) {
+		c.eventOutboxRepository = eventOutboxRepository
+	}
+}
+
+// publishesEvents 이벤트를 메시지 버스에 발행하거나 발행 대기열에 저장하는지 여부
+func (c *CouponService) publishesEvents() bool {
+	return c.events != nil || c.eventOutboxRepository != nil
+}
+
+// publish 이벤트는 요청 처리가 끝난 후에 발행하며, 발행에 실패해도 요청은 실패시키지 않고 기록만 남긴다
+// 발행 대기열을 사용하면 대기열에 저장만 한다
+func (c *CouponService) publish(ctx context.Context, events ...domain.Event) {
+	if !c.publishesEvents() || len(events) == 0 {
+		return
+	}
+	var err error
+	if c.eventOutboxRepository != nil {
+		err = c.eventOutboxRepository.Enqueue(events)
+	} else {
+		err = c.events.Publish(context.WithoutCancel(ctx), events...)
+	}
+	if err != nil {
+		log.Printf("failed to publish events type=%s count=%d: %v", events[0].Type, len(events), err)
+	}
+}
+
+// RunEventRelay 발행 대기열에 저장한 이벤트를 설정한 간격마다 발행한다
+// 발행 대기열을 사용하지 않으면 실행하지 않으며, ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) RunEventRelay(ctx context.Context) {
+	if c.eventOutboxRepository == nil {
+		return
+	}
+	ticker := time.NewTicker(eventRelayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.RelayEvents(ctx); err != nil {
+				log.Println(err.Error())
+			}
+		}
+	}
+}
+
+// RelayEvents 발행 대기열의 이벤트를 저장한 순서대로 웹훅 전달 대기열에 넣고 메시지 버스에 발행한 뒤 발행한 수를 반환한다
+// 순서를 지키도록 여러 인스턴스 중 임대를 획득한 인스턴스만 실행한다
+// 발행에 성공했지만 대기열에서 삭제하지 못한 이벤트는 다시 발행되므로 구독자는 이벤트 ID 로 중복을 걸러야 한다
+func (c *CouponService) RelayEvents(ctx context.Context) (int, error) {
+	if c.eventOutboxRepository == nil {
+		return 0, nil
+	}
+	var total int
+	relay := func() error {
+		for ctx.Err() == nil {
+			count, err := c.eventOutboxRepository.PublishBatch(eventRelayBatchSize, func(events []domain.Event) error {
+				return c.relay(ctx, events)
+			})
+			total += count
+			if err != nil {
+				return err
+			}
+			if count < eventRelayBatchSize {
+				return nil
+			}
+		}
+		return ctx.Err()
+	}
+
+	if _, err := c.withLease(ctx, eventRelayLeaseName, relay); err != nil {
+		return total, EventRelayError.Wrap(err)
+	}
+	return total, nil
+}
+
+// relay 커밋된 발행 대기열의 이벤트만 웹훅 전달 대기열에 넣으므로 전달은 발행 대기열의 이벤트 ID 를 따른다
+// 메시지 버스 발행에 실패하면 이벤트가 대기열에 남아 다시 전달되지만, 이미 넣은 웹훅 전달은 이벤트 ID 로 중복을 거른다
+func (c *CouponService) relay(ctx context.Context, events []domain.Event) error {
+	if c.webhookRepository != nil {
+		if err := c.enqueueWebhooks(events); err != nil {
+			return err
+		}
+	}
+	if c.events == nil {
+		return nil
+	}
+	return c.events.Publish(ctx, events...)
+}
+
+// markSoldOut 마지막 재고를 발급한 요청이나 재고가 없어 거절된 요청에서 캠페인의 매진 이벤트를 한 번만 발행한다
+// 매진 이후의 요청이 모두 DB 에 도달하지 않도록 확인한 캠페인은 로컬 캐시에 기록한다
+func (c *CouponService) markSoldOut(ctx context.Context, coupon *domain.Coupon, now time.Time) {
+	if !c.publishesEvents() {
+		return
+	}
+	if _, ok := c.soldOut.Get(coupon.ID); ok {
+		return
+	}
+	marked, err := c.couponRepository.MarkSoldOut(coupon.ID, now)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+	c.soldOut.Set(coupon.ID, true)
+	if marked {
+		c.publish(ctx, domain.NewCampaignEvent(domain.CampaignSoldOut, coupon, now))
+	}
+}
+
+// RunCampaignOpener 발급 시작 시각이 지난 캠페인의 시작 이벤트를 발행한다
+// 발행 대기열을 사용하지 않으면 실행하지 않으며, ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) RunCampaignOpener(ctx context.Context) {
+	if c.eventOutboxRepository == nil {
+		return
+	}
+	ticker := time.NewTicker(campaignOpenInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.PublishOpenedCampaigns(ctx, time.Now()); err != nil {
+				log.Println(err.Error())
+			}
+		}
+	}
+}
+
+// PublishOpenedCampaigns 발급 시작 시각이 지났지만 시작 이벤트를 발행하지 않은 진행 중인 캠페인의 시작 이벤트를 발행 대기열에 저장한다
+// 여러 인스턴스 중 임대를 획득한 인스턴스만 실행한다
+func (c *CouponService) PublishOpenedCampaigns(ctx context.Context, now time.Time) (int, error) {
+	var total int
+	open := func() error {
+		for ctx.Err() == nil {
+			count, err := c.couponRepository.OpenBatch(now, campaignOpenBatchSize, func(coupons []domain.Coupon) []domain.Event {
+				if c.eventOutboxRepository == nil {
+					return nil
+				}
+				events := make([]domain.Event, len(coupons))
+				for i := range coupons {
+					events[i] = domain.NewCampaignEvent(domain.CampaignOpened, &coupons[i], now)
+				}
+				return events
+			})
+			total += count
+			if err != nil {
+				return err
+			}
+			if count < campaignOpenBatchSize {
+				return nil
+			}
+		}
+		return ctx.Err()
+	}
+
+	if _, err := c.withLease(ctx, campaignOpenerLeaseName, open); err != nil {
+		return total, CampaignOpenError.Wrap(err)
+	}
+	return total, nil
+}
+
+// RevokeCoupon 사용하지 않은 발급 쿠폰을 취소한다. 취소한 쿠폰은 사용할 수 없으며 재고는 복구하지 않는다
+func (c *CouponService) RevokeCoupon(ctx context.Context, issuedCouponId string) (*domain.IssuedCoupon, error) {
+	issuedCoupon, err := c.issuedCouponRepository.Revoke(issuedCouponId)
+	if errors.Is(err, repository.ErrIssuedCouponNotFound) {
+		return nil, IssuedCouponNotFoundError.Wrap(err)
+	}
+	if errors.Is(err, repository.ErrIssuedCouponNotRevocable) {
+		return nil, CouponNotRevocableError.Wrap(err)
+	}
+	if err != nil {
+		return nil, RevokeError.Wrap(err)
+	}
+	c.publish(ctx, domain.NewCouponEvent(domain.CouponRevoked, issuedCoupon, issuedCoupon.ModifiedAt))
+	return issuedCoupon, nil
+}
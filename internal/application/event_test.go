@@ -0,0 +1,153 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/event"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCouponEventsWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	publisher := event.NewMemoryPublisher()
+	couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo,
+		WithEventPublisher(publisher))
+	now := time.Now()
+
+	coupon, err := couponService.CreateCoupon(ctx, "이벤트 쿠폰", 2, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	t.Run("캠페인 생성 이벤트를 발행해야 한다", func(t *testing.T) {
+		events := publisher.EventsOf(domain.CampaignCreated)
+		require.Len(t, events, 1)
+		assert.Equal(t, coupon.ID, events[0].Key)
+	})
+
+	var issued []*domain.IssuedCoupon
+	t.Run("발급마다 발급 이벤트를 발행하고 소진 이벤트는 한 번만 발행해야 한다", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			issuedCoupon, err := couponService.Issue(ctx, coupon.ID, uuid.New().String())
+			require.NoError(t, err)
+			issued = append(issued, issuedCoupon)
+		}
+		// 마지막 재고를 발급한 요청에서 소진 이벤트를 발행한다
+		assert.Len(t, publisher.EventsOf(domain.CampaignSoldOut), 1)
+
+		for i := 0; i < 3; i++ {
+			_, err := couponService.Issue(ctx, coupon.ID, uuid.New().String())
+			require.ErrorIs(t, err, AllCouponIssuedError)
+		}
+
+		assert.Len(t, publisher.EventsOf(domain.CouponIssued), 2)
+		assert.Len(t, publisher.EventsOf(domain.CampaignSoldOut), 1)
+	})
+
+	t.Run("쿠폰 사용과 회수 이벤트를 발행해야 한다", func(t *testing.T) {
+		require.Len(t, issued, 2)
+		_, err := couponService.Redeem(ctx, coupon.ID, issued[0].Code, issued[0].UserID)
+		require.NoError(t, err)
+		_, err = couponService.RevokeCoupon(ctx, issued[1].ID)
+		require.NoError(t, err)
+
+		redeemed := publisher.EventsOf(domain.CouponRedeemed)
+		require.Len(t, redeemed, 1)
+		var payload domain.CouponEventPayload
+		require.NoError(t, redeemed[0].DecodePayload(&payload))
+		assert.Equal(t, issued[0].ID, payload.IssuedCouponID)
+		assert.Len(t, publisher.EventsOf(domain.CouponRevoked), 1)
+	})
+
+	t.Run("회수된 쿠폰은 다시 회수할 수 없어야 한다", func(t *testing.T) {
+		_, err := couponService.RevokeCoupon(ctx, issued[1].ID)
+
+		assert.ErrorIs(t, err, CouponNotRevocableError)
+	})
+
+}
+
+// failingPublisher fail 이 true 인 동안 발행에 실패한다
+type failingPublisher struct {
+	*event.MemoryPublisher
+	fail bool
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	if p.fail {
+		return errors.New("message bus is unavailable")
+	}
+	return p.MemoryPublisher.Publish(ctx, events...)
+}
+
+func TestEventOutboxWithContainer(t *testing.T) {
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.EventOutboxEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	publisher := &failingPublisher{MemoryPublisher: event.NewMemoryPublisher(), fail: true}
+	couponService := NewCouponService(nil, couponRepo, issuedCouponRepo,
+		WithIssuanceStrategy(MySQLIssuance),
+		WithEventPublisher(publisher),
+		WithEventOutbox(repository.NewEventOutboxRepository(mysqlContainer.DB)))
+	now := time.Now()
+
+	coupon, err := couponService.CreateCoupon(ctx, "대기열 쿠폰", 1, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = couponService.Issue(ctx, coupon.ID, uuid.New().String())
+	require.NoError(t, err)
+
+	t.Run("발급 요청은 이벤트를 발행하지 않고 대기열에 저장해야 한다", func(t *testing.T) {
+		assert.Empty(t, publisher.Events())
+	})
+
+	t.Run("발행에 실패하면 대기열에 남겨 다음 실행에서 다시 발행해야 한다", func(t *testing.T) {
+		count, err := couponService.RelayEvents(ctx)
+		require.ErrorIs(t, err, EventRelayError)
+		assert.Equal(t, 0, count)
+
+		publisher.fail = false
+		count, err = couponService.RelayEvents(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		events := publisher.Events()
+		require.Len(t, events, 3)
+		assert.Equal(t, domain.CampaignCreated, events[0].Type)
+		assert.Equal(t, domain.CouponIssued, events[1].Type)
+		assert.Equal(t, domain.CampaignSoldOut, events[2].Type)
+	})
+
+	t.Run("발행한 이벤트는 다시 발행하지 않아야 한다", func(t *testing.T) {
+		count, err := couponService.RelayEvents(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, count)
+		assert.Len(t, publisher.Events(), 3)
+	})
+
+	t.Run("시작된 캠페인마다 오픈 이벤트를 한 번만 대기열에 저장해야 한다", func(t *testing.T) {
+		count, err := couponService.PublishOpenedCampaigns(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = couponService.PublishOpenedCampaigns(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.Empty(t, publisher.EventsOf(domain.CampaignOpened))
+
+		_, err = couponService.RelayEvents(ctx)
+		require.NoError(t, err)
+		assert.Len(t, publisher.EventsOf(domain.CampaignOpened), 1)
+	})
+}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	expirySweepLeaseName = "expiry_sweeper"
+	// leaseTTL 임대를 가진 인스턴스가 해제하지 못하고 종료되어도 이 시간이 지나면 다른 인스턴스가 실행할 수 있다
+	leaseTTL = 5 * time.Minute
+)
+
+// ExpirySweepConfig 만료 처리 작업의 설정
+//   - Interval: 실행 간격. 0 이면 실행하지 않는다
+//   - BatchSize: 한 트랜잭션에서 처리하는 쿠폰 수
+//   - NoticeBefore: 사용 기간이 끝나기 이 시간 전에 만료 예정 이벤트를 보낸다
+type ExpirySweepConfig struct {
+	Interval     time.Duration
+	BatchSize    int
+	NoticeBefore time.Duration
+}
+
+var DefaultExpirySweepConfig = ExpirySweepConfig{
+	Interval:     time.Minute,
+	BatchSize:    500,
+	NoticeBefore: 24 * time.Hour,
+}
+
+// ExpirySweepResult 한 번의 실행에서 처리한 쿠폰 수. Skipped 는 다른 인스턴스가 실행 중이라 건너뛰었는지 여부이다
+type ExpirySweepResult struct {
+	ExpiringSoon int
+	Expired      int
+	Skipped      bool
+}
+
+// WithExpirySweepConfig 만료 처리 작업의 설정을 변경한다
+func WithExpirySweepConfig(config ExpirySweepConfig) Option {
+	return func(c *CouponService) {
+		c.expiryConfig = config
+	}
+}
+
+// RunExpirySweeper 설정한 간격마다 SweepExpiredCoupons 를 실행한다
+// ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) RunExpirySweeper(ctx context.Context) {
+	if c.expiryConfig.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.expiryConfig.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := c.SweepExpiredCoupons(ctx, time.Now())
+			if err != nil {
+				log.Println(err.Error())
+				continue
+			}
+			if result.ExpiringSoon > 0 || result.Expired > 0 {
+				log.Printf("coupon expiry sweep expiring_soon=%d expired=%d", result.ExpiringSoon, result.Expired)
+			}
+		}
+	}
+}
+
+// SweepExpiredCoupons 만료 예정 이벤트를 보내고 사용 기간이 지난 미사용 쿠폰을 만료 처리한다
+// 이벤트는 처리 내용과 같은 트랜잭션에서 발행 대기열에 저장하며 RelayEvents 가 발행한다. 발행 대기열을 사용하지 않으면 이벤트를 보내지 않는다
+// 여러 인스턴스에서 실행되어도 한 인스턴스만 처리하도록 Redis 임대를, Redis 를 사용하지 않으면 DB 잠금을 획득한다
+func (c *CouponService) SweepExpiredCoupons(ctx context.Context, now time.Time) (ExpirySweepResult, error) {
+	var result ExpirySweepResult
+	batchSize := c.expiryConfig.BatchSize
+	expiringBefore := now.Add(c.expiryConfig.NoticeBefore)
+	sweep := func() error {
+		var err error
+		result.ExpiringSoon, err = c.sweepBatches(ctx, domain.CouponExpiringSoon, now,
+			func(events func([]domain.IssuedCoupon) []domain.Event) (int, error) {
+				return c.issuedCouponRepository.NotifyExpiringBatch(now, expiringBefore, batchSize, events)
+			})
+		if err != nil {
+			return err
+		}
+		result.Expired, err = c.sweepBatches(ctx, domain.CouponExpired, now,
+			func(events func([]domain.IssuedCoupon) []domain.Event) (int, error) {
+				return c.issuedCouponRepository.ExpireBatch(now, batchSize, events)
+			})
+		return err
+	}
+
+	acquired, err := c.withLease(ctx, expirySweepLeaseName, sweep)
+	if err != nil {
+		return result, ExpirySweepError.Wrap(err)
+	}
+	result.Skipped = !acquired
+	return result, nil
+}
+
+// sweepBatches 처리할 쿠폰이 BatchSize 보다 적게 남을 때까지 묶음 단위로 처리한다
+func (c *CouponService) sweepBatches(
+	ctx context.Context,
+	eventType domain.EventType,
+	now time.Time,
+	batch func(events func([]domain.IssuedCoupon) []domain.Event) (int, error),
+) (int, error) {
+	events := func(issuedCoupons []domain.IssuedCoupon) []domain.Event {
+		if c.eventOutboxRepository == nil {
+			return nil
+		}
+		return domain.NewCouponEvents(eventType, issuedCoupons, now)
+	}
+	var total int
+	for ctx.Err() == nil {
+		count, err := batch(events)
+		total += count
+		if err != nil {
+			return total, err
+		}
+		if count == 0 || count < c.expiryConfig.BatchSize {
+			break
+		}
+	}
+	return total, ctx.Err()
+}
+
+// withLease 여러 인스턴스 중 하나에서만 fn 을 실행한다. Redis 를 사용하지 않으면 같은 이름의 DB 잠금을 사용한다
+// 임대를 획득하지 못하면 fn 을 실행하지 않고 false 를 반환한다
+func (c *CouponService) withLease(ctx context.Context, name string, fn func() error) (bool, error) {
+	if c.cache == nil {
+		return c.issuedCouponRepository.WithAdvisoryLock(ctx, name, fn)
+	}
+
+	key := genLeaseKey(name)
+	owner := uuid.New().String()
+	acquired, err := c.cache.AcquireLease(ctx, key, owner, leaseTTL)
+	if err != nil || !acquired {
+		return false, err
+	}
+	defer func() {
+		if err2 := c.cache.ReleaseLease(context.WithoutCancel(ctx), key, owner); err2 != nil {
+			log.Println(err2.Error())
+		}
+	}()
+	return true, fn()
+}
+
+func genLeaseKey(name string) string {
+	return fmt.Sprintf("coupon:lease:%s", name)
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/event"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestExpirySweeperWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.EventOutboxEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	publisher := event.NewMemoryPublisher()
+	config := ExpirySweepConfig{Interval: time.Minute, BatchSize: 2, NoticeBefore: 24 * time.Hour}
+	couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo,
+		WithEventPublisher(publisher),
+		WithEventOutbox(repository.NewEventOutboxRepository(mysqlContainer.DB)),
+		WithExpirySweepConfig(config))
+	now := time.Now()
+
+	coupon, err := couponService.CreateCoupon(ctx, "3일 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour),
+		domain.WithUsageValidity(domain.UsageValidity{Duration: 72 * time.Hour}))
+	require.NoError(t, err)
+	userIDs := []string{uuid.New().String(), uuid.New().String(), uuid.New().String()}
+	var codes []string
+	for _, userID := range userIDs {
+		issued, err2 := couponService.Issue(ctx, coupon.ID, userID)
+		require.NoError(t, err2)
+		codes = append(codes, issued.Code)
+	}
+
+	t.Run("다른 인스턴스가 임대 중이면 실행하지 않아야 한다", func(t *testing.T) {
+		require.NoError(t, redisContainer.Client.Set(ctx, genLeaseKey(expirySweepLeaseName), "other", time.Minute).Err())
+		defer redisContainer.Client.Del(ctx, genLeaseKey(expirySweepLeaseName))
+
+		result, err := couponService.SweepExpiredCoupons(ctx, now.Add(60*time.Hour))
+
+		require.NoError(t, err)
+		assert.True(t, result.Skipped)
+	})
+
+	t.Run("만료 예정 이벤트는 쿠폰마다 한 번만 보내야 한다", func(t *testing.T) {
+		result, err := couponService.SweepExpiredCoupons(ctx, now.Add(60*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, ExpirySweepResult{ExpiringSoon: 3}, result)
+
+		result, err = couponService.SweepExpiredCoupons(ctx, now.Add(61*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, ExpirySweepResult{}, result)
+		assert.Empty(t, publisher.EventsOf(domain.CouponExpiringSoon), "대기열에 저장한 이벤트는 RelayEvents 가 발행한다")
+
+		_, err = couponService.RelayEvents(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, len(publisher.EventsOf(domain.CouponExpiringSoon)))
+	})
+
+	t.Run("사용 기간이 지난 미사용 쿠폰은 만료 처리되어 사용할 수 없어야 한다", func(t *testing.T) {
+		_, err := couponService.Redeem(ctx, coupon.ID, codes[0], userIDs[0])
+		require.NoError(t, err)
+
+		result, err := couponService.SweepExpiredCoupons(ctx, now.Add(73*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, ExpirySweepResult{Expired: 2}, result)
+		_, err = couponService.RelayEvents(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(publisher.EventsOf(domain.CouponExpired)))
+
+		_, err = couponService.Redeem(ctx, coupon.ID, codes[1], userIDs[1])
+		assert.ErrorIs(t, err, CouponNotUsableError)
+	})
+
+	t.Run("Redis 를 사용하지 않으면 DB 잠금으로 실행해야 한다", func(t *testing.T) {
+		mysqlService := NewCouponService(nil, couponRepo, issuedCouponRepo,
+			WithIssuanceStrategy(MySQLIssuance), WithExpirySweepConfig(config))
+
+		result, err := mysqlService.SweepExpiredCoupons(ctx, now.Add(73*time.Hour))
+
+		require.NoError(t, err)
+		assert.False(t, result.Skipped)
+	})
+}
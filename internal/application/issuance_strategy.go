@@ -0,0 +1,479 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/cache"
+	"coupon-service/internal/infrastructure/repository"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+)
+
+type IssuanceStrategyType string
+
+const (
+	// RedisIssuance Redis 의 사용자 집합과 재고 카운터로 선착순을 판단하고 DB 에는 결과만 저장한다
+	RedisIssuance IssuanceStrategyType = "redis"
+	// MySQLIssuance 조건부 UPDATE 와 (coupon_id, user_id) 유니크 제약만으로 발급한다. Redis 가 필요 없다
+	MySQLIssuance IssuanceStrategyType = "mysql"
+	// HybridIssuance Redis 로 중복/매진 요청을 먼저 걸러내고, 최종 재고 차감은 MySQL 에서 수행한다
+	HybridIssuance IssuanceStrategyType = "hybrid"
+)
+
+// IssuanceStrategy 재고를 차감하고 발급 내역을 저장하는 방식
+type IssuanceStrategy interface {
+	// Prepare 캠페인 생성 시 발급에 필요한 재고 상태를 준비한다
+	Prepare(ctx context.Context, coupon *domain.Coupon) error
+	// Issue 재고를 차감하고 발급 내역을 저장한다. 저장한 발급으로 재고가 모두 소진되었으면 soldOut 이 true 이다
+	Issue(ctx context.Context, coupon *domain.Coupon, issuedCoupon *domain.IssuedCoupon) (soldOut bool, err error)
+	// Remaining 남은 재고를 조회한다
+	Remaining(ctx context.Context, coupon *domain.Coupon) (int64, error)
+}
+
+func newIssuanceStrategy(c *CouponService) IssuanceStrategy {
+	switch c.strategyType {
+	case MySQLIssuance:
+		return newMySQLIssuanceStrategy(c)
+	case HybridIssuance:
+		return &hybridIssuanceStrategy{
+			redis: newRedisIssuanceStrategy(c),
+			mysql: newMySQLIssuanceStrategy(c),
+		}
+	case RedisIssuance:
+		return newRedisIssuanceStrategy(c)
+	default:
+		panic(fmt.Sprintf("unknown issuance strategy: %s", c.strategyType))
+	}
+}
+
+type redisIssuanceStrategy struct {
+	cache                  cache.Cache
+	dbFallback             bool
+	issuedCouponRepository *repository.IssuedCouponRepository
+}
+
+func newRedisIssuanceStrategy(c *CouponService) *redisIssuanceStrategy {
+	if c.cache == nil {
+		panic(fmt.Sprintf("%s issuance strategy requires a cache client", c.strategyType))
+	}
+	return &redisIssuanceStrategy{
+		cache:                  c.cache,
+		dbFallback:             c.dbFallback,
+		issuedCouponRepository: c.issuedCouponRepository,
+	}
+}
+
+// Prepare 코드 풀 캠페인은 코드를 등록할 때 코드 풀 리스트를 채우므로 재고 카운터를 만들지 않는다
+func (s *redisIssuanceStrategy) Prepare(ctx context.Context, coupon *domain.Coupon) error {
+	if coupon.UsesCodePool() {
+		return nil
+	}
+	keys := genCouponStockKeys(coupon)
+	amounts := coupon.ShardAmounts()
+	for i, key := range keys {
+		if err := s.cache.Set(ctx, key, amounts[i]); err != nil {
+			for _, setKey := range keys[:i] {
+				if delErr := s.cache.Del(ctx, setKey); delErr != nil {
+					log.Println(delErr.Error())
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *redisIssuanceStrategy) Issue(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	issuedCoupon *domain.IssuedCoupon,
+) (bool, error) {
+	if coupon.UsesCodePool() {
+		return s.issueFromPool(ctx, coupon, issuedCoupon, func() (bool, error) {
+			return s.issueWithCouponLock(coupon, issuedCoupon)
+		})
+	}
+
+	stockKey, last, err := s.claim(ctx, coupon, issuedCoupon)
+	if errors.Is(err, CacheUnavailableError) {
+		return s.issueWithCouponLock(coupon, issuedCoupon)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err2 := s.issuedCouponRepository.SaveUnlessDegraded(issuedCoupon); err2 != nil {
+		s.release(ctx, coupon.ID, userMember(issuedCoupon), stockKey)
+		// 다른 인스턴스가 Redis 장애 중 DB 로 발급한 내역이 반영되기 전이므로 DB 기준으로 발급한다
+		if errors.Is(err2, repository.ErrCouponDegraded) {
+			return s.issueDegraded(coupon, issuedCoupon)
+		}
+		return false, IssuedCouponCreationError.Wrap(err2)
+	}
+	return last, nil
+}
+
+func (s *redisIssuanceStrategy) Remaining(ctx context.Context, coupon *domain.Coupon) (int64, error) {
+	if coupon.UsesCodePool() {
+		count, err := s.cache.ListLen(ctx, genCouponCodePoolKey(coupon.ID))
+		if err != nil {
+			return 0, RemainingStockError.Wrap(err)
+		}
+		return count, nil
+	}
+
+	var total int64
+	for _, key := range genCouponStockKeys(coupon) {
+		count, err := s.cache.GetInt(ctx, key)
+		if err != nil {
+			return 0, RemainingStockError.Wrap(err)
+		}
+		// 다른 요청이 감소 후 원복하기 전이라면 일시적으로 음수일 수 있다
+		if count > 0 {
+			total += count
+		}
+	}
+	return total, nil
+}
+
+// claim 사용자 집합에 추가한 후 재고를 차감하고, 차감한 재고 카운터의 키와 마지막 재고였는지를 반환한다
+func (s *redisIssuanceStrategy) claim(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	issuedCoupon *domain.IssuedCoupon,
+) (string, bool, error) {
+	if err := s.addUser(ctx, coupon, issuedCoupon); err != nil {
+		return "", false, err
+	}
+
+	stockKey, last, err2 := s.claimStock(ctx, coupon)
+	if err2 != nil {
+		if !errors.Is(err2, AllCouponIssuedError) {
+			return "", false, err2
+		}
+
+		_, delErr := s.cache.SetDel(ctx, genCouponUserKey(coupon.ID), userMember(issuedCoupon))
+		if delErr != nil {
+			return "", false, DeleteRecoveryError.Wrap(delErr)
+		}
+		return "", false, AllCouponIssuedError
+	}
+	return stockKey, last, nil
+}
+
+// addUser 사용자 집합에 추가하고 발급 순번을 채운다. 발급 한도만큼 이미 있으면 DuplicatedCouponUserError 를 반환한다
+// 한 사용자에게 여러 번 발급할 수 있는 캠페인은 순번마다 다른 구성원(userMember)을 사용한다
+func (s *redisIssuanceStrategy) addUser(ctx context.Context, coupon *domain.Coupon, issuedCoupon *domain.IssuedCoupon) error {
+	for seq := 0; seq < coupon.UserLimit(); seq++ {
+		issuedCoupon.Sequence = seq
+		added, err := s.cache.SetAdd(ctx, genCouponUserKey(coupon.ID), userMember(issuedCoupon))
+		if errors.Is(err, cache.ErrCircuitOpen) {
+			return CacheUnavailableError.Wrap(err)
+		}
+		if err != nil {
+			return CacheAddUserError.Wrap(err)
+		}
+		if added {
+			return nil
+		}
+	}
+	return DuplicatedCouponUserError
+}
+
+// issueFromPool 사용자 집합에 추가한 후 코드 풀 리스트에서 꺼낸 코드로 발급한다
+// Redis 장애 중 DB 에서 발급된 코드는 리스트에 남아있으므로, 이미 발급된 코드를 꺼내면 버리고 다음 코드를 꺼낸다
+// Redis 를 사용할 수 없으면 fallback 으로 발급한다
+func (s *redisIssuanceStrategy) issueFromPool(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	issuedCoupon *domain.IssuedCoupon,
+	fallback func() (bool, error),
+) (bool, error) {
+	err := s.addUser(ctx, coupon, issuedCoupon)
+	if errors.Is(err, CacheUnavailableError) {
+		return fallback()
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		code, err2 := s.cache.ListPop(ctx, genCouponCodePoolKey(coupon.ID))
+		if err2 != nil {
+			s.releaseUser(ctx, coupon.ID, userMember(issuedCoupon))
+			if errors.Is(err2, cache.ErrKeyNotFound) {
+				return false, AllCouponIssuedError
+			}
+			if errors.Is(err2, cache.ErrCircuitOpen) {
+				return fallback()
+			}
+			return false, CouponDecrError.Wrap(err2)
+		}
+
+		issuedCoupon.Code = code
+		remaining, err2 := s.issuedCouponRepository.SaveWithPoolCode(issuedCoupon)
+		if errors.Is(err2, repository.ErrPoolCodeUnavailable) {
+			log.Printf("discard unavailable pool code coupon=%s code=%s", coupon.ID, code)
+			continue
+		}
+		if err2 != nil {
+			s.returnCode(ctx, coupon.ID, code)
+			if errors.Is(err2, repository.ErrDuplicatedCouponUser) {
+				return false, DuplicatedCouponUserError
+			}
+			s.releaseUser(ctx, coupon.ID, userMember(issuedCoupon))
+			if errors.Is(err2, repository.ErrCouponSoldOut) {
+				return false, AllCouponIssuedError
+			}
+			return false, IssuedCouponCreationError.Wrap(err2)
+		}
+		return remaining == 0, nil
+	}
+}
+
+// returnCode 저장에 실패한 코드를 코드 풀 리스트에 되돌린다
+func (s *redisIssuanceStrategy) returnCode(ctx context.Context, couponId string, code string) {
+	if _, err := s.cache.ListPush(ctx, genCouponCodePoolKey(couponId), code); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+func (s *redisIssuanceStrategy) releaseUser(ctx context.Context, couponId string, member string) {
+	if _, err := s.cache.SetDel(ctx, genCouponUserKey(couponId), member); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// release claim 이후 저장에 실패하면 차감한 재고와 사용자 집합을 원복한다
+func (s *redisIssuanceStrategy) release(ctx context.Context, couponId string, member string, stockKey string) {
+	if _, err := s.cache.Incr(ctx, stockKey); err != nil {
+		log.Println(err.Error())
+	}
+	s.releaseUser(ctx, couponId, member)
+}
+
+// claimStock 재고 1개를 차감하고, 차감한 재고 카운터의 키와 마지막 재고였는지를 반환한다
+// 샤딩된 캠페인은 임의의 하위 카운터부터 시작해 비어있으면 다음 하위 카운터로 넘어가며,
+// 차감한 하위 카운터가 비었을 때만 나머지 하위 카운터를 조회해 마지막 재고였는지 판단한다
+func (s *redisIssuanceStrategy) claimStock(ctx context.Context, coupon *domain.Coupon) (string, bool, error) {
+	if !coupon.IsSharded() {
+		key := genCouponAmountKey(coupon.ID)
+		count, err := s.decrStock(ctx, key)
+		return key, err == nil && count == 0, err
+	}
+
+	start := rand.Intn(coupon.StockShards)
+	for i := 0; i < coupon.StockShards; i++ {
+		key := genCouponShardKey(coupon.ID, (start+i)%coupon.StockShards)
+		count, err := s.decrStock(ctx, key)
+		if errors.Is(err, AllCouponIssuedError) {
+			continue
+		}
+		if err != nil || count > 0 {
+			return key, false, err
+		}
+		total, err := s.Remaining(ctx, coupon)
+		if err != nil {
+			// 재고는 이미 차감했으므로 조회 실패로 발급을 실패시키지 않는다. 매진은 다음 요청이 거절될 때 기록된다
+			log.Println(err.Error())
+			return key, false, nil
+		}
+		return key, total == 0, nil
+	}
+	return "", false, AllCouponIssuedError
+}
+
+// decrStock 재고 카운터를 1 차감하고 차감한 후의 값을 반환한다
+func (s *redisIssuanceStrategy) decrStock(ctx context.Context, couponKey string) (int64, error) {
+	count, err := s.cache.Decr(ctx, couponKey)
+	if errors.Is(err, cache.ErrCircuitOpen) {
+		return 0, CacheUnavailableError.Wrap(err)
+	}
+	if err != nil {
+		return 0, CouponDecrError.Wrap(err)
+	}
+
+	if count < 0 {
+		_, incrErr := s.cache.Incr(ctx, couponKey)
+		if incrErr != nil {
+			return 0, CouponAmountRecoveryError.Wrap(incrErr)
+		}
+		return 0, AllCouponIssuedError
+	}
+	return count, nil
+}
+
+// issueWithCouponLock Redis 를 사용할 수 없을 때의 발급 경로
+// DB fallback 이 비활성화되어 있으면 재시도 가능한 에러로 즉시 실패한다
+func (s *redisIssuanceStrategy) issueWithCouponLock(coupon *domain.Coupon, issuedCoupon *domain.IssuedCoupon) (bool, error) {
+	if !s.dbFallback {
+		return false, CacheUnavailableError.Wrap(cache.ErrCircuitOpen)
+	}
+	return s.issueDegraded(coupon, issuedCoupon)
+}
+
+// issueDegraded 캠페인 row 잠금을 이용해 DB 만으로 발급한다
+// 발급 내역은 MySQL 에 남겨 RunDegradedIssuanceSync 가 Redis 에 반영하며, 반영할 때까지 다른 인스턴스도 이 캠페인을 DB 기준으로 발급한다
+func (s *redisIssuanceStrategy) issueDegraded(coupon *domain.Coupon, issuedCoupon *domain.IssuedCoupon) (bool, error) {
+	var remaining int64
+	var err error
+	if coupon.UsesCodePool() {
+		issuedCoupon.Code = ""
+		remaining, err = s.issuedCouponRepository.SaveDegradedWithPoolCode(issuedCoupon)
+	} else {
+		remaining, err = s.issuedCouponRepository.SaveDegraded(issuedCoupon, coupon.UserLimit())
+	}
+	return issueResult(remaining, err)
+}
+
+// syncDegradedIssuance DB 로 발급된 내역 하나를 사용자 집합과 재고 카운터에 반영한다
+// 사용자 집합에 이미 있더라도 재고 차감 전에 장애가 난 경우이므로 재고는 항상 차감한다
+// 재고 카운터가 비어있으면 DB 보다 많이 발급된 것이므로 DegradedIssuanceOversoldError 를 반환한다
+func (s *redisIssuanceStrategy) syncDegradedIssuance(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	issuedCoupon *domain.IssuedCoupon,
+) error {
+	if _, err := s.cache.SetAdd(ctx, genCouponUserKey(coupon.ID), userMember(issuedCoupon)); err != nil {
+		return DegradedIssuanceSyncError.Wrap(err)
+	}
+	// 코드 풀 캠페인은 발급된 코드를 리스트에서 꺼낼 때 건너뛰므로 사용자 집합만 반영한다
+	if coupon.UsesCodePool() {
+		return nil
+	}
+	_, _, err := s.claimStock(ctx, coupon)
+	if errors.Is(err, AllCouponIssuedError) {
+		return DegradedIssuanceOversoldError.Wrap(
+			fmt.Errorf("coupon=%s issued_coupon=%s", coupon.ID, issuedCoupon.ID),
+		)
+	}
+	if err != nil {
+		return DegradedIssuanceSyncError.Wrap(err)
+	}
+	return nil
+}
+
+type mysqlIssuanceStrategy struct {
+	couponRepository       *repository.CouponRepository
+	issuedCouponRepository *repository.IssuedCouponRepository
+}
+
+func newMySQLIssuanceStrategy(c *CouponService) *mysqlIssuanceStrategy {
+	return &mysqlIssuanceStrategy{
+		couponRepository:       c.couponRepository,
+		issuedCouponRepository: c.issuedCouponRepository,
+	}
+}
+
+// Prepare 남은 재고는 캠페인 저장 시 coupons.remaining 에 기록되므로 준비할 것이 없다
+func (s *mysqlIssuanceStrategy) Prepare(_ context.Context, _ *domain.Coupon) error {
+	return nil
+}
+
+func (s *mysqlIssuanceStrategy) Issue(
+	_ context.Context,
+	coupon *domain.Coupon,
+	issuedCoupon *domain.IssuedCoupon,
+) (bool, error) {
+	var remaining int64
+	var err error
+	if coupon.UsesCodePool() {
+		remaining, err = s.issuedCouponRepository.SaveWithPoolCode(issuedCoupon)
+	} else {
+		remaining, err = s.issuedCouponRepository.SaveWithStockDecrement(issuedCoupon, coupon.UserLimit())
+	}
+	return issueResult(remaining, err)
+}
+
+func (s *mysqlIssuanceStrategy) Remaining(_ context.Context, coupon *domain.Coupon) (int64, error) {
+	remaining, err := s.couponRepository.FindRemaining(coupon.ID)
+	if err != nil {
+		return 0, RemainingStockError.Wrap(err)
+	}
+	return remaining, nil
+}
+
+// hybridIssuanceStrategy Redis 는 대부분의 실패 요청을 DB 에 도달하기 전에 걸러내는 용도로만 사용하고,
+// 발행량은 MySQL 의 조건부 UPDATE 로 보장한다. Redis 를 사용할 수 없으면 MySQL 만으로 발급한다
+type hybridIssuanceStrategy struct {
+	redis *redisIssuanceStrategy
+	mysql *mysqlIssuanceStrategy
+}
+
+func (s *hybridIssuanceStrategy) Prepare(ctx context.Context, coupon *domain.Coupon) error {
+	return s.redis.Prepare(ctx, coupon)
+}
+
+// Issue 발행량은 MySQL 이 보장하므로 매진 여부도 MySQL 의 남은 재고로 판단한다
+func (s *hybridIssuanceStrategy) Issue(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	issuedCoupon *domain.IssuedCoupon,
+) (bool, error) {
+	// 코드 풀 캠페인은 Redis 에서 꺼낸 코드를 MySQL 에서 발급 처리하므로 Redis 전략과 같은 경로를 사용한다
+	if coupon.UsesCodePool() {
+		return s.redis.issueFromPool(ctx, coupon, issuedCoupon, func() (bool, error) {
+			issuedCoupon.Code = ""
+			return s.mysql.Issue(ctx, coupon, issuedCoupon)
+		})
+	}
+	stockKey, _, err := s.redis.claim(ctx, coupon, issuedCoupon)
+	if errors.Is(err, CacheUnavailableError) {
+		return s.mysql.Issue(ctx, coupon, issuedCoupon)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// 발급 순번은 MySQL 에서 다시 정해지므로 원복할 구성원을 먼저 구한다
+	member := userMember(issuedCoupon)
+	soldOut, err2 := s.mysql.Issue(ctx, coupon, issuedCoupon)
+	if err2 != nil {
+		s.redis.release(ctx, coupon.ID, member, stockKey)
+		return false, err2
+	}
+	return soldOut, nil
+}
+
+func (s *hybridIssuanceStrategy) Remaining(ctx context.Context, coupon *domain.Coupon) (int64, error) {
+	return s.mysql.Remaining(ctx, coupon)
+}
+
+// checkIssuanceStrategy 발급 방식마다 남은 재고를 관리하는 곳이 다르므로(Redis 의 재고 카운터, coupons.remaining)
+// 캠페인을 만들 때와 다른 발급 방식으로는 발급하지 않는다. 발급 방식이 추가되기 전에 만든 캠페인은 Redis 전략으로 만들었다
+func (c *CouponService) checkIssuanceStrategy(coupon *domain.Coupon) error {
+	strategyType := IssuanceStrategyType(coupon.IssuanceStrategy)
+	if strategyType == "" {
+		strategyType = RedisIssuance
+	}
+	if strategyType != c.strategyType {
+		return IssuanceStrategyMismatchError.Wrap(
+			fmt.Errorf("coupon=%s strategy=%s configured=%s", coupon.ID, strategyType, c.strategyType),
+		)
+	}
+	return nil
+}
+
+// issueResult DB 에 저장한 결과를 발급 결과로 바꾼다. 저장한 후 남은 재고가 없으면 매진이다
+func issueResult(remaining int64, err error) (bool, error) {
+	if errors.Is(err, repository.ErrCouponSoldOut) {
+		return false, AllCouponIssuedError
+	}
+	if errors.Is(err, repository.ErrDuplicatedCouponUser) {
+		return false, DuplicatedCouponUserError
+	}
+	if err != nil {
+		return false, IssuedCouponCreationError.Wrap(err)
+	}
+	return remaining == 0, nil
+}
+
+// userMember 사용자 집합의 구성원. 첫 번째 발급은 사용자 ID 를, 이후 발급은 "사용자 ID#순번" 을 사용한다
+func userMember(issuedCoupon *domain.IssuedCoupon) string {
+	if issuedCoupon.Sequence == 0 {
+		return issuedCoupon.UserID
+	}
+	return fmt.Sprintf("%s#%d", issuedCoupon.UserID, issuedCoupon.Sequence)
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// legacyCampaignKey 해시 태그를 사용하기 전의 캠페인 키 (coupon:<id>:data, coupon:<id>:users, coupon:<id>:remaining)
+var legacyCampaignKey = regexp.MustCompile(`^coupon:([^{}:]+):(data|users|remaining)$`)
+
+// MigrateLegacyKeys 해시 태그를 사용하기 전의 캠페인 키를 coupon:{<id>}:* 로 옮기고 옮긴 키의 수를 반환한다
+// 이전 키가 남아 있으면 진행 중인 캠페인이 DataKeyNotFound 로 실패하고 사용자 집합이 비어 중복 발급되므로 요청을 받기 전에 호출한다
+// 새 키가 이미 있으면 새 키를 유지하고 이전 키는 삭제하므로 여러 인스턴스가 동시에 호출해도 된다
+func (c *CouponService) MigrateLegacyKeys(ctx context.Context) (int, error) {
+	if c.cache == nil {
+		return 0, nil
+	}
+	keys, err := c.cache.Scan(ctx, "coupon:*")
+	if err != nil {
+		return 0, CacheUnavailableError.Wrap(err)
+	}
+
+	moved := 0
+	for _, key := range keys {
+		match := legacyCampaignKey.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		ok, err2 := c.cache.Move(ctx, key, fmt.Sprintf("coupon:{%s}:%s", match[1], match[2]))
+		if err2 != nil {
+			return moved, CacheUnavailableError.Wrap(err2)
+		}
+		if ok {
+			moved++
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"coupon-service/internal/test"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyCampaignKey(t *testing.T) {
+	id := uuid.New().String()
+	tests := []struct {
+		name  string
+		key   string
+		match bool
+	}{
+		{"이전 데이터 키", "coupon:" + id + ":data", true},
+		{"이전 사용자 집합", "coupon:" + id + ":users", true},
+		{"이전 재고 카운터", "coupon:" + id + ":remaining", true},
+		{"해시 태그를 사용한 키", genCouponDataKey(id), false},
+		{"하위 카운터", genCouponShardKey(id, 1), false},
+		{"임대 키", genLeaseKey("expiry"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, legacyCampaignKey.MatchString(tt.key))
+		})
+	}
+}
+
+func TestMigrateLegacyKeysWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	client := redisContainer.Client
+	service := NewCouponService(client, nil, nil)
+	now := time.Now()
+
+	id := uuid.New().String()
+	data, err := json.Marshal(map[string]any{
+		"id": id, "name": "이전 쿠폰", "issue_amount": 10,
+		"issued_at": now.Add(-time.Hour), "expires_at": now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "coupon:"+id+":data", data, 0).Err())
+	require.NoError(t, client.Set(ctx, "coupon:"+id+":remaining", 7, 0).Err())
+	require.NoError(t, client.SAdd(ctx, "coupon:"+id+":users", "user-1", "user-2", "user-3").Err())
+
+	t.Run("이전 키를 새 키로 옮겨야 한다", func(t *testing.T) {
+		moved, err := service.MigrateLegacyKeys(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, moved)
+		remaining, err := client.Get(ctx, genCouponAmountKey(id)).Int64()
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), remaining)
+		isMember, err := client.SIsMember(ctx, genCouponUserKey(id), "user-2").Result()
+		require.NoError(t, err)
+		assert.True(t, isMember)
+		exists, err := client.Exists(ctx, "coupon:"+id+":data", "coupon:"+id+":remaining", "coupon:"+id+":users").Result()
+		require.NoError(t, err)
+		assert.Zero(t, exists)
+	})
+
+	t.Run("옮긴 캠페인을 조회할 수 있어야 한다", func(t *testing.T) {
+		_, err := service.validateCouponEvent(ctx, id, now)
+		require.NoError(t, err)
+	})
+
+	t.Run("새 키가 이미 있으면 새 키를 유지해야 한다", func(t *testing.T) {
+		require.NoError(t, client.Set(ctx, "coupon:"+id+":remaining", 100, 0).Err())
+
+		moved, err := service.MigrateLegacyKeys(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, moved)
+		remaining, err := client.Get(ctx, genCouponAmountKey(id)).Int64()
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), remaining)
+	})
+}
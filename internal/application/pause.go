@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/repository"
+	"errors"
+	"time"
+)
+
+// PauseCampaign 캠페인의 발급을 일시 중지한다. 중지한 동안의 발급 요청은 CampaignPausedError 로 실패한다
+// 이미 중지된 캠페인이면 상태를 바꾸지 않고 이벤트도 발행하지 않는다
+func (c *CouponService) PauseCampaign(ctx context.Context, couponId string) (*domain.Coupon, error) {
+	now := time.Now()
+	return c.setPaused(ctx, couponId, &now, domain.CampaignPaused)
+}
+
+// ResumeCampaign 일시 중지한 캠페인의 발급을 다시 시작한다
+// 중지되지 않은 캠페인이면 상태를 바꾸지 않고 이벤트도 발행하지 않는다
+func (c *CouponService) ResumeCampaign(ctx context.Context, couponId string) (*domain.Coupon, error) {
+	return c.setPaused(ctx, couponId, nil, domain.CampaignResumed)
+}
+
+// setPaused DB 에 기록한 후 Redis 의 캠페인 데이터를 갱신하고 모든 인스턴스의 로컬 캐시와 캠페인 상태 스트림에 알린다
+// Redis 갱신에 실패해도 다시 요청하면 갱신하도록 상태가 바뀌지 않았더라도 캠페인 데이터는 항상 갱신한다
+func (c *CouponService) setPaused(
+	ctx context.Context,
+	couponId string,
+	pausedAt *time.Time,
+	eventType domain.EventType,
+) (*domain.Coupon, error) {
+	changed, err := c.couponRepository.SetPaused(couponId, pausedAt)
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		return nil, CouponNotFoundError.Wrap(err)
+	}
+	if err != nil {
+		return nil, CampaignPauseError.Wrap(err)
+	}
+	coupon, err := c.couponRepository.FindOne(couponId)
+	if err != nil {
+		return nil, CampaignPauseError.Wrap(err)
+	}
+
+	if c.cache != nil {
+		if err = c.cache.Set(ctx, genCouponDataKey(coupon.ID), coupon); err != nil {
+			return nil, CouponCacheError.Wrap(err)
+		}
+	}
+	c.invalidateCouponData(ctx, coupon.ID)
+	if changed {
+		c.publish(ctx, domain.NewCampaignEvent(eventType, coupon, time.Now()))
+	}
+	return coupon, nil
+}
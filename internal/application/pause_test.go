@@ -0,0 +1,88 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/event"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseCampaignWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	publisher := event.NewMemoryPublisher()
+	// 다른 인스턴스에서 중지해도 로컬 캐시와 스트림에 반영되어야 한다
+	admin := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo, WithEventPublisher(publisher))
+	issuer := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go issuer.ListenCouponInvalidation(listenCtx)
+	now := time.Now()
+
+	coupon, err := admin.CreateCoupon(ctx, "중지 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = issuer.Issue(ctx, coupon.ID, uuid.New().String())
+	require.NoError(t, err)
+
+	snapshots := make(chan CampaignSnapshot, 10)
+	go func() {
+		_ = issuer.WatchCampaign(listenCtx, coupon.ID, func(snapshot CampaignSnapshot) error {
+			snapshots <- snapshot
+			return nil
+		})
+	}()
+	assert.Equal(t, domain.CampaignStatusOpen, (<-snapshots).Status)
+	// 구독이 시작되기 전의 변경은 전달되지 않으므로 잠시 기다린다
+	time.Sleep(100 * time.Millisecond)
+
+	t.Run("일시 중지하면 발급을 거절하고 스트림에 중지 상태를 보내야 한다", func(t *testing.T) {
+		paused, err := admin.PauseCampaign(ctx, coupon.ID)
+		require.NoError(t, err)
+		assert.True(t, paused.Paused())
+
+		select {
+		case snapshot := <-snapshots:
+			assert.Equal(t, domain.CampaignStatusPaused, snapshot.Status)
+		case <-time.After(5 * time.Second):
+			t.Fatal("중지 상태를 받지 못했습니다")
+		}
+		_, err = issuer.Issue(ctx, coupon.ID, uuid.New().String())
+		assert.ErrorIs(t, err, CampaignPausedError)
+	})
+
+	t.Run("이미 중지된 캠페인을 다시 중지하면 이벤트를 발행하지 않아야 한다", func(t *testing.T) {
+		_, err := admin.PauseCampaign(ctx, coupon.ID)
+		require.NoError(t, err)
+
+		assert.Len(t, publisher.EventsOf(domain.CampaignPaused), 1)
+	})
+
+	t.Run("발급을 재개하면 다시 발급해야 한다", func(t *testing.T) {
+		resumed, err := admin.ResumeCampaign(ctx, coupon.ID)
+		require.NoError(t, err)
+		assert.False(t, resumed.Paused())
+
+		require.Eventually(t, func() bool {
+			_, err := issuer.Issue(ctx, coupon.ID, uuid.New().String())
+			return err == nil
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.Len(t, publisher.EventsOf(domain.CampaignResumed), 1)
+	})
+
+	t.Run("없는 캠페인은 중지할 수 없어야 한다", func(t *testing.T) {
+		_, err := admin.PauseCampaign(ctx, uuid.New().String())
+
+		assert.ErrorIs(t, err, CouponNotFoundError)
+	})
+}
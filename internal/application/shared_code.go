@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func isCouponID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+// findSharedCodeCouponID 공용 코드를 사용하는 캠페인의 ID
+// 공용 코드와 캠페인의 관계는 바뀌지 않으므로 로컬 캐시에 보관한다
+func (c *CouponService) findSharedCodeCouponID(code string) (string, error) {
+	code = domain.NormalizeSharedCode(code)
+	if code == "" {
+		return "", CouponNotFoundError
+	}
+	if couponId, ok := c.sharedCodes.Get(code); ok {
+		return couponId, nil
+	}
+
+	couponId, err := c.couponRepository.FindIDBySharedCode(code)
+	if errors.Is(err, repository.ErrCouponNotFound) {
+		return "", CouponNotFoundError.Wrap(err)
+	}
+	if err != nil {
+		return "", CouponLookupError.Wrap(err)
+	}
+	c.sharedCodes.Set(code, couponId)
+	return couponId, nil
+}
+
+// Redeem 쿠폰을 사용 처리한다
+// campaignId 가 비어있거나 code 가 캠페인의 공용 코드이면 공용 코드로 발급받으면서 바로 사용 처리하고,
+// 그 외에는 사용자에게 발급된 쿠폰 중 코드가 일치하는 쿠폰을 사용 처리한다
+func (c *CouponService) Redeem(
+	ctx context.Context,
+	campaignId string,
+	code string,
+	userId string,
+	opts ...IssueOption,
+) (*domain.IssuedCoupon, error) {
+	if campaignId == "" {
+		couponId, err := c.findSharedCodeCouponID(code)
+		if err != nil {
+			return nil, err
+		}
+		return c.issue(ctx, couponId, userId, true, opts...)
+	}
+
+	coupon, err := c.loadCouponData(ctx, campaignId)
+	if err != nil {
+		return nil, err
+	}
+	if coupon.UsesSharedCode() && domain.NormalizeSharedCode(code) == coupon.SharedCode {
+		return c.issue(ctx, campaignId, userId, true, opts...)
+	}
+
+	issuedCoupon, err := c.issuedCouponRepository.Redeem(campaignId, strings.TrimSpace(code), userId, time.Now())
+	if errors.Is(err, repository.ErrIssuedCouponNotFound) {
+		return nil, IssuedCouponNotFoundError.Wrap(err)
+	}
+	if errors.Is(err, repository.ErrCouponAlreadyRedeemed) {
+		return nil, CouponAlreadyRedeemedError.Wrap(err)
+	}
+	if errors.Is(err, repository.ErrCouponNotUsable) {
+		return nil, CouponNotUsableError.Wrap(err)
+	}
+	if err != nil {
+		return nil, RedeemError.Wrap(err)
+	}
+	c.publish(ctx, domain.NewCouponEvent(domain.CouponRedeemed, issuedCoupon, *issuedCoupon.RedeemedAt))
+	return issuedCoupon, nil
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSharedCodeWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	now := time.Now()
+
+	for _, strategy := range []IssuanceStrategyType{RedisIssuance, MySQLIssuance, HybridIssuance} {
+		t.Run(string(strategy)+" 전략은 공용 코드로 사용자당 횟수와 전체 한도까지 발급해야 한다", func(t *testing.T) {
+			couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo, WithIssuanceStrategy(strategy))
+			code := "SPRING-" + string(strategy)
+			coupon, err := couponService.CreateCoupon(ctx, "봄 프로모션", 3, now.Add(-time.Hour), now.Add(time.Hour),
+				domain.WithSharedCode(code, 2))
+			require.NoError(t, err)
+
+			userID := uuid.New().String()
+			require.NoError(t, couponService.IssueCoupon(ctx, code, userID))
+			redeemed, err := couponService.Redeem(ctx, "", code, userID)
+			require.NoError(t, err)
+			assert.Equal(t, coupon.ID, redeemed.CouponID)
+			assert.Equal(t, domain.IssuedCouponRedeemed, redeemed.Status)
+			assert.ErrorIs(t, couponService.IssueCoupon(ctx, code, userID), DuplicatedCouponUserError)
+
+			require.NoError(t, couponService.IssueCoupon(ctx, code, uuid.New().String()))
+			assert.ErrorIs(t, couponService.IssueCoupon(ctx, code, uuid.New().String()), AllCouponIssuedError)
+			assert.Len(t, issuedCouponRepo.FindByCouponId(coupon.ID), 3)
+		})
+	}
+
+	for _, strategy := range []IssuanceStrategyType{RedisIssuance, MySQLIssuance, HybridIssuance} {
+		t.Run(string(strategy)+" 전략은 같은 사용자가 공용 코드를 동시에 사용해도 사용자당 횟수까지 사용 처리해야 한다", func(t *testing.T) {
+			couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo, WithIssuanceStrategy(strategy))
+			code := "AUTUMN-" + string(strategy)
+			coupon, err := couponService.CreateCoupon(ctx, "가을 프로모션", 10, now.Add(-time.Hour), now.Add(time.Hour),
+				domain.WithSharedCode(code, 3))
+			require.NoError(t, err)
+
+			userID := uuid.New().String()
+			errs := runConcurrently(5, func() error {
+				_, err := couponService.Redeem(ctx, "", code, userID)
+				return err
+			})
+
+			var redeemedCount int
+			for _, err := range errs {
+				if err == nil {
+					redeemedCount++
+					continue
+				}
+				assert.ErrorIs(t, err, DuplicatedCouponUserError)
+			}
+			assert.Equal(t, 3, redeemedCount)
+			issuedCoupons := issuedCouponRepo.FindByCouponId(coupon.ID)
+			require.Len(t, issuedCoupons, 3)
+			for _, issued := range issuedCoupons {
+				assert.Equal(t, domain.IssuedCouponRedeemed, issued.Status)
+			}
+		})
+	}
+
+	t.Run("이미 사용 중인 공용 코드로는 캠페인을 만들 수 없어야 한다", func(t *testing.T) {
+		couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+		_, err := couponService.CreateCoupon(ctx, "여름 프로모션", 10, now, now.Add(time.Hour), domain.WithSharedCode("SUMMER", 1))
+		require.NoError(t, err)
+
+		_, err = couponService.CreateCoupon(ctx, "여름 프로모션", 10, now, now.Add(time.Hour), domain.WithSharedCode("summer", 1))
+
+		assert.ErrorIs(t, err, SharedCodeConflictError)
+	})
+
+	t.Run("발급받은 고유 코드는 한 번만 사용할 수 있어야 한다", func(t *testing.T) {
+		couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+		coupon, err := couponService.CreateCoupon(ctx, "일반 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+		userID := uuid.New().String()
+		require.NoError(t, couponService.IssueCoupon(ctx, coupon.ID, userID))
+		code := issuedCouponRepo.FindByCouponId(coupon.ID)[0].Code
+
+		_, err = couponService.Redeem(ctx, coupon.ID, code, uuid.New().String())
+		assert.ErrorIs(t, err, IssuedCouponNotFoundError)
+
+		redeemed, err := couponService.Redeem(ctx, coupon.ID, code, userID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.IssuedCouponRedeemed, redeemed.Status)
+		assert.NotNil(t, redeemed.RedeemedAt)
+
+		_, err = couponService.Redeem(ctx, coupon.ID, code, userID)
+		assert.ErrorIs(t, err, CouponAlreadyRedeemedError)
+	})
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// issuanceTracker 처리 중인 발급 수
+// 종료를 시작하면 새 발급을 거절하고, 처리 중인 발급이 모두 끝나면 idle 을 닫는다
+type issuanceTracker struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight int
+	idle     chan struct{}
+}
+
+// begin 종료를 시작했으면 false 를 반환한다. true 를 반환하면 발급이 끝난 뒤 end 를 호출해야 한다
+func (t *issuanceTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	t.inflight++
+	return true
+}
+
+func (t *issuanceTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inflight--
+	if t.closed && t.inflight == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// close 새 발급을 거절하고 처리 중인 발급이 끝나기를 기다린다
+func (t *issuanceTracker) close(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	if t.inflight == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return fmt.Errorf("%d issuances are still in flight: %w", t.inflight, ctx.Err())
+	}
+}
+
+// StopWatches 캠페인 상태 스트림을 모두 끝낸다
+// 서버를 종료할 때 클라이언트가 연결을 끊을 때까지 기다리지 않도록 처리 중인 요청을 기다리기 전에 호출한다
+func (c *CouponService) StopWatches() {
+	c.stockWatchers.stop()
+}
+
+// Shutdown 새 발급을 거절하고 처리 중인 발급이 끝나기를 기다린다
+// Redis 와 DB 연결을 닫기 전에 호출한다. ctx 가 끝날 때까지 끝나지 않은 발급이 있으면 에러를 반환한다
+// Redis 장애 중 DB 로 발급된 내역은 MySQL 에 남아 있으므로 다른 인스턴스가 반영한다
+func (c *CouponService) Shutdown(ctx context.Context) error {
+	c.StopWatches()
+	return c.issuances.close(ctx)
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	newService := func() *CouponService {
+		return NewCouponService(nil, nil, nil, WithIssuanceStrategy(MySQLIssuance))
+	}
+
+	t.Run("처리 중인 발급이 끝날 때까지 기다린 뒤 새 발급은 거절해야 한다", func(t *testing.T) {
+		service := newService()
+		require.True(t, service.issuances.begin())
+
+		done := make(chan error, 1)
+		go func() {
+			done <- service.Shutdown(context.Background())
+		}()
+		select {
+		case <-done:
+			t.Fatal("shutdown returned before the issuance finished")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		service.issuances.end()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("shutdown did not return after the issuance finished")
+		}
+
+		_, err := service.Issue(context.Background(), uuid.New().String(), "user")
+		assert.ErrorIs(t, err, ServiceShuttingDownError)
+	})
+
+	t.Run("제한 시간 안에 발급이 끝나지 않으면 에러를 반환해야 한다", func(t *testing.T) {
+		service := newService()
+		require.True(t, service.issuances.begin())
+		defer service.issuances.end()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := service.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("여러 번 호출해도 에러 없이 끝나야 한다", func(t *testing.T) {
+		service := newService()
+
+		assert.NoError(t, service.Shutdown(context.Background()))
+		assert.NoError(t, service.Shutdown(context.Background()))
+	})
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUsageValidityWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo, WithIssuanceStrategy(MySQLIssuance))
+	now := time.Now()
+
+	t.Run("발급된 쿠폰은 발급 후 사용 기간까지 사용할 수 있어야 한다", func(t *testing.T) {
+		coupon, err := couponService.CreateCoupon(ctx, "3일 쿠폰", 10, now.Add(-time.Hour), now.Add(7*24*time.Hour),
+			domain.WithUsageValidity(domain.UsageValidity{Duration: 72 * time.Hour}))
+		require.NoError(t, err)
+		userID := uuid.New().String()
+
+		issued, err := couponService.Issue(ctx, coupon.ID, userID)
+		require.NoError(t, err)
+		require.NotNil(t, issued.ValidUntil)
+		assert.WithinDuration(t, issued.CreatedAt.Add(72*time.Hour), *issued.ValidUntil, time.Second)
+
+		stored := issuedCouponRepo.FindByCouponId(coupon.ID)[0]
+		assert.WithinDuration(t, *issued.ValidUntil, *stored.ValidUntil, time.Second)
+	})
+
+	t.Run("사용 기간이 시작되기 전의 쿠폰은 사용할 수 없어야 한다", func(t *testing.T) {
+		from := now.Add(24 * time.Hour)
+		coupon, err := couponService.CreateCoupon(ctx, "다음 주 쿠폰", 10, now.Add(-time.Hour), now.Add(time.Hour),
+			domain.WithUsageValidity(domain.UsageValidity{From: &from}))
+		require.NoError(t, err)
+		userID := uuid.New().String()
+		issued, err := couponService.Issue(ctx, coupon.ID, userID)
+		require.NoError(t, err)
+
+		_, err = couponService.Redeem(ctx, coupon.ID, issued.Code, userID)
+
+		assert.ErrorIs(t, err, CouponNotUsableError)
+	})
+}
@@ -0,0 +1,250 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+// stockChannel 발급으로 재고가 바뀐 캠페인 ID 를 모든 인스턴스에 알리는 채널
+const stockChannel = "coupon:stock"
+
+// WatchConfig 캠페인 상태 스트림의 설정
+//   - MaxUpdatesPerSecond: 한 스트림에 보내는 초당 최대 갱신 수. 그 사이의 재고 변경은 하나로 합쳐 보낸다
+//   - Heartbeat: 변경이 없어도 서버 시각을 보내는 간격
+type WatchConfig struct {
+	MaxUpdatesPerSecond int
+	Heartbeat           time.Duration
+}
+
+var DefaultWatchConfig = WatchConfig{
+	MaxUpdatesPerSecond: 5,
+	Heartbeat:           10 * time.Second,
+}
+
+// WithWatchConfig 캠페인 상태 스트림의 설정을 변경한다
+func WithWatchConfig(config WatchConfig) Option {
+	return func(c *CouponService) {
+		c.watchConfig = config
+	}
+}
+
+// CampaignSnapshot 캠페인 상태 스트림으로 보내는 한 시점의 상태
+type CampaignSnapshot struct {
+	CampaignID string
+	Remaining  int64
+	Status     domain.CampaignStatus
+	ServerTime time.Time
+}
+
+// stockWatchers 이 인스턴스에서 캠페인 상태를 구독 중인 스트림
+// 알림 채널은 버퍼가 1 이므로 스트림이 처리하기 전의 알림은 하나로 합쳐진다
+// reads 는 캠페인별 최근 남은 재고 조회로, 같은 캠페인의 스트림들이 함께 사용한다
+// stopped 는 종료를 시작하면 닫혀 모든 스트림을 끝낸다
+type stockWatchers struct {
+	mu       sync.Mutex
+	watchers map[string]map[chan struct{}]struct{}
+	reads    map[string]*remainingRead
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// remainingRead 남은 재고 조회 한 번의 결과. done 이 닫힌 후에 나머지 값을 읽는다
+type remainingRead struct {
+	done      chan struct{}
+	remaining int64
+	err       error
+	readAt    time.Time
+}
+
+func newStockWatchers() *stockWatchers {
+	return &stockWatchers{
+		watchers: make(map[string]map[chan struct{}]struct{}),
+		reads:    make(map[string]*remainingRead),
+		stopped:  make(chan struct{}),
+	}
+}
+
+func (w *stockWatchers) stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+	})
+}
+
+func (w *stockWatchers) add(couponId string) chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	if w.watchers[couponId] == nil {
+		w.watchers[couponId] = make(map[chan struct{}]struct{})
+	}
+	w.watchers[couponId][ch] = struct{}{}
+	return ch
+}
+
+func (w *stockWatchers) remove(couponId string, ch chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.watchers[couponId], ch)
+	if len(w.watchers[couponId]) == 0 {
+		delete(w.watchers, couponId)
+		delete(w.reads, couponId)
+	}
+}
+
+// remaining 같은 캠페인의 스트림 수와 관계없이 남은 재고를 maxAge 마다 한 번만 read 로 조회한다
+// 다른 스트림이 조회 중이면 그 결과를 기다리고, maxAge 안에 성공한 조회가 있으면 그 결과를 반환한다
+func (w *stockWatchers) remaining(couponId string, maxAge time.Duration, read func() (int64, error)) (int64, error) {
+	w.mu.Lock()
+	current, ok := w.reads[couponId]
+	if ok {
+		select {
+		case <-current.done:
+			ok = current.err == nil && time.Since(current.readAt) < maxAge
+		default:
+		}
+	}
+	if ok {
+		w.mu.Unlock()
+		<-current.done
+		return current.remaining, current.err
+	}
+	current = &remainingRead{done: make(chan struct{})}
+	w.reads[couponId] = current
+	w.mu.Unlock()
+
+	current.remaining, current.err = read()
+	current.readAt = time.Now()
+	close(current.done)
+	return current.remaining, current.err
+}
+
+func (w *stockWatchers) notify(couponId string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.watchers[couponId] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// notifyStockChanged Redis 를 사용하지 않으면 이 인스턴스의 스트림에만 알린다
+func (c *CouponService) notifyStockChanged(ctx context.Context, couponId string) {
+	if c.cache == nil {
+		c.stockWatchers.notify(couponId)
+		return
+	}
+	if err := c.cache.Publish(context.WithoutCancel(ctx), stockChannel, couponId); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// ListenStockChanges 다른 인스턴스를 포함한 발급으로 재고가 바뀌면 이 인스턴스의 스트림에 알린다
+// ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) ListenStockChanges(ctx context.Context) {
+	if c.cache == nil {
+		return
+	}
+	for couponId := range c.cache.Subscribe(ctx, stockChannel) {
+		c.stockWatchers.notify(couponId)
+	}
+}
+
+// WatchCampaign 캠페인의 현재 상태를 보낸 뒤 재고나 발급 상태가 바뀔 때마다 send 로 보낸다
+// 재고 변경은 초당 MaxUpdatesPerSecond 번까지로 합쳐 보내며, ctx 가 종료되거나 send 가 실패할 때까지 블로킹된다
+// 남은 재고는 스트림마다 조회하지 않고 이 인스턴스에서 캠페인마다 초당 MaxUpdatesPerSecond 번까지만 조회한다
+// StopWatches 로 종료를 시작하면 스트림을 끝내고, 이후의 요청은 ServiceShuttingDownError 로 실패한다
+func (c *CouponService) WatchCampaign(
+	ctx context.Context,
+	couponId string,
+	send func(CampaignSnapshot) error,
+) error {
+	select {
+	case <-c.stockWatchers.stopped:
+		return ServiceShuttingDownError
+	default:
+	}
+	if _, err := c.loadCouponData(ctx, couponId); err != nil {
+		return err
+	}
+	updates := c.stockWatchers.add(couponId)
+	defer c.stockWatchers.remove(couponId, updates)
+
+	interval := time.Second / time.Duration(max(c.watchConfig.MaxUpdatesPerSecond, 1))
+	heartbeat := time.NewTicker(max(c.watchConfig.Heartbeat, interval))
+	defer heartbeat.Stop()
+	// flush 가 만료되면 상태를 보낸다. 처음에는 바로 현재 상태를 보낸다
+	flush := time.NewTimer(0)
+	defer flush.Stop()
+	statusChange := time.NewTimer(0)
+	statusChange.Stop()
+	defer statusChange.Stop()
+
+	pending := true
+	var lastSent time.Time
+	schedule := func() {
+		if pending {
+			return
+		}
+		pending = true
+		flush.Reset(max(interval-time.Since(lastSent), 0))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.stockWatchers.stopped:
+			return nil
+		case <-updates:
+			schedule()
+		case <-heartbeat.C:
+			schedule()
+		case <-statusChange.C:
+			schedule()
+		case <-flush.C:
+			pending = false
+			// 일시 중지처럼 캠페인 데이터가 바뀌면 로컬 캐시가 비워지므로 매번 다시 읽는다
+			coupon, err := c.loadCouponData(ctx, couponId)
+			if err != nil {
+				return err
+			}
+			snapshot, err := c.campaignSnapshot(ctx, coupon, interval)
+			if err != nil {
+				return err
+			}
+			if err = send(snapshot); err != nil {
+				return err
+			}
+			lastSent = time.Now()
+			heartbeat.Reset(max(c.watchConfig.Heartbeat, interval))
+			if next, ok := coupon.NextStatusChange(snapshot.ServerTime); ok {
+				statusChange.Reset(next.Sub(snapshot.ServerTime))
+			}
+		}
+	}
+}
+
+func (c *CouponService) campaignSnapshot(
+	ctx context.Context,
+	coupon *domain.Coupon,
+	maxAge time.Duration,
+) (CampaignSnapshot, error) {
+	// 조회를 시작한 스트림이 끝나도 결과를 기다리는 다른 스트림이 있으므로 취소를 전파하지 않는다
+	remaining, err := c.stockWatchers.remaining(coupon.ID, maxAge, func() (int64, error) {
+		return c.strategy.Remaining(context.WithoutCancel(ctx), coupon)
+	})
+	if err != nil {
+		return CampaignSnapshot{}, err
+	}
+	now := time.Now()
+	return CampaignSnapshot{
+		CampaignID: coupon.ID,
+		Remaining:  remaining,
+		Status:     coupon.Status(now, remaining),
+		ServerTime: now,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/test"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRemainingStrategy 남은 재고만 조회하는 발급 전략
+type stubRemainingStrategy struct {
+	IssuanceStrategy
+	remaining atomic.Int64
+	calls     atomic.Int32
+}
+
+func (s *stubRemainingStrategy) Remaining(context.Context, *domain.Coupon) (int64, error) {
+	s.calls.Add(1)
+	return s.remaining.Load(), nil
+}
+
+func TestWatchCampaign(t *testing.T) {
+	now := time.Now()
+	newService := func(config WatchConfig, coupon *domain.Coupon) (*CouponService, *stubRemainingStrategy) {
+		service := NewCouponService(nil, nil, nil, WithIssuanceStrategy(MySQLIssuance), WithWatchConfig(config))
+		strategy := &stubRemainingStrategy{}
+		service.strategy = strategy
+		service.coupons.Set(coupon.ID, coupon)
+		return service, strategy
+	}
+	watch := func(service *CouponService, couponId string, timeout time.Duration) <-chan CampaignSnapshot {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		snapshots := make(chan CampaignSnapshot, 100)
+		go func() {
+			defer cancel()
+			defer close(snapshots)
+			_ = service.WatchCampaign(ctx, couponId, func(snapshot CampaignSnapshot) error {
+				snapshots <- snapshot
+				return nil
+			})
+		}()
+		return snapshots
+	}
+
+	t.Run("현재 상태를 먼저 보내고 재고 변경은 초당 최대 갱신 수로 합쳐 보내야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+		service, strategy := newService(WatchConfig{MaxUpdatesPerSecond: 5, Heartbeat: time.Hour}, coupon)
+		strategy.remaining.Store(100)
+
+		snapshots := watch(service, coupon.ID, time.Second)
+		first := <-snapshots
+		assert.Equal(t, int64(100), first.Remaining)
+		assert.Equal(t, domain.CampaignStatusOpen, first.Status)
+
+		for i := 99; i >= 0; i-- {
+			strategy.remaining.Store(int64(i))
+			service.notifyStockChanged(context.Background(), coupon.ID)
+			time.Sleep(5 * time.Millisecond)
+		}
+		var last CampaignSnapshot
+		var count int
+		for snapshot := range snapshots {
+			last = snapshot
+			count++
+		}
+		// 약 0.5초 동안의 변경 100 번을 200ms 간격으로 합쳐 보낸다
+		assert.LessOrEqual(t, count, 5)
+		assert.Equal(t, int64(0), last.Remaining)
+		assert.Equal(t, domain.CampaignStatusSoldOut, last.Status)
+	})
+
+	t.Run("같은 캠페인의 스트림들은 남은 재고 조회를 함께 사용해야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+		service, strategy := newService(WatchConfig{MaxUpdatesPerSecond: 5, Heartbeat: time.Hour}, coupon)
+		strategy.remaining.Store(100)
+
+		streams := make([]<-chan CampaignSnapshot, 10)
+		for i := range streams {
+			streams[i] = watch(service, coupon.ID, time.Second)
+		}
+		for i := 99; i >= 0; i-- {
+			strategy.remaining.Store(int64(i))
+			service.notifyStockChanged(context.Background(), coupon.ID)
+			time.Sleep(5 * time.Millisecond)
+		}
+		for _, snapshots := range streams {
+			for range snapshots {
+			}
+		}
+
+		// 스트림마다 조회하면 처음 상태만으로도 10 번을 조회한다
+		assert.LessOrEqual(t, strategy.calls.Load(), int32(7))
+	})
+
+	t.Run("일시 중지된 캠페인은 중지 상태를 보내야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+		service, strategy := newService(WatchConfig{MaxUpdatesPerSecond: 5, Heartbeat: time.Hour}, coupon)
+		strategy.remaining.Store(10)
+
+		snapshots := watch(service, coupon.ID, time.Second)
+		assert.Equal(t, domain.CampaignStatusOpen, (<-snapshots).Status)
+
+		paused := *coupon
+		paused.PausedAt = &now
+		service.coupons.Set(coupon.ID, &paused)
+		service.stockWatchers.notify(coupon.ID)
+
+		assert.Equal(t, domain.CampaignStatusPaused, (<-snapshots).Status)
+	})
+
+	t.Run("발급 시작 시각이 되면 변경이 없어도 상태를 보내야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: time.Now().Add(300 * time.Millisecond), ExpiresAt: now.Add(time.Hour)}
+		service, strategy := newService(WatchConfig{MaxUpdatesPerSecond: 5, Heartbeat: time.Hour}, coupon)
+		strategy.remaining.Store(10)
+
+		snapshots := watch(service, coupon.ID, time.Second)
+
+		assert.Equal(t, domain.CampaignStatusScheduled, (<-snapshots).Status)
+		assert.Equal(t, domain.CampaignStatusOpen, (<-snapshots).Status)
+	})
+
+	t.Run("변경이 없으면 heartbeat 간격마다 서버 시각을 보내야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+		service, _ := newService(WatchConfig{MaxUpdatesPerSecond: 10, Heartbeat: 200 * time.Millisecond}, coupon)
+
+		snapshots := watch(service, coupon.ID, 700*time.Millisecond)
+		var times []time.Time
+		for snapshot := range snapshots {
+			times = append(times, snapshot.ServerTime)
+		}
+
+		require.GreaterOrEqual(t, len(times), 3)
+		assert.True(t, times[len(times)-1].After(times[0]))
+	})
+
+	t.Run("스트림이 종료되면 구독을 해제해야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+		service, _ := newService(DefaultWatchConfig, coupon)
+
+		for range watch(service, coupon.ID, 50*time.Millisecond) {
+		}
+
+		service.stockWatchers.mu.Lock()
+		defer service.stockWatchers.mu.Unlock()
+		assert.Empty(t, service.stockWatchers.watchers)
+		assert.Empty(t, service.stockWatchers.reads)
+	})
+
+	t.Run("종료를 시작하면 스트림을 끝내고 새 스트림은 거절해야 한다", func(t *testing.T) {
+		coupon := &domain.Coupon{ID: uuid.New().String(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}
+		service, _ := newService(DefaultWatchConfig, coupon)
+
+		snapshots := watch(service, coupon.ID, time.Minute)
+		<-snapshots
+		service.StopWatches()
+
+		select {
+		case _, ok := <-snapshots:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("stream was not stopped")
+		}
+		err := service.WatchCampaign(context.Background(), coupon.ID, func(CampaignSnapshot) error { return nil })
+		assert.ErrorIs(t, err, ServiceShuttingDownError)
+	})
+}
+
+func TestWatchCampaignWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(&entity.CouponEntity{}, &entity.IssuedCouponEntity{})
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	// 다른 인스턴스에서 발급해도 Redis 채널로 전달되어야 한다
+	watcher := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+	issuer := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo)
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go watcher.ListenStockChanges(listenCtx)
+	now := time.Now()
+
+	coupon, err := issuer.CreateCoupon(ctx, "스트림 쿠폰", 2, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	snapshots := make(chan CampaignSnapshot, 10)
+	go func() {
+		_ = watcher.WatchCampaign(listenCtx, coupon.ID, func(snapshot CampaignSnapshot) error {
+			snapshots <- snapshot
+			return nil
+		})
+	}()
+	assert.Equal(t, int64(2), (<-snapshots).Remaining)
+	// 구독이 시작되기 전의 발급은 전달되지 않으므로 잠시 기다린다
+	time.Sleep(100 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		_, err = issuer.Issue(ctx, coupon.ID, uuid.New().String())
+		require.NoError(t, err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case snapshot := <-snapshots:
+			if snapshot.Remaining == 0 {
+				assert.Equal(t, domain.CampaignStatusSoldOut, snapshot.Status)
+				return
+			}
+		case <-timeout:
+			t.Fatal("재고 변경을 받지 못했습니다")
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package application
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/infrastructure/webhook"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// WebhookConfig 웹훅 전달 작업의 설정
+//   - Interval: 전달할 웹훅을 확인하는 간격. 0 이면 전달하지 않는다
+//   - BatchSize: 한 번에 가져오는 전달 수
+//   - Concurrency: 동시에 보내는 요청 수
+//   - MaxAttempts: 이 횟수만큼 실패하면 dead letter 로 옮긴다
+//   - BackoffBase, BackoffMax: n 번째 실패 후 BackoffBase * 2^(n-1) 만큼 기다리며 BackoffMax 를 넘지 않는다
+//   - Timeout: 수신 서버의 응답을 기다리는 시간
+type WebhookConfig struct {
+	Interval    time.Duration
+	BatchSize   int
+	Concurrency int
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Timeout     time.Duration
+}
+
+var DefaultWebhookConfig = WebhookConfig{
+	Interval:    5 * time.Second,
+	BatchSize:   100,
+	Concurrency: 8,
+	MaxAttempts: 8,
+	BackoffBase: 10 * time.Second,
+	BackoffMax:  time.Hour,
+	Timeout:     webhook.DefaultTimeout,
+}
+
+// backoff attempts 번 실패한 후 다음 전달까지 기다리는 시간
+func (w WebhookConfig) backoff(attempts int) time.Duration {
+	delay := w.BackoffBase
+	for i := 1; i < attempts && delay < w.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, w.BackoffMax)
+}
+
+// claimLease 가져온 묶음을 모두 보낼 때까지 다른 인스턴스가 같은 전달을 가져가지 않도록 미루는 시간
+func (w WebhookConfig) claimLease() time.Duration {
+	concurrency := max(w.Concurrency, 1)
+	rounds := (w.BatchSize + concurrency - 1) / concurrency
+	return w.Timeout*time.Duration(rounds) + time.Minute
+}
+
+// WebhookDispatchResult 한 번의 실행에서 처리한 전달 수
+//   - Retrying: 실패하여 재시도를 기다리는 전달 수
+//   - Dead: 최대 재시도 횟수를 넘겨 dead letter 로 옮긴 전달 수
+type WebhookDispatchResult struct {
+	Delivered int
+	Retrying  int
+	Dead      int
+}
+
+// WebhookDeliveryFilter 웹훅 전달 목록의 조회 조건. 비어있는 조건은 적용하지 않는다
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	CouponID       string
+	Status         domain.WebhookDeliveryStatus
+	Limit          int
+}
+
+// WithWebhooks 캠페인의 이벤트를 구독한 제휴사 URL 로 전달한다. WithEventOutbox 와 함께 사용한다
+// RelayEvents 가 커밋된 발행 대기열의 이벤트를 전달 대기열에 넣고, RunWebhookDispatcher 가 재시도하며 전달한다
+func WithWebhooks(webhookRepository *repository.WebhookRepository, config WebhookConfig) Option {
+	return func(c *CouponService) {
+		c.webhookRepository = webhookRepository
+		c.webhookConfig = config
+	}
+}
+
+// enqueueWebhooks 전달은 구독과 이벤트 ID 마다 하나만 저장하므로 같은 이벤트를 다시 넣어도 중복되지 않는다
+func (c *CouponService) enqueueWebhooks(events []domain.Event) error {
+	now := time.Now()
+	var deliveries []domain.WebhookDelivery
+	for _, event := range events {
+		subscriptions, err := c.webhookSubscriptionsOf(event.Key)
+		if err != nil {
+			return err
+		}
+		for i := range subscriptions {
+			if !subscriptions[i].Subscribes(event.Type) {
+				continue
+			}
+			delivery, err := domain.NewWebhookDelivery(&subscriptions[i], event, now)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return c.webhookRepository.EnqueueDeliveries(deliveries)
+}
+
+// webhookSubscriptionsOf 이벤트마다 DB 를 조회하지 않도록 캠페인의 구독 목록을 로컬 캐시에 보관한다
+// 다른 인스턴스에서 변경한 구독은 캐시가 만료된 후에 반영된다
+func (c *CouponService) webhookSubscriptionsOf(couponId string) ([]domain.WebhookSubscription, error) {
+	if subscriptions, ok := c.webhookSubscriptions.Get(couponId); ok {
+		return subscriptions, nil
+	}
+	subscriptions, err := c.webhookRepository.FindSubscriptionsByCouponId(couponId)
+	if err != nil {
+		return nil, err
+	}
+	c.webhookSubscriptions.Set(couponId, subscriptions)
+	return subscriptions, nil
+}
+
+// CreateWebhookSubscription secret 이 비어있으면 임의의 서명 키를 만들어 반환한다
+// eventTypes 가 비어있으면 캠페인의 모든 이벤트를 전달한다
+func (c *CouponService) CreateWebhookSubscription(
+	ctx context.Context,
+	couponId string,
+	url string,
+	secret string,
+	eventTypes []domain.EventType,
+) (*domain.WebhookSubscription, error) {
+	if c.webhookRepository == nil {
+		return nil, WebhooksDisabledError
+	}
+	subscription, err := domain.NewWebhookSubscription(couponId, url, secret, eventTypes, time.Now())
+	if err != nil {
+		return nil, InvalidRequestError.Wrap(err)
+	}
+	if _, err = c.couponRepository.FindOne(couponId); err != nil {
+		if errors.Is(err, repository.ErrCouponNotFound) {
+			return nil, CouponNotFoundError.Wrap(err)
+		}
+		return nil, CouponLookupError.Wrap(err)
+	}
+	if err = c.webhookRepository.SaveSubscription(subscription); err != nil {
+		return nil, WebhookUpdateError.Wrap(err)
+	}
+	c.webhookSubscriptions.Delete(couponId)
+	return subscription, nil
+}
+
+func (c *CouponService) ListWebhookSubscriptions(ctx context.Context, couponId string) ([]domain.WebhookSubscription, error) {
+	if c.webhookRepository == nil {
+		return nil, WebhooksDisabledError
+	}
+	subscriptions, err := c.webhookRepository.FindSubscriptionsByCouponId(couponId)
+	if err != nil {
+		return nil, WebhookLookupError.Wrap(err)
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription 아직 전달하지 못한 이벤트는 dead letter 로 옮긴다
+func (c *CouponService) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if c.webhookRepository == nil {
+		return WebhooksDisabledError
+	}
+	subscription, err := c.webhookRepository.FindSubscription(id)
+	if err == nil {
+		err = c.webhookRepository.DeleteSubscription(id)
+	}
+	if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return WebhookSubscriptionNotFoundError.Wrap(err)
+	}
+	if err != nil {
+		return WebhookUpdateError.Wrap(err)
+	}
+	c.webhookSubscriptions.Delete(subscription.CouponID)
+	return nil
+}
+
+// ListWebhookDeliveries 최근에 만든 전달부터 전달 시도 기록과 함께 반환한다
+func (c *CouponService) ListWebhookDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	if c.webhookRepository == nil {
+		return nil, WebhooksDisabledError
+	}
+	deliveries, err := c.webhookRepository.FindDeliveries(repository.WebhookDeliveryFilter{
+		SubscriptionID: filter.SubscriptionID,
+		CouponID:       filter.CouponID,
+		Status:         filter.Status,
+		Limit:          filter.Limit,
+	})
+	if err != nil {
+		return nil, WebhookLookupError.Wrap(err)
+	}
+	return deliveries, nil
+}
+
+// RetryWebhookDelivery dead letter 로 옮겨진 전달을 다시 전달한다
+func (c *CouponService) RetryWebhookDelivery(ctx context.Context, id string) error {
+	if c.webhookRepository == nil {
+		return WebhooksDisabledError
+	}
+	err := c.webhookRepository.RequeueDelivery(id, time.Now())
+	if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+		return WebhookDeliveryNotFoundError.Wrap(err)
+	}
+	if errors.Is(err, repository.ErrWebhookDeliveryNotRetryable) {
+		return WebhookDeliveryNotRetryableError.Wrap(err)
+	}
+	if err != nil {
+		return WebhookUpdateError.Wrap(err)
+	}
+	return nil
+}
+
+// RunWebhookDispatcher 설정한 간격마다 DispatchWebhooks 를 실행한다
+// 웹훅을 사용하지 않으면 실행하지 않으며, ctx 가 종료될 때까지 블로킹된다
+func (c *CouponService) RunWebhookDispatcher(ctx context.Context) {
+	if c.webhookRepository == nil || c.webhookConfig.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.webhookConfig.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := c.DispatchWebhooks(ctx, time.Now())
+			if err != nil {
+				log.Println(err.Error())
+				continue
+			}
+			if result.Retrying > 0 || result.Dead > 0 {
+				log.Printf("webhook dispatch delivered=%d retrying=%d dead=%d", result.Delivered, result.Retrying, result.Dead)
+			}
+		}
+	}
+}
+
+// DispatchWebhooks 전달 시각이 지난 웹훅을 보낸다. 여러 인스턴스에서 함께 실행해도 같은 전달을 동시에 보내지 않는다
+// 실패한 전달은 backoff 후 다시 보내며, 최대 재시도 횟수를 넘기면 dead letter 로 옮긴다
+func (c *CouponService) DispatchWebhooks(ctx context.Context, now time.Time) (WebhookDispatchResult, error) {
+	var result WebhookDispatchResult
+	if c.webhookRepository == nil {
+		return result, nil
+	}
+	for ctx.Err() == nil {
+		deliveries, err := c.webhookRepository.ClaimDueDeliveries(now, c.webhookConfig.BatchSize, c.webhookConfig.claimLease())
+		if err != nil {
+			return result, WebhookDispatchError.Wrap(err)
+		}
+		if len(deliveries) == 0 {
+			return result, nil
+		}
+		if err = c.dispatchBatch(ctx, deliveries, now, &result); err != nil {
+			return result, WebhookDispatchError.Wrap(err)
+		}
+		if len(deliveries) < c.webhookConfig.BatchSize {
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+func (c *CouponService) dispatchBatch(
+	ctx context.Context,
+	deliveries []domain.WebhookDelivery,
+	now time.Time,
+	result *WebhookDispatchResult,
+) error {
+	ids := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.SubscriptionID)
+	}
+	subscriptions, err := c.webhookRepository.FindSubscriptionsByIds(ids)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(c.webhookConfig.Concurrency, 1))
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			subscription, ok := subscriptions[delivery.SubscriptionID]
+			if ok {
+				c.deliverWebhook(ctx, &subscription, delivery, now)
+			} else {
+				// 구독이 삭제되었으면 보내지 않고 dead letter 로 옮긴다
+				delivery.Record(domain.WebhookAttempt{Error: "subscription deleted", AttemptedAt: now}, 0, c.webhookConfig.backoff)
+			}
+			if err := c.webhookRepository.RecordAttempt(delivery); err != nil {
+				log.Printf("failed to record webhook delivery id=%s: %v", delivery.ID, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch delivery.Status {
+			case domain.WebhookDeliverySucceeded:
+				result.Delivered++
+			case domain.WebhookDeliveryDead:
+				result.Dead++
+			default:
+				result.Retrying++
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (c *CouponService) deliverWebhook(
+	ctx context.Context,
+	subscription *domain.WebhookSubscription,
+	delivery *domain.WebhookDelivery,
+	now time.Time,
+) {
+	start := time.Now()
+	statusCode, err := c.webhookSender.Send(ctx, webhook.Request{
+		ID:        delivery.ID,
+		URL:       subscription.URL,
+		Secret:    subscription.Secret,
+		EventType: string(delivery.EventType),
+		Body:      delivery.Payload,
+		Timestamp: start,
+	})
+	attempt := domain.WebhookAttempt{
+		StatusCode:  statusCode,
+		Duration:    time.Since(start),
+		AttemptedAt: now,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Record(attempt, c.webhookConfig.MaxAttempts, c.webhookConfig.backoff)
+}
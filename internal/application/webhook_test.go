@@ -0,0 +1,210 @@
+package application
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"coupon-service/internal/infrastructure/event"
+	"coupon-service/internal/infrastructure/repository"
+	"coupon-service/internal/infrastructure/webhook"
+	"coupon-service/internal/test"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookConfigBackoff(t *testing.T) {
+	config := WebhookConfig{BackoffBase: 10 * time.Second, BackoffMax: time.Minute}
+
+	assert.Equal(t, 10*time.Second, config.backoff(1))
+	assert.Equal(t, 20*time.Second, config.backoff(2))
+	assert.Equal(t, 40*time.Second, config.backoff(3))
+	assert.Equal(t, time.Minute, config.backoff(4))
+	assert.Equal(t, time.Minute, config.backoff(100))
+}
+
+func TestWebhooksWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(
+		&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.EventOutboxEntity{},
+		&entity.WebhookSubscriptionEntity{}, &entity.WebhookDeliveryEntity{}, &entity.WebhookAttemptEntity{},
+	)
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	config := WebhookConfig{
+		Interval:    time.Second,
+		BatchSize:   10,
+		Concurrency: 2,
+		MaxAttempts: 2,
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+		Timeout:     time.Second,
+	}
+	couponService := NewCouponService(redisContainer.Client, couponRepo, issuedCouponRepo,
+		WithEventOutbox(repository.NewEventOutboxRepository(mysqlContainer.DB)),
+		WithWebhooks(repository.NewWebhookRepository(mysqlContainer.DB), config))
+	now := time.Now()
+
+	var mu sync.Mutex
+	var received []domain.Event
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if !webhook.Verify("partner-secret-0001", r.Header.Get(webhook.TimestampHeader), body, r.Header.Get(webhook.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event domain.Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer partner.Close()
+	var failures atomic.Int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		failures.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	coupon, err := couponService.CreateCoupon(ctx, "웹훅 쿠폰", 1, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	subscription, err := couponService.CreateWebhookSubscription(ctx, coupon.ID, partner.URL, "partner-secret-0001",
+		[]domain.EventType{domain.CampaignSoldOut, domain.CouponRedeemed})
+	require.NoError(t, err)
+	brokenSubscription, err := couponService.CreateWebhookSubscription(ctx, coupon.ID, broken.URL, "",
+		[]domain.EventType{domain.CampaignSoldOut})
+	require.NoError(t, err)
+
+	issued, err := couponService.Issue(ctx, coupon.ID, uuid.New().String())
+	require.NoError(t, err)
+	_, err = couponService.Issue(ctx, coupon.ID, uuid.New().String())
+	require.ErrorIs(t, err, AllCouponIssuedError)
+	_, err = couponService.Redeem(ctx, coupon.ID, issued.Code, issued.UserID)
+	require.NoError(t, err)
+	_, err = couponService.RelayEvents(ctx)
+	require.NoError(t, err)
+
+	t.Run("구독한 이벤트만 서명하여 전달해야 한다", func(t *testing.T) {
+		result, err := couponService.DispatchWebhooks(ctx, time.Now())
+
+		require.NoError(t, err)
+		assert.Equal(t, WebhookDispatchResult{Delivered: 2, Retrying: 1}, result)
+		mu.Lock()
+		defer mu.Unlock()
+		types := make([]domain.EventType, len(received))
+		for i, event := range received {
+			types[i] = event.Type
+		}
+		assert.ElementsMatch(t, []domain.EventType{domain.CampaignSoldOut, domain.CouponRedeemed}, types)
+	})
+
+	t.Run("재시도 시각 전에는 다시 보내지 않아야 한다", func(t *testing.T) {
+		result, err := couponService.DispatchWebhooks(ctx, time.Now())
+
+		require.NoError(t, err)
+		assert.Equal(t, WebhookDispatchResult{}, result)
+		assert.Equal(t, int32(1), failures.Load())
+	})
+
+	t.Run("최대 횟수만큼 실패하면 dead letter 로 옮겨야 한다", func(t *testing.T) {
+		result, err := couponService.DispatchWebhooks(ctx, time.Now().Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, WebhookDispatchResult{Dead: 1}, result)
+
+		deliveries, err := couponService.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{
+			SubscriptionID: brokenSubscription.ID,
+			Status:         domain.WebhookDeliveryDead,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Len(t, deliveries[0].History, 2)
+		assert.Equal(t, http.StatusInternalServerError, deliveries[0].History[1].StatusCode)
+	})
+
+	t.Run("dead letter 는 다시 전달할 수 있어야 한다", func(t *testing.T) {
+		deliveries, err := couponService.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{Status: domain.WebhookDeliveryDead})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+
+		require.NoError(t, couponService.RetryWebhookDelivery(ctx, deliveries[0].ID))
+		assert.ErrorIs(t, couponService.RetryWebhookDelivery(ctx, deliveries[0].ID), WebhookDeliveryNotRetryableError)
+
+		result, err := couponService.DispatchWebhooks(ctx, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, WebhookDispatchResult{Retrying: 1}, result)
+		assert.Equal(t, int32(3), failures.Load())
+	})
+
+	t.Run("구독을 삭제하면 남은 전달은 dead letter 로 옮겨야 한다", func(t *testing.T) {
+		require.NoError(t, couponService.DeleteWebhookSubscription(ctx, brokenSubscription.ID))
+
+		deliveries, err := couponService.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{CouponID: coupon.ID})
+		require.NoError(t, err)
+		statuses := make(map[string]domain.WebhookDeliveryStatus)
+		for _, delivery := range deliveries {
+			statuses[delivery.SubscriptionID] = delivery.Status
+		}
+		assert.Equal(t, domain.WebhookDeliveryDead, statuses[brokenSubscription.ID])
+		assert.Equal(t, domain.WebhookDeliverySucceeded, statuses[subscription.ID])
+		assert.ErrorIs(t, couponService.DeleteWebhookSubscription(ctx, brokenSubscription.ID), WebhookSubscriptionNotFoundError)
+	})
+}
+
+func TestWebhookRelayWithContainer(t *testing.T) {
+	mysqlContainer, ctx := test.SetupMySQLForTest(t)
+	mysqlContainer.MigrateEntities(
+		&entity.CouponEntity{}, &entity.IssuedCouponEntity{}, &entity.EventOutboxEntity{},
+		&entity.WebhookSubscriptionEntity{}, &entity.WebhookDeliveryEntity{}, &entity.WebhookAttemptEntity{},
+	)
+	couponRepo := repository.NewCouponRepository(mysqlContainer.DB)
+	issuedCouponRepo := repository.NewIssuedCouponRepository(mysqlContainer.DB)
+	bus := &failingPublisher{MemoryPublisher: event.NewMemoryPublisher(), fail: true}
+	couponService := NewCouponService(nil, couponRepo, issuedCouponRepo,
+		WithIssuanceStrategy(MySQLIssuance),
+		WithEventPublisher(bus),
+		WithEventOutbox(repository.NewEventOutboxRepository(mysqlContainer.DB)),
+		WithWebhooks(repository.NewWebhookRepository(mysqlContainer.DB), DefaultWebhookConfig))
+	now := time.Now()
+
+	coupon, err := couponService.CreateCoupon(ctx, "웹훅 쿠폰", 1, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = couponService.CreateWebhookSubscription(ctx, coupon.ID, "https://partner.example.com/hook", "",
+		[]domain.EventType{domain.CampaignSoldOut})
+	require.NoError(t, err)
+	_, err = couponService.Issue(ctx, coupon.ID, uuid.New().String())
+	require.NoError(t, err)
+
+	t.Run("대기열에서 발행하기 전에는 웹훅을 전달 대기열에 넣지 않아야 한다", func(t *testing.T) {
+		deliveries, err := couponService.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{CouponID: coupon.ID})
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+
+	t.Run("메시지 버스 발행에 실패해 다시 발행해도 전달은 대기열의 이벤트마다 한 번만 넣어야 한다", func(t *testing.T) {
+		_, err := couponService.RelayEvents(ctx)
+		require.ErrorIs(t, err, EventRelayError)
+
+		bus.fail = false
+		_, err = couponService.RelayEvents(ctx)
+		require.NoError(t, err)
+
+		deliveries, err := couponService.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{CouponID: coupon.ID})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		soldOut := bus.EventsOf(domain.CampaignSoldOut)
+		require.Len(t, soldOut, 1)
+		assert.Equal(t, soldOut[0].ID, deliveries[0].EventID)
+	})
+}
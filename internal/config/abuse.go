@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+// AbuseMaxUsers 한 캠페인에서 같은 IP, 기기(Coupon-Device-Id)로 요청할 수 있는 사용자 수. 0 이면 검사하지 않는다
+//   - ABUSE_MAX_USERS_PER_IP
+//   - ABUSE_MAX_USERS_PER_DEVICE
+func AbuseMaxUsers() (perIP int, perDevice int) {
+	return envInt("ABUSE_MAX_USERS_PER_IP", 0), envInt("ABUSE_MAX_USERS_PER_DEVICE", 0)
+}
+
+// AbuseNewAccountRush 가입한 지 accountAge 가 지나지 않은 계정이 발급 시작 후 window 이내에 요청하면 추가 인증을 요구한다
+// 0 이면 검사하지 않는다
+//   - ABUSE_NEW_ACCOUNT_AGE (예: 24h)
+//   - ABUSE_RUSH_WINDOW (예: 200ms)
+func AbuseNewAccountRush() (accountAge time.Duration, window time.Duration) {
+	return envDuration("ABUSE_NEW_ACCOUNT_AGE", 0), envDuration("ABUSE_RUSH_WINDOW", 0)
+}
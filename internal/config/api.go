@@ -0,0 +1,7 @@
+package config
+
+// ConnectErrorCodes CONNECT_ERROR_CODES 가 true 이면 실패한 요청을 응답 메시지의 에러 필드 대신
+// connect 에러 코드로 반환한다. 요청 헤더로 요청마다 선택할 수도 있다
+func ConnectErrorCodes() bool {
+	return envBool("CONNECT_ERROR_CODES", false)
+}
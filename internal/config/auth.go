@@ -0,0 +1,36 @@
+package config
+
+import (
+	"coupon-service/internal/infrastructure/auth"
+	"os"
+)
+
+// AuthVerifier 토큰 검증 설정. 둘 다 없으면 인증을 사용하지 않으며 nil 을 반환한다
+//   - AUTH_JWT_SECRET: HMAC(HS256, HS384, HS512) 서명용 공유 비밀 키
+//   - AUTH_JWKS_FILE: RSA, EC 공개 키가 담긴 JWKS 파일 경로. AUTH_JWT_SECRET 보다 우선한다
+//   - AUTH_ISSUER, AUTH_AUDIENCE: 지정하면 iss, aud 클레임을 확인한다
+func AuthVerifier() (*auth.Verifier, error) {
+	var opts []auth.VerifierOption
+	if issuer := os.Getenv("AUTH_ISSUER"); issuer != "" {
+		opts = append(opts, auth.WithIssuer(issuer))
+	}
+	if audience := os.Getenv("AUTH_AUDIENCE"); audience != "" {
+		opts = append(opts, auth.WithAudience(audience))
+	}
+
+	if path := os.Getenv("AUTH_JWKS_FILE"); path != "" {
+		return auth.NewJWKSVerifier(path, opts...)
+	}
+	if secret := os.Getenv("AUTH_JWT_SECRET"); secret != "" {
+		return auth.NewHMACVerifier([]byte(secret), opts...), nil
+	}
+	return nil, nil
+}
+
+// AuthAdminScope AUTH_ADMIN_SCOPE 로 관리자 RPC 호출에 필요한 scope 를 지정한다 (기본값: coupon:admin)
+func AuthAdminScope() string {
+	if value := os.Getenv("AUTH_ADMIN_SCOPE"); value != "" {
+		return value
+	}
+	return "coupon:admin"
+}
@@ -1,10 +1,64 @@
 package config
 
-import "github.com/redis/go-redis/v9"
-
-var CacheClient = redis.NewClient(&redis.Options{
-	Addr:     "localhost:6379",
-	Password: "", // No password set
-	DB:       0,  // Use default DB
-	Protocol: 2,  // Connection protocol
-})
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisModeSingle   = "single"
+	RedisModeCluster  = "cluster"
+	RedisModeSentinel = "sentinel"
+)
+
+var CacheClient = NewCacheClient()
+
+// NewCacheClient 환경 변수에 따라 단일 노드, 클러스터, 센티널 클라이언트를 생성한다
+//   - REDIS_MODE: single(기본값), cluster, sentinel
+//   - REDIS_ADDRS: 쉼표로 구분된 주소 목록 (센티널 모드는 센티널 주소), 없으면 REDIS_ADDR 사용
+//   - REDIS_MASTER_NAME: 센티널 모드의 마스터 이름
+//   - REDIS_PASSWORD: 비밀번호
+func NewCacheClient() redis.UniversalClient {
+	opts := &redis.UniversalOptions{
+		Addrs:      redisAddrs(),
+		MasterName: os.Getenv("REDIS_MASTER_NAME"),
+		Password:   os.Getenv("REDIS_PASSWORD"),
+		DB:         0, // Use default DB
+		Protocol:   2, // Connection protocol
+	}
+
+	switch mode := os.Getenv("REDIS_MODE"); mode {
+	case "", RedisModeSingle:
+		return redis.NewClient(opts.Simple())
+	case RedisModeCluster:
+		return redis.NewClusterClient(opts.Cluster())
+	case RedisModeSentinel:
+		if opts.MasterName == "" {
+			panic("REDIS_MASTER_NAME is required in sentinel mode")
+		}
+		return redis.NewFailoverClient(opts.Failover())
+	default:
+		panic(fmt.Sprintf("unknown REDIS_MODE: %s", mode))
+	}
+}
+
+func redisAddrs() []string {
+	value := os.Getenv("REDIS_ADDRS")
+	if value == "" {
+		value = os.Getenv("REDIS_ADDR")
+	}
+	if value == "" {
+		return []string{"localhost:6379"}
+	}
+
+	var addrs []string
+	for _, addr := range strings.Split(value, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
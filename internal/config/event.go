@@ -0,0 +1,46 @@
+package config
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/event"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EventPublisher EVENT_PUBLISHER 로 도메인 이벤트를 발행할 메시지 버스를 지정한다: redis, kafka
+// 지정하지 않으면 이벤트를 발행하지 않으며 nil 을 반환한다
+//   - EVENT_REDIS_STREAM: Redis Stream 키 (기본값: coupon:events)
+//   - EVENT_REDIS_STREAM_MAXLEN: 스트림에 보관하는 이벤트 수
+//   - KAFKA_BROKERS: 쉼표로 구분한 Kafka 브로커 주소
+//   - KAFKA_EVENT_TOPIC: 이벤트를 발행할 토픽 (기본값: coupon-events)
+func EventPublisher() (domain.EventPublisher, error) {
+	switch publisher := os.Getenv("EVENT_PUBLISHER"); publisher {
+	case "":
+		return nil, nil
+	case "redis":
+		return event.NewRedisStreamPublisher(
+			CacheClient,
+			envString("EVENT_REDIS_STREAM", "coupon:events"),
+			int64(envInt("EVENT_REDIS_STREAM_MAXLEN", event.DefaultStreamMaxLen)),
+		), nil
+	case "kafka":
+		brokers := kafkaBrokers()
+		if len(brokers) == 0 {
+			return nil, fmt.Errorf("KAFKA_BROKERS is required for the kafka event publisher")
+		}
+		return event.NewKafkaPublisher(brokers, envString("KAFKA_EVENT_TOPIC", "coupon-events")), nil
+	default:
+		return nil, fmt.Errorf("unknown EVENT_PUBLISHER(%s)", publisher)
+	}
+}
+
+func kafkaBrokers() []string {
+	var brokers []string
+	for _, broker := range strings.Split(os.Getenv("KAFKA_BROKERS"), ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
+}
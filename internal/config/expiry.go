@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// ExpirySweep 사용 기간이 지난 쿠폰의 만료 처리 작업 설정
+//   - EXPIRY_SWEEP_INTERVAL: 실행 간격 (예: 1m). 0 이면 실행하지 않는다
+//   - EXPIRY_SWEEP_BATCH_SIZE: 한 트랜잭션에서 처리하는 쿠폰 수
+//   - EXPIRY_NOTICE_BEFORE: 사용 기간이 끝나기 얼마 전에 만료 예정 이벤트를 보낼지 (예: 24h)
+func ExpirySweep() (interval time.Duration, batchSize int, noticeBefore time.Duration) {
+	return envDuration("EXPIRY_SWEEP_INTERVAL", time.Minute),
+		envInt("EXPIRY_SWEEP_BATCH_SIZE", 500),
+		envDuration("EXPIRY_NOTICE_BEFORE", 24*time.Hour)
+}
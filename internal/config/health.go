@@ -0,0 +1,8 @@
+package config
+
+import "time"
+
+// ReadinessCheckTimeout READINESS_CHECK_TIMEOUT 준비 상태 확인(/readyz)에서 MySQL, Redis 등 의존성 하나를 확인하는 최대 시간 (예: 2s)
+func ReadinessCheckTimeout() time.Duration {
+	return envDuration("READINESS_CHECK_TIMEOUT", 2*time.Second)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"coupon-service/internal/infrastructure/cache"
+	"os"
+)
+
+// CacheCircuitBreaker Redis circuit breaker 설정
+//   - CACHE_BREAKER_FAILURE_THRESHOLD: 회로를 여는 연속 실패 횟수
+//   - CACHE_BREAKER_OPEN_TIMEOUT: 회로가 열린 후 복구를 시도하기까지의 시간 (예: 10s)
+func CacheCircuitBreaker() cache.CircuitBreakerConfig {
+	return cache.CircuitBreakerConfig{
+		FailureThreshold: envInt("CACHE_BREAKER_FAILURE_THRESHOLD", cache.DefaultCircuitBreakerConfig.FailureThreshold),
+		OpenTimeout:      envDuration("CACHE_BREAKER_OPEN_TIMEOUT", cache.DefaultCircuitBreakerConfig.OpenTimeout),
+	}
+}
+
+// IssuanceDBFallback ISSUANCE_DB_FALLBACK 이 true 이면 Redis 장애 중 DB 만으로 발급한다
+func IssuanceDBFallback() bool {
+	return envBool("ISSUANCE_DB_FALLBACK", false)
+}
+
+// IssuanceStrategy ISSUANCE_STRATEGY 로 재고 차감 방식을 지정한다: redis(기본값), mysql, hybrid
+func IssuanceStrategy() string {
+	if value := os.Getenv("ISSUANCE_STRATEGY"); value != "" {
+		return value
+	}
+	return "redis"
+}
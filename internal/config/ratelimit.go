@@ -0,0 +1,18 @@
+package config
+
+import (
+	"coupon-service/internal/infrastructure/ratelimit"
+	"os"
+)
+
+// RateLimitRules RATE_LIMITS 로 RPC 별 요청 한도를 지정한다. 없으면 요청 한도를 적용하지 않는다
+// 예: RATE_LIMITS="IssueCoupon:user=5/1s,IssueCoupon:ip=20/1s,IssueCoupon:campaign=2000/1s,*:rpc=5000/1s"
+func RateLimitRules() ([]ratelimit.Rule, error) {
+	return ratelimit.ParseRules(os.Getenv("RATE_LIMITS"))
+}
+
+// TrustForwardedFor TRUST_FORWARDED_FOR 가 true 이면 요청 한도와 어뷰징 검사에서 X-Forwarded-For 의 첫 번째 주소를 클라이언트 IP 로 사용한다
+// 로드 밸런서가 헤더를 덮어쓰는 환경에서만 사용해야 한다
+func TrustForwardedFor() bool {
+	return envBool("TRUST_FORWARDED_FOR", false)
+}
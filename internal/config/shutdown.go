@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// Shutdown 종료 신호(SIGINT, SIGTERM)를 받은 뒤의 종료 설정
+//   - SHUTDOWN_TIMEOUT: 처리 중인 요청을 기다리는 최대 시간. 처리 중인 발급과 백그라운드 작업도 각각 이 시간만큼 기다린다 (예: 30s)
+//   - SHUTDOWN_DRAIN_DELAY: /readyz 를 실패로 바꾼 뒤 새 연결을 받지 않기 전까지 기다리는 시간 (예: 5s)
+func Shutdown() (timeout time.Duration, drainDelay time.Duration) {
+	return envDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		envDuration("SHUTDOWN_DRAIN_DELAY", 0)
+}
@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// CampaignWatch 캠페인 상태 스트림(WatchCampaign) 설정
+//   - WATCH_MAX_UPDATES_PER_SECOND: 한 스트림에 보내는 초당 최대 갱신 수
+//   - WATCH_HEARTBEAT_INTERVAL: 변경이 없어도 서버 시각을 보내는 간격 (예: 10s)
+func CampaignWatch() (maxUpdatesPerSecond int, heartbeat time.Duration) {
+	return envInt("WATCH_MAX_UPDATES_PER_SECOND", 5),
+		envDuration("WATCH_HEARTBEAT_INTERVAL", 10*time.Second)
+}
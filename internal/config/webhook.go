@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// Webhooks WEBHOOKS_ENABLED 로 캠페인 이벤트의 웹훅 전달을 사용할지 지정한다
+//   - WEBHOOK_DISPATCH_INTERVAL: 전달할 웹훅을 확인하는 간격 (예: 5s)
+//   - WEBHOOK_BATCH_SIZE: 한 번에 가져오는 전달 수
+//   - WEBHOOK_CONCURRENCY: 동시에 보내는 요청 수
+//   - WEBHOOK_TIMEOUT: 수신 서버의 응답을 기다리는 시간
+func Webhooks() (enabled bool, interval time.Duration, batchSize int, concurrency int, timeout time.Duration) {
+	return envBool("WEBHOOKS_ENABLED", false),
+		envDuration("WEBHOOK_DISPATCH_INTERVAL", 5*time.Second),
+		envInt("WEBHOOK_BATCH_SIZE", 100),
+		envInt("WEBHOOK_CONCURRENCY", 8),
+		envDuration("WEBHOOK_TIMEOUT", 5*time.Second)
+}
+
+// WebhookRetry 실패한 웹훅의 재시도 설정
+//   - WEBHOOK_MAX_ATTEMPTS: 이 횟수만큼 실패하면 dead letter 로 옮긴다
+//   - WEBHOOK_BACKOFF_BASE, WEBHOOK_BACKOFF_MAX: 재시도 간격의 시작값과 최댓값. 실패할 때마다 두 배씩 늘어난다
+func WebhookRetry() (maxAttempts int, backoffBase time.Duration, backoffMax time.Duration) {
+	return envInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		envDuration("WEBHOOK_BACKOFF_BASE", 10*time.Second),
+		envDuration("WEBHOOK_BACKOFF_MAX", time.Hour)
+}
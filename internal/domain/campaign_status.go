@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// CampaignStatus 발급 기간과 남은 재고로 판단하는 캠페인의 발급 상태
+type CampaignStatus string
+
+const (
+	// CampaignStatusScheduled 발급 시작 시각 전이다
+	CampaignStatusScheduled CampaignStatus = "scheduled"
+	CampaignStatusOpen      CampaignStatus = "open"
+	// CampaignStatusPaused 발급 기간이지만 운영자가 발급을 일시 중지했다
+	CampaignStatusPaused CampaignStatus = "paused"
+	// CampaignStatusSoldOut 발급 기간이지만 남은 재고가 없다
+	CampaignStatusSoldOut CampaignStatus = "sold_out"
+	// CampaignStatusEnded 발급 종료 시각이 지났다
+	CampaignStatusEnded CampaignStatus = "ended"
+)
+
+// Status now 시점의 발급 상태. 발급 기간 검증과 같이 종료 시각까지는 발급할 수 있다
+func (c *Coupon) Status(now time.Time, remaining int64) CampaignStatus {
+	switch {
+	case c.IssuedAt.After(now):
+		return CampaignStatusScheduled
+	case c.ExpiresAt.Before(now):
+		return CampaignStatusEnded
+	case c.Paused():
+		return CampaignStatusPaused
+	case remaining <= 0:
+		return CampaignStatusSoldOut
+	default:
+		return CampaignStatusOpen
+	}
+}
+
+// Paused 운영자가 발급을 일시 중지했는지 여부
+func (c *Coupon) Paused() bool {
+	return c.PausedAt != nil
+}
+
+// NextStatusChange now 이후 시간이 지나 발급 상태가 바뀌는 시각. 종료된 캠페인은 false 를 반환한다
+func (c *Coupon) NextStatusChange(now time.Time) (time.Time, bool) {
+	switch {
+	case c.IssuedAt.After(now):
+		return c.IssuedAt, true
+	case !c.ExpiresAt.Before(now):
+		return c.ExpiresAt.Add(time.Nanosecond), true
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCouponStatus(t *testing.T) {
+	now := time.Now()
+	coupon := &Coupon{IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		remaining int64
+		status    CampaignStatus
+		next      time.Time
+	}{
+		{"시작 전", now.Add(-time.Second), 10, CampaignStatusScheduled, now},
+		{"발급 중", now, 10, CampaignStatusOpen, now.Add(time.Hour + time.Nanosecond)},
+		{"매진", now.Add(time.Minute), 0, CampaignStatusSoldOut, now.Add(time.Hour + time.Nanosecond)},
+		{"종료", now.Add(2 * time.Hour), 10, CampaignStatusEnded, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, coupon.Status(tt.at, tt.remaining))
+			next, ok := coupon.NextStatusChange(tt.at)
+			assert.Equal(t, !tt.next.IsZero(), ok)
+			assert.Equal(t, tt.next, next)
+		})
+	}
+}
+
+func TestPausedCouponStatus(t *testing.T) {
+	now := time.Now()
+	coupon := &Coupon{IssuedAt: now, ExpiresAt: now.Add(time.Hour), PausedAt: &now}
+
+	assert.Equal(t, CampaignStatusPaused, coupon.Status(now.Add(time.Minute), 0))
+	assert.Equal(t, CampaignStatusScheduled, coupon.Status(now.Add(-time.Second), 10))
+	assert.Equal(t, CampaignStatusEnded, coupon.Status(now.Add(2*time.Hour), 10))
+}
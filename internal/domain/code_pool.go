@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CodeSource 발급하는 쿠폰 코드를 만드는 방식
+type CodeSource string
+
+const (
+	// CodeSourceGenerated 발급할 때마다 임의의 코드를 생성한다
+	CodeSourceGenerated CodeSource = "generated"
+	// CodeSourcePool 제휴사에서 받은 코드(기프트카드 PIN 등)를 미리 등록해두고 하나씩 꺼내 발급한다
+	CodeSourcePool CodeSource = "pool"
+	// CodeSourceShared 캠페인의 공용 코드 하나를 여러 사용자가 사용한다. 사용할 때마다 발급 내역이 하나씩 생긴다
+	CodeSourceShared CodeSource = "shared"
+)
+
+// MaxCodeLength issued_coupons.code, coupon_codes.code 컬럼(varchar(64))의 최대 길이
+const MaxCodeLength = 64
+
+// WithCodePool 등록한 코드로만 발급하는 캠페인으로 만든다. 재고는 등록한 코드 수이다
+func WithCodePool() CouponOption {
+	return func(c *Coupon) {
+		c.CodeSource = CodeSourcePool
+	}
+}
+
+// UsesCodePool 코드 풀에서 코드를 꺼내 발급하는 캠페인인지 여부
+func (c *Coupon) UsesCodePool() bool {
+	return c.CodeSource == CodeSourcePool
+}
+
+// NormalizePoolCodes 등록할 코드의 앞뒤 공백을 제거하고 중복을 제외한다
+// 빈 코드나 너무 긴 코드가 있으면 위치를 담은 ValidationError 를 반환한다
+func NormalizePoolCodes(codes []string) ([]string, error) {
+	v := &validator{}
+	seen := make(map[string]struct{}, len(codes))
+	normalized := make([]string, 0, len(codes))
+	for i, code := range codes {
+		code = strings.TrimSpace(code)
+		field := fmt.Sprintf("codes[%d]", i)
+		v.check(code != "", field, "must not be empty")
+		v.check(
+			utf8.RuneCountInString(code) <= MaxCodeLength,
+			field, fmt.Sprintf("must be at most %d characters", MaxCodeLength),
+		)
+		if _, ok := seen[code]; ok || code == "" {
+			continue
+		}
+		seen[code] = struct{}{}
+		normalized = append(normalized, code)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizePoolCodes(t *testing.T) {
+	t.Run("공백을 제거하고 중복된 코드를 제외해야 한다", func(t *testing.T) {
+		codes, err := NormalizePoolCodes([]string{" PIN-1", "PIN-2", "PIN-1 "})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PIN-1", "PIN-2"}, codes)
+	})
+
+	t.Run("빈 코드나 너무 긴 코드의 위치가 반환되어야 한다", func(t *testing.T) {
+		_, err := NormalizePoolCodes([]string{"PIN-1", " ", strings.Repeat("1", MaxCodeLength+1)})
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t,
+			"codes[1]: must not be empty, codes[2]: must be at most 64 characters",
+			validationErr.Error(),
+		)
+	})
+}
+
+func TestNewCouponWithCodePool(t *testing.T) {
+	now := time.Now()
+
+	coupon, err := NewCoupon("제휴 코드", 0, now, now.Add(time.Hour), WithCodePool())
+	require.NoError(t, err)
+	assert.True(t, coupon.UsesCodePool())
+
+	_, err = NewCoupon("제휴 코드", 10, now, now.Add(time.Hour), WithCodePool())
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "amount: must be 0 for a code pool campaign", validationErr.Error())
+}
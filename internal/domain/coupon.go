@@ -1,35 +1,129 @@
 package domain
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"time"
+	"unicode/utf8"
 )
 
+// MaxCouponNameLength coupons.name 컬럼(varchar(20))의 최대 길이
+const MaxCouponNameLength = 20
+
+// MaxStockShards 재고 하위 카운터의 최대 개수. 캠페인 생성 시 하위 카운터마다 Redis 키를 하나씩 만든다
+const MaxStockShards = 64
+
 type Coupon struct {
-	ID            string         `json:"id"`
-	Name          string         `json:"name"`
-	IssueAmount   int64          `json:"issue_amount"`
-	IssuedAt      time.Time      `json:"issued_at"`
-	ExpiresAt     time.Time      `json:"expires_at"`
+	ID           string           `json:"id"`
+	Name         string           `json:"name"`
+	IssueAmount  int64            `json:"issue_amount"`
+	IssuedAt     time.Time        `json:"issued_at"`
+	ExpiresAt    time.Time        `json:"expires_at"`
+	StockShards  int              `json:"stock_shards"`
+	Eligibility  EligibilityRules `json:"eligibility"`
+	CodeSource   CodeSource       `json:"code_source"`
+	SharedCode   string           `json:"shared_code,omitempty"`
+	PerUserLimit int              `json:"per_user_limit,omitempty"`
+	Validity     UsageValidity    `json:"validity"`
+	// IssuanceStrategy 캠페인을 만들 때 사용한 발급 방식. 비어있으면 발급 방식이 추가되기 전에 만든 캠페인이다
+	IssuanceStrategy string `json:"issuance_strategy,omitempty"`
+	// PausedAt 운영자가 발급을 일시 중지한 시각. nil 이면 중지되지 않았다
+	PausedAt      *time.Time     `json:"paused_at,omitempty"`
 	IssuedCoupons []IssuedCoupon `json:"issued_coupons"`
 	CreatedAt     time.Time      `json:"created_at"`
 	ModifiedAt    time.Time      `json:"modified_at"`
 }
 
+// CouponOption 캠페인 생성 시 선택적으로 지정하는 설정
+type CouponOption func(*Coupon)
+
+// WithStockShards 재고 카운터를 n 개의 하위 카운터로 나누어 관리한다
+func WithStockShards(n int) CouponOption {
+	return func(c *Coupon) {
+		c.StockShards = n
+	}
+}
+
+// NewCoupon 캠페인을 생성한다. 입력값이 올바르지 않으면 위반한 필드를 담은 ValidationError 를 반환한다
 func NewCoupon(
 	name string,
 	issueAmount int64,
 	issuedAt time.Time,
 	expiresAt time.Time,
-) *Coupon {
+	opts ...CouponOption,
+) (*Coupon, error) {
 	now := time.Now()
-	return &Coupon{
+	coupon := &Coupon{
 		ID:          uuid.New().String(),
 		Name:        name,
 		IssueAmount: issueAmount,
 		IssuedAt:    issuedAt,
 		ExpiresAt:   expiresAt,
+		StockShards: 1,
+		CodeSource:  CodeSourceGenerated,
 		CreatedAt:   now,
 		ModifiedAt:  now,
 	}
+	for _, opt := range opts {
+		opt(coupon)
+	}
+	if coupon.StockShards < 1 {
+		coupon.StockShards = 1
+	}
+
+	v := &validator{}
+	v.check(name != "", "name", "must not be empty")
+	v.check(
+		utf8.RuneCountInString(name) <= MaxCouponNameLength,
+		"name", fmt.Sprintf("must be at most %d characters", MaxCouponNameLength),
+	)
+	// 코드 풀 캠페인의 재고는 등록한 코드 수이다
+	if coupon.UsesCodePool() {
+		v.check(issueAmount == 0, "amount", "must be 0 for a code pool campaign")
+	} else {
+		v.check(issueAmount > 0, "amount", "must be greater than 0")
+	}
+	v.check(
+		coupon.StockShards <= MaxStockShards,
+		"stock_shards", fmt.Sprintf("must be at most %d", MaxStockShards),
+	)
+	// 하위 카운터마다 재고가 1개 이상 있어야 한다
+	if !coupon.UsesCodePool() && issueAmount > 0 {
+		v.check(int64(coupon.StockShards) <= issueAmount, "stock_shards", "must not exceed amount")
+	}
+	v.check(!issuedAt.IsZero(), "issued_at", "must be set")
+	v.check(!expiresAt.IsZero(), "expires_at", "must be set")
+	if !issuedAt.IsZero() && !expiresAt.IsZero() {
+		v.check(expiresAt.After(issuedAt), "expires_at", "must be after issued_at")
+	}
+	coupon.validateSharedCode(v)
+	coupon.Validity.validate(v, issuedAt)
+	coupon.Eligibility.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// IsSharded 재고가 여러 하위 카운터로 나뉘어 있는지 여부
+func (c *Coupon) IsSharded() bool {
+	return c.StockShards > 1
+}
+
+// ShardAmounts 발급 수량을 하위 카운터별로 분배한다. 나머지는 앞쪽 카운터부터 1개씩 더 배정된다
+func (c *Coupon) ShardAmounts() []int64 {
+	if !c.IsSharded() {
+		return []int64{c.IssueAmount}
+	}
+
+	amounts := make([]int64, c.StockShards)
+	base := c.IssueAmount / int64(c.StockShards)
+	remainder := c.IssueAmount % int64(c.StockShards)
+	for i := range amounts {
+		amounts[i] = base
+		if int64(i) < remainder {
+			amounts[i]++
+		}
+	}
+	return amounts
 }
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewCouponValidation(t *testing.T) {
+	now := time.Now()
+
+	t.Run("올바른 입력값이면 캠페인이 생성되어야 한다", func(t *testing.T) {
+		coupon, err := NewCoupon("쿠폰", 10, now, now.Add(time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), coupon.IssueAmount)
+		assert.Equal(t, 1, coupon.StockShards)
+	})
+
+	t.Run("위반한 모든 필드가 반환되어야 한다", func(t *testing.T) {
+		_, err := NewCoupon(strings.Repeat("가", MaxCouponNameLength+1), 0, now, now.Add(-time.Hour))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		fields := make([]string, len(validationErr.Violations))
+		for i, violation := range validationErr.Violations {
+			fields[i] = violation.Field
+		}
+		assert.Equal(t, []string{"name", "amount", "expires_at"}, fields)
+	})
+
+	t.Run("발급 기간이 지정되지 않으면 실패해야 한다", func(t *testing.T) {
+		_, err := NewCoupon("쿠폰", 10, time.Time{}, time.Time{})
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, validationErr.Violations, FieldViolation{Field: "issued_at", Description: "must be set"})
+		assert.Contains(t, validationErr.Violations, FieldViolation{Field: "expires_at", Description: "must be set"})
+	})
+}
+
+func TestNewCouponStockShardsValidation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		amount      int64
+		shards      int
+		description string
+	}{
+		{"최대 개수를 넘으면 실패해야 한다", 1000, MaxStockShards + 1, "must be at most 64"},
+		{"발급 수량보다 많으면 실패해야 한다", 3, 4, "must not exceed amount"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCoupon("쿠폰", tt.amount, now, now.Add(time.Hour), WithStockShards(tt.shards))
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, validationErr.Violations, FieldViolation{Field: "stock_shards", Description: tt.description})
+		})
+	}
+
+	t.Run("최대 개수와 발급 수량 이하이면 생성되어야 한다", func(t *testing.T) {
+		coupon, err := NewCoupon("쿠폰", MaxStockShards, now, now.Add(time.Hour), WithStockShards(MaxStockShards))
+
+		require.NoError(t, err)
+		assert.Len(t, coupon.ShardAmounts(), MaxStockShards)
+	})
+}
+
+func TestNewIssuedCouponValidation(t *testing.T) {
+	_, err := NewIssuedCoupon("coupon-id", "", time.Now())
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "user_id: must not be empty", validationErr.Error())
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserListMode 캠페인별 사용자 목록의 용도
+type UserListMode string
+
+const (
+	UserListNone UserListMode = ""
+	// UserListAllow 목록에 있는 사용자만 발급받을 수 있다
+	UserListAllow UserListMode = "allow"
+	// UserListDeny 목록에 있는 사용자는 발급받을 수 없다
+	UserListDeny UserListMode = "deny"
+)
+
+// DefaultNewUserMaxAge NewUsersOnly 캠페인에서 NewUserMaxAge 를 지정하지 않았을 때 신규 사용자로 보는 가입 후 기간
+const DefaultNewUserMaxAge = 30 * 24 * time.Hour
+
+// EligibilityRules 캠페인별 발급 대상 조건. 지정하지 않은 조건은 확인하지 않는다
+//   - UserListMode: 사용자 목록(허용 또는 차단)의 용도. 목록 자체는 크기가 클 수 있어 캠페인 데이터와 별도로 저장한다
+//   - Segments: 사용자가 이 중 하나 이상의 세그먼트에 속해야 한다
+//   - NewUsersOnly: 가입한 지 NewUserMaxAge 가 지나지 않은 사용자만 발급받을 수 있다
+//   - Regions: 사용자의 지역이 이 중 하나여야 한다
+type EligibilityRules struct {
+	UserListMode  UserListMode  `json:"user_list_mode,omitempty"`
+	Segments      []string      `json:"segments,omitempty"`
+	NewUsersOnly  bool          `json:"new_users_only,omitempty"`
+	NewUserMaxAge time.Duration `json:"new_user_max_age,omitempty"`
+	Regions       []string      `json:"regions,omitempty"`
+}
+
+// Eligibility 발급 대상 조건을 확인하는 데 필요한 사용자 정보
+//   - InUserList: 캠페인의 사용자 목록에 포함되어 있는지 여부
+type Eligibility struct {
+	Segments         []string
+	Region           string
+	AccountCreatedAt time.Time
+	InUserList       bool
+	RequestedAt      time.Time
+}
+
+// IneligibleError 발급 대상이 아닌 사유 목록
+type IneligibleError struct {
+	Reasons []string
+}
+
+func (e *IneligibleError) Error() string {
+	return strings.Join(e.Reasons, ", ")
+}
+
+// WithEligibility 캠페인의 발급 대상 조건을 지정한다
+func WithEligibility(rules EligibilityRules) CouponOption {
+	return func(c *Coupon) {
+		c.Eligibility = rules
+	}
+}
+
+func (r EligibilityRules) validate(v *validator) {
+	switch r.UserListMode {
+	case UserListNone, UserListAllow, UserListDeny:
+	default:
+		v.check(false, "eligibility.user_list_mode", "must be one of allow, deny")
+	}
+	v.check(r.NewUserMaxAge >= 0, "eligibility.new_user_max_age", "must not be negative")
+}
+
+// HasUserList 사용자 목록 확인이 필요한지 여부
+func (r EligibilityRules) HasUserList() bool {
+	return r.UserListMode != UserListNone
+}
+
+// Check 발급 대상이 아니면 모든 사유를 담은 IneligibleError 를 반환한다
+func (r EligibilityRules) Check(e Eligibility) error {
+	var reasons []string
+	switch r.UserListMode {
+	case UserListAllow:
+		if !e.InUserList {
+			reasons = append(reasons, "user is not in the allowlist")
+		}
+	case UserListDeny:
+		if e.InUserList {
+			reasons = append(reasons, "user is in the denylist")
+		}
+	}
+	if len(r.Segments) > 0 && !containsAny(r.Segments, e.Segments) {
+		reasons = append(reasons, fmt.Sprintf("user is not in segments %v", r.Segments))
+	}
+	if r.NewUsersOnly && !r.isNewUser(e) {
+		reasons = append(reasons, "campaign is for new users only")
+	}
+	if len(r.Regions) > 0 && !containsAny(r.Regions, []string{e.Region}) {
+		reasons = append(reasons, fmt.Sprintf("region %q is not in %v", e.Region, r.Regions))
+	}
+
+	if len(reasons) > 0 {
+		return &IneligibleError{Reasons: reasons}
+	}
+	return nil
+}
+
+// isNewUser 가입 일시를 알 수 없으면 신규 사용자로 보지 않는다
+func (r EligibilityRules) isNewUser(e Eligibility) bool {
+	if e.AccountCreatedAt.IsZero() {
+		return false
+	}
+	maxAge := r.NewUserMaxAge
+	if maxAge == 0 {
+		maxAge = DefaultNewUserMaxAge
+	}
+	return e.RequestedAt.Sub(e.AccountCreatedAt) <= maxAge
+}
+
+func containsAny(candidates []string, values []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if strings.EqualFold(candidate, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEligibilityRulesCheck(t *testing.T) {
+	now := time.Now()
+
+	t.Run("조건이 없으면 모든 사용자가 대상이어야 한다", func(t *testing.T) {
+		assert.NoError(t, EligibilityRules{}.Check(Eligibility{RequestedAt: now}))
+	})
+
+	t.Run("허용 목록에 없거나 차단 목록에 있는 사용자는 대상이 아니어야 한다", func(t *testing.T) {
+		assert.Error(t, EligibilityRules{UserListMode: UserListAllow}.Check(Eligibility{}))
+		assert.NoError(t, EligibilityRules{UserListMode: UserListAllow}.Check(Eligibility{InUserList: true}))
+		assert.Error(t, EligibilityRules{UserListMode: UserListDeny}.Check(Eligibility{InUserList: true}))
+		assert.NoError(t, EligibilityRules{UserListMode: UserListDeny}.Check(Eligibility{}))
+	})
+
+	t.Run("세그먼트와 지역은 대소문자를 구분하지 않아야 한다", func(t *testing.T) {
+		rules := EligibilityRules{Segments: []string{"vip", "premium"}, Regions: []string{"KR"}}
+
+		assert.NoError(t, rules.Check(Eligibility{Segments: []string{"basic", "VIP"}, Region: "kr"}))
+	})
+
+	t.Run("가입 일시를 알 수 없거나 오래된 계정은 신규 사용자가 아니어야 한다", func(t *testing.T) {
+		rules := EligibilityRules{NewUsersOnly: true, NewUserMaxAge: 24 * time.Hour}
+
+		assert.NoError(t, rules.Check(Eligibility{AccountCreatedAt: now.Add(-time.Hour), RequestedAt: now}))
+		assert.Error(t, rules.Check(Eligibility{AccountCreatedAt: now.Add(-48 * time.Hour), RequestedAt: now}))
+		assert.Error(t, rules.Check(Eligibility{RequestedAt: now}))
+		assert.NoError(t, EligibilityRules{NewUsersOnly: true}.Check(
+			Eligibility{AccountCreatedAt: now.Add(-7 * 24 * time.Hour), RequestedAt: now},
+		))
+	})
+
+	t.Run("대상이 아닌 모든 사유가 반환되어야 한다", func(t *testing.T) {
+		rules := EligibilityRules{UserListMode: UserListAllow, Segments: []string{"vip"}, Regions: []string{"KR"}}
+
+		err := rules.Check(Eligibility{Region: "US", RequestedAt: now})
+
+		var ineligibleErr *IneligibleError
+		require.ErrorAs(t, err, &ineligibleErr)
+		assert.Len(t, ineligibleErr.Reasons, 3)
+	})
+}
+
+func TestNewCouponEligibilityValidation(t *testing.T) {
+	now := time.Now()
+
+	_, err := NewCoupon("쿠폰", 10, now, now.Add(time.Hour), WithEligibility(EligibilityRules{
+		UserListMode:  "unknown",
+		NewUserMaxAge: -time.Hour,
+	}))
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t,
+		"eligibility.user_list_mode: must be one of allow, deny, eligibility.new_user_max_age: must not be negative",
+		validationErr.Error(),
+	)
+}
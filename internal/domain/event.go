@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType 서비스 밖으로 전달하는 도메인 이벤트 종류
+type EventType string
+
+const (
+	CampaignCreated EventType = "campaign.created"
+	// CampaignOpened 캠페인의 발급 시작 시각이 지났다
+	CampaignOpened EventType = "campaign.opened"
+	// CampaignSoldOut 재고가 모두 발급되었다
+	CampaignSoldOut EventType = "campaign.sold_out"
+	// CampaignPaused 운영자가 발급을 일시 중지했다
+	CampaignPaused EventType = "campaign.paused"
+	// CampaignResumed 일시 중지한 발급을 다시 시작했다
+	CampaignResumed EventType = "campaign.resumed"
+
+	CouponIssued   EventType = "coupon.issued"
+	CouponRedeemed EventType = "coupon.redeemed"
+	CouponRevoked  EventType = "coupon.revoked"
+	// CouponExpiringSoon 사용 기간이 곧 끝나는 미사용 쿠폰
+	CouponExpiringSoon EventType = "coupon.expiring_soon"
+	// CouponExpired 사용하지 않은 채 사용 기간이 끝나 만료 처리된 쿠폰
+	CouponExpired EventType = "coupon.expired"
+)
+
+// EventVersion 페이로드 형식의 버전. 하위 호환되지 않게 바뀌면 올린다
+const EventVersion = 1
+
+// Event 도메인 이벤트의 envelope
+//   - Key: 이벤트가 속한 캠페인 ID. 같은 캠페인의 이벤트 순서를 유지하는 파티션 키로 사용한다
+//   - Payload: 캠페인 이벤트는 CampaignEventPayload, 쿠폰 이벤트는 CouponEventPayload 이다
+type Event struct {
+	ID         string          `json:"id"`
+	Type       EventType       `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Key        string          `json:"key"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// EventPublisher 도메인 이벤트를 메시지 버스에 전달한다
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+type CampaignEventPayload struct {
+	CampaignID  string    `json:"campaign_id"`
+	Name        string    `json:"name"`
+	IssueAmount int64     `json:"issue_amount"`
+	IssuedAt    time.Time `json:"issued_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type CouponEventPayload struct {
+	CampaignID     string             `json:"campaign_id"`
+	IssuedCouponID string             `json:"issued_coupon_id"`
+	UserID         string             `json:"user_id"`
+	Code           string             `json:"code"`
+	Status         IssuedCouponStatus `json:"status"`
+	ValidFrom      time.Time          `json:"valid_from"`
+	ValidUntil     *time.Time         `json:"valid_until,omitempty"`
+	RedeemedAt     *time.Time         `json:"redeemed_at,omitempty"`
+}
+
+func NewCampaignEvent(eventType EventType, coupon *Coupon, occurredAt time.Time) Event {
+	return newEvent(eventType, coupon.ID, CampaignEventPayload{
+		CampaignID:  coupon.ID,
+		Name:        coupon.Name,
+		IssueAmount: coupon.IssueAmount,
+		IssuedAt:    coupon.IssuedAt,
+		ExpiresAt:   coupon.ExpiresAt,
+	}, occurredAt)
+}
+
+func NewCouponEvent(eventType EventType, issuedCoupon *IssuedCoupon, occurredAt time.Time) Event {
+	return newEvent(eventType, issuedCoupon.CouponID, CouponEventPayload{
+		CampaignID:     issuedCoupon.CouponID,
+		IssuedCouponID: issuedCoupon.ID,
+		UserID:         issuedCoupon.UserID,
+		Code:           issuedCoupon.Code,
+		Status:         issuedCoupon.Status,
+		ValidFrom:      issuedCoupon.ValidFrom,
+		ValidUntil:     issuedCoupon.ValidUntil,
+		RedeemedAt:     issuedCoupon.RedeemedAt,
+	}, occurredAt)
+}
+
+// NewCouponEvents 같은 시각에 발생한 쿠폰별 이벤트
+func NewCouponEvents(eventType EventType, issuedCoupons []IssuedCoupon, occurredAt time.Time) []Event {
+	events := make([]Event, len(issuedCoupons))
+	for i := range issuedCoupons {
+		events[i] = NewCouponEvent(eventType, &issuedCoupons[i], occurredAt)
+	}
+	return events
+}
+
+// newEvent 페이로드는 JSON 으로 변환할 수 없는 값을 갖지 않으므로 변환 에러를 무시한다
+func newEvent(eventType EventType, key string, payload interface{}, occurredAt time.Time) Event {
+	data, _ := json.Marshal(payload)
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Version:    EventVersion,
+		OccurredAt: occurredAt,
+		Key:        key,
+		Payload:    data,
+	}
+}
+
+// DecodePayload 이벤트의 페이로드를 v 로 변환한다
+func (e Event) DecodePayload(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewCouponEvent(t *testing.T) {
+	now := time.Now()
+	issuedCoupon, err := NewIssuedCoupon("campaign-1", "user-1", now)
+	require.NoError(t, err)
+	issuedCoupon.Redeem(now)
+
+	event := NewCouponEvent(CouponRedeemed, issuedCoupon, now)
+
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, CouponRedeemed, event.Type)
+	assert.Equal(t, EventVersion, event.Version)
+	assert.Equal(t, "campaign-1", event.Key)
+	var payload CouponEventPayload
+	require.NoError(t, event.DecodePayload(&payload))
+	assert.Equal(t, issuedCoupon.ID, payload.IssuedCouponID)
+	assert.Equal(t, IssuedCouponRedeemed, payload.Status)
+	assert.NotNil(t, payload.RedeemedAt)
+}
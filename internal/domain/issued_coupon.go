@@ -1,29 +1,87 @@
 package domain
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"math/rand"
 	"time"
+	"unicode/utf8"
 )
 
+// IssuedCouponStatus 발급된 쿠폰의 상태
+type IssuedCouponStatus string
+
+const (
+	IssuedCouponIssued   IssuedCouponStatus = "issued"
+	IssuedCouponRedeemed IssuedCouponStatus = "redeemed"
+	// IssuedCouponExpired 사용하지 않은 채 사용 기간이 지났다
+	IssuedCouponExpired IssuedCouponStatus = "expired"
+	// IssuedCouponRevoked 운영자가 발급을 취소했다
+	IssuedCouponRevoked IssuedCouponStatus = "revoked"
+)
+
+// IssuedCoupon Sequence 는 같은 캠페인에서 같은 사용자에게 발급된 순번(0 부터)이다
+// 공용 코드 캠페인이 아니면 사용자별로 한 번만 발급되므로 항상 0 이다
+// ValidFrom, ValidUntil 은 발급 시점에 캠페인의 사용 기간으로 계산하며, ValidUntil 이 nil 이면 기한이 없다
 type IssuedCoupon struct {
-	ID         string    `json:"id"`
-	CouponID   string    `json:"coupon_id"`
-	Code       string    `json:"code"`
-	CreatedAt  time.Time `json:"created_at"`
-	ModifiedAt time.Time `json:"modified_at"`
+	ID         string             `json:"id"`
+	CouponID   string             `json:"coupon_id"`
+	UserID     string             `json:"user_id"`
+	Code       string             `json:"code"`
+	Sequence   int                `json:"sequence"`
+	Status     IssuedCouponStatus `json:"status"`
+	RedeemedAt *time.Time         `json:"redeemed_at,omitempty"`
+	ValidFrom  time.Time          `json:"valid_from"`
+	ValidUntil *time.Time         `json:"valid_until,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ModifiedAt time.Time          `json:"modified_at"`
 }
 
-func NewIssuedCoupon(couponId string, createdAt time.Time) *IssuedCoupon {
+// MaxUserIDLength issued_coupons.user_id 컬럼(varchar(64))의 최대 길이
+const MaxUserIDLength = 64
+
+func NewIssuedCoupon(couponId string, userId string, createdAt time.Time) (*IssuedCoupon, error) {
+	v := &validator{}
+	v.check(userId != "", "user_id", "must not be empty")
+	v.check(
+		utf8.RuneCountInString(userId) <= MaxUserIDLength,
+		"user_id", fmt.Sprintf("must be at most %d characters", MaxUserIDLength),
+	)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
 	id := uuid.New()
 
 	return &IssuedCoupon{
 		ID:         id.String(),
 		CouponID:   couponId,
+		UserID:     userId,
 		Code:       generateUniqueCode(),
+		Status:     IssuedCouponIssued,
+		ValidFrom:  createdAt,
 		CreatedAt:  createdAt,
 		ModifiedAt: createdAt,
+	}, nil
+}
+
+// Redeem 쿠폰을 사용한 상태로 바꾼다
+func (i *IssuedCoupon) Redeem(redeemedAt time.Time) {
+	i.Status = IssuedCouponRedeemed
+	i.RedeemedAt = &redeemedAt
+}
+
+// ApplyValidity 캠페인의 사용 기간으로 쿠폰의 사용 기간을 정한다
+func (i *IssuedCoupon) ApplyValidity(validity UsageValidity) {
+	i.ValidFrom, i.ValidUntil = validity.Window(i.CreatedAt)
+}
+
+// UsableAt t 가 사용 기간 안에 있는지 여부
+func (i *IssuedCoupon) UsableAt(t time.Time) bool {
+	if t.Before(i.ValidFrom) {
+		return false
 	}
+	return i.ValidUntil == nil || t.Before(*i.ValidUntil)
 }
 
 func generateUniqueCode() string {
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxPerUserLimit 공용 코드 캠페인에서 한 사용자가 사용할 수 있는 최대 횟수의 상한
+const MaxPerUserLimit = 100
+
+var sharedCodePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// WithSharedCode "SPRING2026" 과 같이 여러 사용자가 함께 사용하는 공용 코드 캠페인으로 만든다
+// 발급 수량은 전체 사용 한도이며, 한 사용자는 perUserLimit 번까지 사용할 수 있다
+func WithSharedCode(code string, perUserLimit int) CouponOption {
+	return func(c *Coupon) {
+		c.CodeSource = CodeSourceShared
+		c.SharedCode = NormalizeSharedCode(code)
+		c.PerUserLimit = perUserLimit
+	}
+}
+
+// NormalizeSharedCode 공용 코드는 대소문자를 구분하지 않으므로 대문자로 저장하고 비교한다
+func NormalizeSharedCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// UsesSharedCode 공용 코드 캠페인인지 여부
+func (c *Coupon) UsesSharedCode() bool {
+	return c.CodeSource == CodeSourceShared
+}
+
+// UserLimit 한 사용자가 발급받을 수 있는 횟수. 공용 코드 캠페인이 아니면 1 이다
+func (c *Coupon) UserLimit() int {
+	if c.UsesSharedCode() && c.PerUserLimit > 1 {
+		return c.PerUserLimit
+	}
+	return 1
+}
+
+func (c *Coupon) validateSharedCode(v *validator) {
+	if !c.UsesSharedCode() {
+		return
+	}
+	v.check(c.SharedCode != "", "shared_code", "must not be empty")
+	v.check(
+		utf8.RuneCountInString(c.SharedCode) <= MaxCodeLength,
+		"shared_code", fmt.Sprintf("must be at most %d characters", MaxCodeLength),
+	)
+	v.check(
+		c.SharedCode == "" || sharedCodePattern.MatchString(c.SharedCode),
+		"shared_code", "must contain only letters, digits, '-' and '_'",
+	)
+	v.check(
+		c.PerUserLimit >= 1 && c.PerUserLimit <= MaxPerUserLimit,
+		"per_user_limit", fmt.Sprintf("must be between 1 and %d", MaxPerUserLimit),
+	)
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewCouponWithSharedCode(t *testing.T) {
+	now := time.Now()
+
+	t.Run("공용 코드는 대문자로 저장되고 사용자당 사용 횟수를 따라야 한다", func(t *testing.T) {
+		coupon, err := NewCoupon("봄 프로모션", 100, now, now.Add(time.Hour), WithSharedCode(" spring2026 ", 3))
+
+		require.NoError(t, err)
+		assert.True(t, coupon.UsesSharedCode())
+		assert.Equal(t, "SPRING2026", coupon.SharedCode)
+		assert.Equal(t, 3, coupon.UserLimit())
+	})
+
+	t.Run("공용 코드 캠페인이 아니면 사용자당 한 번만 발급받을 수 있어야 한다", func(t *testing.T) {
+		coupon, err := NewCoupon("일반 쿠폰", 100, now, now.Add(time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, coupon.UserLimit())
+	})
+
+	t.Run("올바르지 않은 공용 코드와 사용 횟수가 거부되어야 한다", func(t *testing.T) {
+		_, err := NewCoupon("봄 프로모션", 100, now, now.Add(time.Hour), WithSharedCode("SPRING 2026", 0))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t,
+			"shared_code: must contain only letters, digits, '-' and '_', per_user_limit: must be between 1 and 100",
+			validationErr.Error(),
+		)
+	})
+}
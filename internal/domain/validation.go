@@ -0,0 +1,40 @@
+package domain
+
+import "strings"
+
+// FieldViolation 하나의 필드가 위반한 검증 규칙
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ValidationError 도메인 객체 생성 시 검증에 실패한 필드 목록
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Field + ": " + violation.Description
+	}
+	return strings.Join(messages, ", ")
+}
+
+// validator 모든 규칙을 확인한 뒤 위반한 필드를 한 번에 반환하기 위해 사용한다
+type validator struct {
+	violations []FieldViolation
+}
+
+func (v *validator) check(ok bool, field string, description string) {
+	if !ok {
+		v.violations = append(v.violations, FieldViolation{Field: field, Description: description})
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// UsageValidity 발급된 쿠폰을 사용할 수 있는 기간. 캠페인 기간(IssuedAt, ExpiresAt)은 발급받을 수 있는 기간이다
+//   - From, Until: 사용할 수 있는 기간의 절대 시각. 지정하지 않으면 제한하지 않는다
+//   - Duration: 발급 후 사용할 수 있는 기간. Until 과 함께 지정하면 더 이른 시각까지 사용할 수 있다
+type UsageValidity struct {
+	From     *time.Time    `json:"from,omitempty"`
+	Until    *time.Time    `json:"until,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// WithUsageValidity 발급된 쿠폰의 사용 기간을 지정한다
+func WithUsageValidity(validity UsageValidity) CouponOption {
+	return func(c *Coupon) {
+		c.Validity = validity
+	}
+}
+
+func (u UsageValidity) validate(v *validator, issuedAt time.Time) {
+	v.check(u.Duration >= 0, "validity.duration", "must not be negative")
+	if u.From != nil && u.Until != nil {
+		v.check(u.Until.After(*u.From), "validity.until", "must be after validity.from")
+	}
+	if u.Until != nil && !issuedAt.IsZero() {
+		v.check(u.Until.After(issuedAt), "validity.until", "must be after issued_at")
+	}
+}
+
+// Window issuedAt 에 발급된 쿠폰의 사용 기간. until 이 nil 이면 기한 없이 사용할 수 있다
+func (u UsageValidity) Window(issuedAt time.Time) (from time.Time, until *time.Time) {
+	from = issuedAt
+	if u.From != nil && u.From.After(issuedAt) {
+		from = *u.From
+	}
+	if u.Duration > 0 {
+		relative := issuedAt.Add(u.Duration)
+		until = &relative
+	}
+	if u.Until != nil && (until == nil || u.Until.Before(*until)) {
+		absolute := *u.Until
+		until = &absolute
+	}
+	return from, until
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUsageValidityWindow(t *testing.T) {
+	issuedAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("사용 기간을 지정하지 않으면 발급 시각부터 기한 없이 사용할 수 있어야 한다", func(t *testing.T) {
+		from, until := UsageValidity{}.Window(issuedAt)
+
+		assert.Equal(t, issuedAt, from)
+		assert.Nil(t, until)
+	})
+
+	t.Run("발급 후 사용 기간과 종료 시각 중 더 이른 시각까지 사용할 수 있어야 한다", func(t *testing.T) {
+		absolute := issuedAt.Add(48 * time.Hour)
+		_, until := UsageValidity{Until: &absolute, Duration: 72 * time.Hour}.Window(issuedAt)
+		assert.Equal(t, absolute, *until)
+
+		_, until = UsageValidity{Duration: 24 * time.Hour}.Window(issuedAt)
+		assert.Equal(t, issuedAt.Add(24*time.Hour), *until)
+	})
+
+	t.Run("시작 시각 전에 발급된 쿠폰은 시작 시각부터 사용할 수 있어야 한다", func(t *testing.T) {
+		start := issuedAt.Add(time.Hour)
+		issued, err := NewIssuedCoupon("coupon", "user", issuedAt)
+		require.NoError(t, err)
+
+		issued.ApplyValidity(UsageValidity{From: &start, Duration: 2 * time.Hour})
+
+		assert.Equal(t, start, issued.ValidFrom)
+		assert.False(t, issued.UsableAt(issuedAt))
+		assert.True(t, issued.UsableAt(start))
+		assert.False(t, issued.UsableAt(issuedAt.Add(2*time.Hour)))
+	})
+}
+
+func TestNewCouponWithUsageValidity(t *testing.T) {
+	now := time.Now()
+	from := now.Add(-2 * time.Hour)
+	until := now.Add(-time.Hour)
+
+	_, err := NewCoupon("사용 기간", 10, now, now.Add(time.Hour),
+		WithUsageValidity(UsageValidity{From: &until, Until: &from, Duration: -time.Hour}))
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t,
+		"validity.duration: must not be negative, validity.until: must be after validity.from, validity.until: must be after issued_at",
+		validationErr.Error(),
+	)
+}
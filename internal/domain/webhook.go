@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxWebhookURLLength webhook_subscriptions.url 컬럼(varchar(512))의 최대 길이
+	MaxWebhookURLLength = 512
+	// MinWebhookSecretLength 서명 키를 추측할 수 없도록 요구하는 최소 길이
+	MinWebhookSecretLength = 16
+	// MaxWebhookSecretLength webhook_subscriptions.secret 컬럼(varchar(128))의 최대 길이
+	MaxWebhookSecretLength = 128
+)
+
+// eventTypes 웹훅으로 구독할 수 있는 이벤트 종류
+var eventTypes = []EventType{
+	CampaignCreated, CampaignOpened, CampaignSoldOut,
+	CouponIssued, CouponRedeemed, CouponRevoked, CouponExpiringSoon, CouponExpired,
+}
+
+// WebhookSubscription 캠페인의 이벤트를 제휴사 URL 로 전달하는 구독
+//   - Secret: 전달하는 요청 본문의 HMAC-SHA256 서명 키
+//   - EventTypes: 전달할 이벤트 종류. 비어있으면 모든 이벤트를 전달한다
+type WebhookSubscription struct {
+	ID         string      `json:"id"`
+	CouponID   string      `json:"coupon_id"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"-"`
+	EventTypes []EventType `json:"event_types"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// NewWebhookSubscription secret 이 비어있으면 임의의 서명 키를 만든다
+// 입력값이 올바르지 않으면 위반한 필드를 담은 ValidationError 를 반환한다
+func NewWebhookSubscription(
+	couponId string,
+	rawURL string,
+	secret string,
+	types []EventType,
+	now time.Time,
+) (*WebhookSubscription, error) {
+	if secret == "" {
+		secret = newWebhookSecret()
+	}
+
+	v := &validator{}
+	v.check(couponId != "", "campaign_id", "must not be empty")
+	v.check(
+		utf8.RuneCountInString(rawURL) <= MaxWebhookURLLength,
+		"url", fmt.Sprintf("must be at most %d characters", MaxWebhookURLLength),
+	)
+	parsed, err := url.Parse(rawURL)
+	v.check(
+		err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "",
+		"url", "must be an absolute http or https URL",
+	)
+	v.check(
+		len(secret) >= MinWebhookSecretLength && len(secret) <= MaxWebhookSecretLength,
+		"secret", fmt.Sprintf("must be between %d and %d characters", MinWebhookSecretLength, MaxWebhookSecretLength),
+	)
+	var subscribed []EventType
+	for _, eventType := range types {
+		v.check(slices.Contains(eventTypes, eventType), "event_types", fmt.Sprintf("unknown event type %q", eventType))
+		if !slices.Contains(subscribed, eventType) {
+			subscribed = append(subscribed, eventType)
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return &WebhookSubscription{
+		ID:         uuid.New().String(),
+		CouponID:   couponId,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: subscribed,
+		CreatedAt:  now,
+	}, nil
+}
+
+// Subscribes 이벤트를 전달해야 하는지 여부
+func (s *WebhookSubscription) Subscribes(eventType EventType) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WebhookDeliveryStatus 웹훅 전달 상태
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending 전달 대기 중이거나 실패하여 재시도를 기다린다
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded 수신 서버가 2xx 로 응답했다
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryDead 최대 재시도 횟수를 넘겨 더 이상 전달하지 않는다 (dead letter)
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery 하나의 구독에 하나의 이벤트를 전달하는 작업
+//   - Payload: 요청 본문으로 보내는 이벤트 JSON
+//   - NextAttemptAt: 다음 전달 시각
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	SubscriptionID string                `json:"subscription_id"`
+	CouponID       string                `json:"coupon_id"`
+	EventID        string                `json:"event_id"`
+	EventType      EventType             `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	History        []WebhookAttempt      `json:"history,omitempty"`
+}
+
+// NewWebhookDelivery 이벤트를 바로 전달할 수 있는 대기 상태로 만든다
+func NewWebhookDelivery(subscription *WebhookSubscription, event Event, now time.Time) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscription.ID,
+		CouponID:       subscription.CouponID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}, nil
+}
+
+// WebhookAttempt 한 번의 전달 시도 결과. StatusCode 는 응답을 받지 못했으면 0 이다
+type WebhookAttempt struct {
+	Attempt     int           `json:"attempt"`
+	StatusCode  int           `json:"status_code"`
+	Error       string        `json:"error,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	AttemptedAt time.Time     `json:"attempted_at"`
+}
+
+// Succeeded 수신 서버가 2xx 로 응답했는지 여부
+func (a WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// Record 전달 결과를 반영한다. 실패하면 backoff 후 재시도하도록 하고, maxAttempts 번 실패하면 dead letter 로 옮긴다
+func (d *WebhookDelivery) Record(attempt WebhookAttempt, maxAttempts int, backoff func(attempts int) time.Duration) {
+	d.Attempts++
+	attempt.Attempt = d.Attempts
+	d.History = append(d.History, attempt)
+	switch {
+	case attempt.Succeeded():
+		d.Status = WebhookDeliverySucceeded
+		deliveredAt := attempt.AttemptedAt
+		d.DeliveredAt = &deliveredAt
+	case d.Attempts >= maxAttempts:
+		d.Status = WebhookDeliveryDead
+	default:
+		d.NextAttemptAt = attempt.AttemptedAt.Add(backoff(d.Attempts))
+	}
+}
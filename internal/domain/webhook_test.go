@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewWebhookSubscription(t *testing.T) {
+	now := time.Now()
+
+	t.Run("서명 키를 지정하지 않으면 임의의 키를 만들어야 한다", func(t *testing.T) {
+		subscription, err := NewWebhookSubscription("campaign-1", "https://partner.example.com/hooks", "", nil, now)
+
+		require.NoError(t, err)
+		assert.Len(t, subscription.Secret, 64)
+		assert.True(t, subscription.Subscribes(CampaignSoldOut))
+	})
+
+	t.Run("구독한 이벤트만 전달해야 한다", func(t *testing.T) {
+		subscription, err := NewWebhookSubscription("campaign-1", "https://partner.example.com/hooks", "",
+			[]EventType{CampaignSoldOut, CouponRedeemed, CampaignSoldOut}, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, []EventType{CampaignSoldOut, CouponRedeemed}, subscription.EventTypes)
+		assert.True(t, subscription.Subscribes(CouponRedeemed))
+		assert.False(t, subscription.Subscribes(CouponIssued))
+	})
+
+	t.Run("올바르지 않은 입력값은 모든 위반 필드를 반환해야 한다", func(t *testing.T) {
+		_, err := NewWebhookSubscription("", "ftp://partner.example.com", "short", []EventType{"coupon.unknown"}, now)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		fields := make([]string, len(validationErr.Violations))
+		for i, violation := range validationErr.Violations {
+			fields[i] = violation.Field
+		}
+		assert.ElementsMatch(t, []string{"campaign_id", "url", "secret", "event_types"}, fields)
+	})
+}
+
+func TestWebhookDeliveryRecord(t *testing.T) {
+	now := time.Now()
+	subscription, err := NewWebhookSubscription("campaign-1", "https://partner.example.com/hooks", "", nil, now)
+	require.NoError(t, err)
+	event := NewCampaignEvent(CampaignSoldOut, &Coupon{ID: "campaign-1"}, now)
+	backoff := func(attempts int) time.Duration { return time.Duration(attempts) * time.Minute }
+
+	t.Run("실패하면 backoff 후 재시도하고 최대 횟수를 넘기면 dead letter 로 옮겨야 한다", func(t *testing.T) {
+		delivery, err := NewWebhookDelivery(subscription, event, now)
+		require.NoError(t, err)
+
+		delivery.Record(WebhookAttempt{StatusCode: 500, Error: "status 500", AttemptedAt: now}, 2, backoff)
+		assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+
+		delivery.Record(WebhookAttempt{Error: "timeout", AttemptedAt: now.Add(time.Minute)}, 2, backoff)
+		assert.Equal(t, WebhookDeliveryDead, delivery.Status)
+		assert.Equal(t, 2, delivery.History[1].Attempt)
+	})
+
+	t.Run("2xx 응답을 받으면 전달 완료로 기록해야 한다", func(t *testing.T) {
+		delivery, err := NewWebhookDelivery(subscription, event, now)
+		require.NoError(t, err)
+
+		delivery.Record(WebhookAttempt{StatusCode: 204, AttemptedAt: now}, 2, backoff)
+
+		assert.Equal(t, WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, now, *delivery.DeliveredAt)
+	})
+}
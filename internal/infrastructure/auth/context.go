@@ -0,0 +1,23 @@
+package auth
+
+import "context"
+
+// Principal 인증된 호출자
+type Principal struct {
+	Claims *Claims
+	// Admin 관리자 scope 를 가진 호출자인지 여부
+	Admin bool
+}
+
+type principalKey struct{}
+
+// WithPrincipal 인증된 호출자를 요청 컨텍스트에 담는다
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext 인증이 비활성화되어 있어 인증을 거치지 않은 요청이면 false 를 반환한다
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
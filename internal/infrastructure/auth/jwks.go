@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"os"
+
+	"github.com/MicahParks/keyfunc/v3"
+)
+
+// NewJWKSVerifier JWKS 파일의 공개 키(RSA, EC)로 서명된 토큰을 검증한다. 토큰의 kid 로 검증 키를 고른다
+func NewJWKSVerifier(path string, opts ...VerifierOption) (*Verifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := keyfunc.NewJWKSetJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return newVerifier(keys.Keyfunc, publicKeyMethods, opts...), nil
+}
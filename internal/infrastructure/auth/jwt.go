@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// clockSkew 토큰 발급 서버와의 시간 차이를 허용하는 범위
+const clockSkew = 30 * time.Second
+
+// Claims 토큰에서 사용하는 클레임
+type Claims struct {
+	Subject   string
+	Scopes    []string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	// AccountCreatedAt 사용자의 가입 일시(account_created_at). 토큰에 없으면 zero time 이다
+	AccountCreatedAt time.Time
+	// Segments, Region 발급 대상 조건에 사용하는 사용자 세그먼트(segments)와 지역(region)
+	Segments []string
+	Region   string
+}
+
+// HasScope scope 를 가지고 있는지 확인한다
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenClaims scope 는 공백으로 구분된 문자열(scope) 또는 배열(scp) 로 전달될 수 있다
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+	// AccountCreatedAt 가입 일시(Unix time). 표준 클레임이 아니며 어뷰징 검사에 사용한다
+	AccountCreatedAt *jwt.NumericDate `json:"account_created_at"`
+	// Segments, Region 표준 클레임이 아니며 발급 대상 조건에 사용한다
+	Segments []string `json:"segments"`
+	Region   string   `json:"region"`
+}
+
+// VerifierOption 토큰 검증 시 선택적으로 확인하는 항목
+type VerifierOption func(*Verifier)
+
+// WithIssuer iss 클레임이 issuer 와 같아야 한다
+func WithIssuer(issuer string) VerifierOption {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience aud 클레임에 audience 가 포함되어야 한다
+func WithAudience(audience string) VerifierOption {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+var (
+	// hmacMethods 공유 비밀 키로 검증하는 서명 알고리즘
+	hmacMethods = []string{"HS256", "HS384", "HS512"}
+	// publicKeyMethods JWKS 의 공개 키(RSA, EC)로 검증하는 서명 알고리즘
+	publicKeyMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+)
+
+// Verifier JWT 의 서명과 유효 기간을 검증한다
+// 서명 알고리즘은 키 종류에 맞는 것만 허용하며 none 은 허용하지 않는다
+type Verifier struct {
+	keyfunc  jwt.Keyfunc
+	methods  []string
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+func newVerifier(keyfunc jwt.Keyfunc, methods []string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{keyfunc: keyfunc, methods: methods, now: time.Now}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// NewHMACVerifier 공유 비밀 키로 서명된(HS256, HS384, HS512) 토큰을 검증한다
+func NewHMACVerifier(secret []byte, opts ...VerifierOption) *Verifier {
+	keyfunc := func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}
+	return newVerifier(keyfunc, hmacMethods, opts...)
+}
+
+// Verify 토큰을 검증하고 클레임을 반환한다
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(v.audience))
+	}
+
+	var raw tokenClaims
+	_, err := jwt.ParseWithClaims(token, &raw, v.keyfunc, parserOpts...)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrTokenExpired
+	}
+	if err != nil || raw.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return raw.toClaims(), nil
+}
+
+func (r tokenClaims) toClaims() *Claims {
+	claims := &Claims{
+		Subject:  r.Subject,
+		Issuer:   r.Issuer,
+		Audience: r.Audience,
+		Scopes:   r.Scp,
+		Segments: r.Segments,
+		Region:   r.Region,
+	}
+	if r.Scope != "" {
+		claims.Scopes = append(claims.Scopes, strings.Fields(r.Scope)...)
+	}
+	if r.ExpiresAt != nil {
+		claims.ExpiresAt = r.ExpiresAt.Time
+	}
+	if r.NotBefore != nil {
+		claims.NotBefore = r.NotBefore.Time
+	}
+	if r.AccountCreatedAt != nil {
+		claims.AccountCreatedAt = r.AccountCreatedAt.Time
+	}
+	return claims
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func signHS256(t *testing.T, secret []byte, claims jwt.MapClaims) string {
+	return sign(t, jwt.SigningMethodHS256, secret, "", claims)
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"scope": "coupon:admin profile",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestHMACVerifier(t *testing.T) {
+	secret := []byte("secret")
+	verifier := NewHMACVerifier(secret, WithIssuer("issuer"))
+
+	t.Run("올바르게 서명된 토큰의 클레임이 반환되어야 한다", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "issuer"
+
+		result, err := verifier.Verify(signHS256(t, secret, claims))
+
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", result.Subject)
+		assert.True(t, result.HasScope("coupon:admin"))
+	})
+
+	t.Run("다른 키로 서명된 토큰은 거부되어야 한다", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "issuer"
+
+		_, err := verifier.Verify(signHS256(t, []byte("other"), claims))
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("만료된 토큰은 거부되어야 한다", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "issuer"
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		_, err := verifier.Verify(signHS256(t, secret, claims))
+
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("발급자가 다르면 거부되어야 한다", func(t *testing.T) {
+		_, err := verifier.Verify(signHS256(t, secret, validClaims()))
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("서명이 없는 토큰은 거부되어야 한다", func(t *testing.T) {
+		token := sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims())
+
+		_, err := verifier.Verify(token)
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestJWKSVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encodeInt := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y)},
+		},
+	}
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	verifier, err := NewJWKSVerifier(path)
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"RS256": sign(t, jwt.SigningMethodRS256, rsaKey, "rsa", validClaims()),
+		"ES256": sign(t, jwt.SigningMethodES256, ecKey, "ec", validClaims()),
+	} {
+		t.Run(fmt.Sprintf("%s 로 서명된 토큰이 검증되어야 한다", name), func(t *testing.T) {
+			claims, err := verifier.Verify(token)
+
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.Subject)
+		})
+	}
+
+	t.Run("kid 와 다른 키로 서명된 토큰은 거부되어야 한다", func(t *testing.T) {
+		_, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, "ec", validClaims()))
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("공개 키를 비밀 키로 사용한 HMAC 토큰은 거부되어야 한다", func(t *testing.T) {
+		publicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, publicKey, "rsa", validClaims()))
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
@@ -7,29 +7,70 @@ import (
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"log"
+	"strings"
+	"sync"
 	"time"
 )
 
+var ErrKeyNotFound = errors.New("key not found")
+
 type Cache interface {
 	SetAdd(ctx context.Context, key string, value string) (bool, error)
 	SetDel(ctx context.Context, key string, value string) (bool, error)
+	SetIsMember(ctx context.Context, key string, value string) (bool, error)
+	SetCard(ctx context.Context, key string) (int64, error)
+	SetMembers(ctx context.Context, key string) ([]string, error)
+	ListPush(ctx context.Context, key string, values ...string) (int64, error)
+	ListPop(ctx context.Context, key string) (string, error)
+	ListLen(ctx context.Context, key string) (int64, error)
 	Set(ctx context.Context, key string, value interface{}) error
 	Get(ctx context.Context, key string) ([]byte, error)
+	GetInt(ctx context.Context, key string) (int64, error)
 	Del(ctx context.Context, key string) error
 	Incr(ctx context.Context, key string) (int64, error)
 	Decr(ctx context.Context, key string) (int64, error)
 	ExpireAt(ctx context.Context, key string, expr time.Time) (bool, error)
+	Publish(ctx context.Context, channel string, message string) error
+	Subscribe(ctx context.Context, channel string) <-chan string
+	AcquireLease(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, key string, owner string) error
+	Batch(ctx context.Context, reads []BatchRead) ([]BatchResult, error)
+	Scan(ctx context.Context, pattern string) ([]string, error)
+	Move(ctx context.Context, from string, to string) (bool, error)
+}
+
+// BatchRead Batch 로 함께 조회할 키. Member 가 있으면 집합에 포함되는지를, Len 이면 리스트의 길이를,
+// 아니면 값을 조회한다
+type BatchRead struct {
+	Key    string
+	Len    bool
+	Member string
 }
 
+// BatchResult Found 는 키가 있는지(Member 로 조회하면 집합에 포함되는지) 여부이며, Len 으로 조회한 키는 Len 에 길이를 담는다
+type BatchResult struct {
+	Value []byte
+	Len   int64
+	Found bool
+}
+
+// releaseLeaseScript 다른 소유자가 다시 획득한 임대를 해제하지 않도록 소유자가 같을 때만 삭제한다
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
 type cache struct {
-	redisClient *redis.Client
+	redisClient redis.UniversalClient
 }
 
 func (c cache) SetAdd(ctx context.Context, key string, value string) (bool, error) {
 	added, err := c.redisClient.SAdd(ctx, key, value).Result()
 	if err != nil {
 		log.Println(err)
-		return false, errors.New("occurred an error when adding value to cache")
+		return false, fmt.Errorf("occurred an error when adding value to cache: %w", err)
 	}
 	if added == 0 {
 		return false, nil
@@ -41,7 +82,7 @@ func (c cache) SetDel(ctx context.Context, key string, value string) (bool, erro
 	result, err := c.redisClient.SRem(ctx, key, value).Result()
 	if err != nil {
 		log.Println(err)
-		return false, errors.New("occurred an error when deleting value from cache")
+		return false, fmt.Errorf("occurred an error when deleting value from cache: %w", err)
 	}
 	if result == 0 {
 		return false, nil
@@ -50,6 +91,69 @@ func (c cache) SetDel(ctx context.Context, key string, value string) (bool, erro
 	return true, nil
 }
 
+func (c cache) SetIsMember(ctx context.Context, key string, value string) (bool, error) {
+	result, err := c.redisClient.SIsMember(ctx, key, value).Result()
+	if err != nil {
+		log.Println(err)
+		return false, fmt.Errorf("occurred an error when checking value in cache: %w", err)
+	}
+	return result, nil
+}
+
+func (c cache) SetCard(ctx context.Context, key string) (int64, error) {
+	result, err := c.redisClient.SCard(ctx, key).Result()
+	if err != nil {
+		log.Println(err)
+		return 0, fmt.Errorf("occurred an error when counting values in cache: %w", err)
+	}
+	return result, nil
+}
+
+func (c cache) SetMembers(ctx context.Context, key string) ([]string, error) {
+	result, err := c.redisClient.SMembers(ctx, key).Result()
+	if err != nil {
+		log.Println(err)
+		return nil, fmt.Errorf("occurred an error when getting values from cache: %w", err)
+	}
+	return result, nil
+}
+
+// ListPush 리스트의 끝에 값을 추가하고 추가한 후의 길이를 반환한다
+func (c cache) ListPush(ctx context.Context, key string, values ...string) (int64, error) {
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+	result, err := c.redisClient.RPush(ctx, key, args...).Result()
+	if err != nil {
+		log.Println(err)
+		return 0, fmt.Errorf("occurred an error when pushing values to cache: %w", err)
+	}
+	return result, nil
+}
+
+// ListPop 리스트의 첫 번째 값을 꺼낸다. 리스트가 비어있으면 ErrKeyNotFound 를 반환한다
+func (c cache) ListPop(ctx context.Context, key string) (string, error) {
+	result, err := c.redisClient.LPop(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrKeyNotFound
+		}
+		log.Println(err)
+		return "", fmt.Errorf("occurred an error when popping value from cache: %w", err)
+	}
+	return result, nil
+}
+
+func (c cache) ListLen(ctx context.Context, key string) (int64, error) {
+	result, err := c.redisClient.LLen(ctx, key).Result()
+	if err != nil {
+		log.Println(err)
+		return 0, fmt.Errorf("occurred an error when counting values in cache: %w", err)
+	}
+	return result, nil
+}
+
 func (c cache) Set(ctx context.Context, key string, value interface{}) error {
 	data, marshalErr := json.Marshal(value)
 	if marshalErr != nil {
@@ -58,7 +162,7 @@ func (c cache) Set(ctx context.Context, key string, value interface{}) error {
 	err := c.redisClient.Set(ctx, key, data, 0).Err()
 	if err != nil {
 		fmt.Println(err)
-		return errors.New("occurred an error when setting value to cache")
+		return fmt.Errorf("occurred an error when setting value to cache: %w", err)
 	}
 	return nil
 }
@@ -68,24 +172,38 @@ func (c cache) Get(ctx context.Context, key string) ([]byte, error) {
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			fmt.Println(err)
-			return nil, errors.New("key not found")
+			return nil, ErrKeyNotFound
 		}
 		fmt.Println(err)
-		return nil, errors.New("occurred an error when getting value from cache")
+		return nil, fmt.Errorf("occurred an error when getting value from cache: %w", err)
 	}
 
 	return data, nil
 }
 
+func (c cache) GetInt(ctx context.Context, key string) (int64, error) {
+	result, err := c.redisClient.Get(ctx, key).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			fmt.Println(err)
+			return 0, ErrKeyNotFound
+		}
+		fmt.Println(err)
+		return 0, fmt.Errorf("occurred an error when getting value from cache: %w", err)
+	}
+
+	return result, nil
+}
+
 func (c cache) Del(ctx context.Context, key string) error {
 	err := c.redisClient.Del(ctx, key).Err()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			fmt.Println(err)
-			return errors.New("key not found")
+			return ErrKeyNotFound
 		}
 		fmt.Println(err)
-		return errors.New("occurred an error when deleting value from cache")
+		return fmt.Errorf("occurred an error when deleting value from cache: %w", err)
 	}
 	return nil
 }
@@ -94,7 +212,7 @@ func (c cache) Incr(ctx context.Context, key string) (int64, error) {
 	result, err := c.redisClient.Incr(ctx, key).Result()
 	if err != nil {
 		log.Println(err)
-		return result, errors.New(fmt.Sprintf("occurred an error when try to increment by the key(%s)", key))
+		return result, fmt.Errorf("occurred an error when try to increment by the key(%s): %w", key, err)
 	}
 
 	return result, nil
@@ -104,7 +222,7 @@ func (c cache) Decr(ctx context.Context, key string) (int64, error) {
 	result, err := c.redisClient.Decr(ctx, key).Result()
 	if err != nil {
 		log.Println(err)
-		return result, errors.New(fmt.Sprintf("occurred an error when try to decrement by the key(%s)", key))
+		return result, fmt.Errorf("occurred an error when try to decrement by the key(%s): %w", key, err)
 	}
 
 	return result, nil
@@ -114,11 +232,170 @@ func (c cache) ExpireAt(ctx context.Context, key string, expr time.Time) (bool,
 	result, err := c.redisClient.ExpireAt(ctx, key, expr).Result()
 	if err != nil {
 		log.Println(err)
-		return result, errors.New(fmt.Sprintf("occurred an error when try to decrement by the key(%s)", key))
+		return result, fmt.Errorf("occurred an error when try to set expiration of the key(%s): %w", key, err)
 	}
 	return result, nil
 }
 
-func NewCacheClient(client *redis.Client) Cache {
+func (c cache) Publish(ctx context.Context, channel string, message string) error {
+	err := c.redisClient.Publish(ctx, channel, message).Err()
+	if err != nil {
+		log.Println(err)
+		return fmt.Errorf("occurred an error when publishing to the channel(%s): %w", channel, err)
+	}
+	return nil
+}
+
+// Subscribe 채널 메시지를 전달하며, ctx 가 종료되면 구독을 해제하고 채널을 닫는다
+func (c cache) Subscribe(ctx context.Context, channel string) <-chan string {
+	messages := make(chan string)
+	pubsub := c.redisClient.Subscribe(ctx, channel)
+
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages
+}
+
+// AcquireLease 키가 없을 때만 owner 를 값으로 ttl 동안 임대를 획득한다. 다른 소유자가 임대 중이면 false 를 반환한다
+func (c cache) AcquireLease(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	result, err := c.redisClient.SetNX(ctx, key, owner, ttl).Result()
+	if err != nil {
+		log.Println(err)
+		return false, fmt.Errorf("occurred an error when acquiring the lease(%s): %w", key, err)
+	}
+	return result, nil
+}
+
+// ReleaseLease owner 가 소유한 임대를 해제한다
+func (c cache) ReleaseLease(ctx context.Context, key string, owner string) error {
+	if err := releaseLeaseScript.Run(ctx, c.redisClient, []string{key}, owner).Err(); err != nil {
+		log.Println(err)
+		return fmt.Errorf("occurred an error when releasing the lease(%s): %w", key, err)
+	}
+	return nil
+}
+
+func NewCacheClient(client redis.UniversalClient) Cache {
 	return &cache{redisClient: client}
 }
+
+// Batch 여러 키를 한 번의 파이프라인으로 조회한다
+func (c cache) Batch(ctx context.Context, reads []BatchRead) ([]BatchResult, error) {
+	results := make([]BatchResult, len(reads))
+	if len(reads) == 0 {
+		return results, nil
+	}
+	cmds := make([]redis.Cmder, len(reads))
+	_, err := c.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, read := range reads {
+			switch {
+			case read.Member != "":
+				cmds[i] = pipe.SIsMember(ctx, read.Key, read.Member)
+			case read.Len:
+				cmds[i] = pipe.LLen(ctx, read.Key)
+			default:
+				cmds[i] = pipe.Get(ctx, read.Key)
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Println(err)
+		return nil, fmt.Errorf("occurred an error when reading keys from cache: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		switch cmd := cmd.(type) {
+		case *redis.BoolCmd:
+			results[i].Found = cmd.Val()
+		case *redis.IntCmd:
+			results[i].Len = cmd.Val()
+			results[i].Found = cmd.Val() > 0
+		case *redis.StringCmd:
+			if cmd.Err() == nil {
+				results[i].Value = []byte(cmd.Val())
+				results[i].Found = true
+			}
+		}
+	}
+	return results, nil
+}
+
+// Scan pattern 에 맞는 키를 SCAN 으로 찾는다. 클러스터에서는 모든 마스터 노드를 조회한다
+func (c cache) Scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	scan := func(ctx context.Context, client redis.Cmdable) error {
+		iter := client.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		return iter.Err()
+	}
+
+	var err error
+	if cluster, ok := c.redisClient.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return scan(ctx, client)
+		})
+	} else {
+		err = scan(ctx, c.redisClient)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("occurred an error when scanning keys(%s): %w", pattern, err)
+	}
+	return keys, nil
+}
+
+// Move from 의 값을 to 로 옮긴다. 클러스터에서 두 키의 슬롯이 달라도 동작하도록 DUMP, RESTORE 후 from 을 삭제한다
+// from 이 없거나 to 가 이미 있으면 옮기지 않고 false 를 반환하며, to 가 이미 있으면 from 은 삭제한다
+func (c cache) Move(ctx context.Context, from string, to string) (bool, error) {
+	dump, err := c.redisClient.Dump(ctx, from).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("occurred an error when dumping the key(%s): %w", from, err)
+	}
+	ttl, err := c.redisClient.PTTL(ctx, from).Result()
+	if err != nil {
+		return false, fmt.Errorf("occurred an error when getting ttl of the key(%s): %w", from, err)
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	moved := true
+	if err = c.redisClient.Restore(ctx, to, ttl, dump).Err(); err != nil {
+		if !strings.HasPrefix(err.Error(), "BUSYKEY") {
+			return false, fmt.Errorf("occurred an error when restoring the key(%s): %w", to, err)
+		}
+		moved = false
+	}
+	if err = c.redisClient.Del(ctx, from).Err(); err != nil {
+		return moved, fmt.Errorf("occurred an error when deleting the key(%s): %w", from, err)
+	}
+	return moved, nil
+}
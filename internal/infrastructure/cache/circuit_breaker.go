@@ -0,0 +1,314 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("cache circuit breaker is open")
+
+type CircuitBreakerConfig struct {
+	// FailureThreshold 회로를 여는 연속 실패 횟수
+	FailureThreshold int
+	// OpenTimeout 회로가 열린 후 half-open 상태로 전환되기까지의 시간
+	OpenTimeout time.Duration
+}
+
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	FailureThreshold: 5,
+	OpenTimeout:      10 * time.Second,
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker Redis 장애가 발생하면 요청을 즉시 실패시켜 장애가 전파되는 것을 막는다
+// half-open 상태에서는 하나의 요청만 통과시켜 복구 여부를 확인한다
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	config   CircuitBreakerConfig
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{config: config}
+}
+
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.config.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 클라이언트가 요청을 취소했거나 요청의 제한 시간이 지난 것은 Redis 장애로 판단할 수 없으므로 세지 않는다
+	// half-open 상태에서 확인 요청이 취소되었으면 다음 요청으로 다시 확인한다
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		b.probing = false
+		return
+	}
+
+	// 키가 없는 것은 Redis 장애가 아니다
+	if err == nil || errors.Is(err, ErrKeyNotFound) {
+		if b.state != breakerClosed {
+			log.Println("cache circuit breaker closed")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		if b.state != breakerOpen {
+			log.Printf("cache circuit breaker opened after %d failures", b.failures)
+		}
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		b.probing = false
+	}
+}
+
+// IsOpen 회로가 열려 있어 요청이 차단되는 상태인지 여부
+func (b *CircuitBreaker) IsOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state == breakerOpen && time.Since(b.openedAt) < b.config.OpenTimeout
+}
+
+type circuitBreakerCache struct {
+	cache   Cache
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerCache 모든 캐시 호출을 breaker 로 감싼다. 회로가 열려 있으면 ErrCircuitOpen 을 반환한다
+func NewCircuitBreakerCache(cache Cache, breaker *CircuitBreaker) Cache {
+	return &circuitBreakerCache{cache: cache, breaker: breaker}
+}
+
+func (c circuitBreakerCache) SetAdd(ctx context.Context, key string, value string) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	result, err := c.cache.SetAdd(ctx, key, value)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) SetDel(ctx context.Context, key string, value string) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	result, err := c.cache.SetDel(ctx, key, value)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) SetIsMember(ctx context.Context, key string, value string) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	result, err := c.cache.SetIsMember(ctx, key, value)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) SetCard(ctx context.Context, key string) (int64, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+	result, err := c.cache.SetCard(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) SetMembers(ctx context.Context, key string) ([]string, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.cache.SetMembers(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) ListPush(ctx context.Context, key string, values ...string) (int64, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+	result, err := c.cache.ListPush(ctx, key, values...)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) ListPop(ctx context.Context, key string) (string, error) {
+	if err := c.breaker.allow(); err != nil {
+		return "", err
+	}
+	result, err := c.cache.ListPop(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) ListLen(ctx context.Context, key string) (int64, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+	result, err := c.cache.ListLen(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) Set(ctx context.Context, key string, value interface{}) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.cache.Set(ctx, key, value)
+	c.breaker.record(err)
+	return err
+}
+
+func (c circuitBreakerCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.cache.Get(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) GetInt(ctx context.Context, key string) (int64, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+	result, err := c.cache.GetInt(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) Del(ctx context.Context, key string) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.cache.Del(ctx, key)
+	c.breaker.record(err)
+	return err
+}
+
+func (c circuitBreakerCache) Incr(ctx context.Context, key string) (int64, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+	result, err := c.cache.Incr(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) Decr(ctx context.Context, key string) (int64, error) {
+	if err := c.breaker.allow(); err != nil {
+		return 0, err
+	}
+	result, err := c.cache.Decr(ctx, key)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) ExpireAt(ctx context.Context, key string, expr time.Time) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	result, err := c.cache.ExpireAt(ctx, key, expr)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) Publish(ctx context.Context, channel string, message string) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.cache.Publish(ctx, channel, message)
+	c.breaker.record(err)
+	return err
+}
+
+// Subscribe 구독은 go-redis 가 재연결을 처리하므로 breaker 를 거치지 않는다
+func (c circuitBreakerCache) Subscribe(ctx context.Context, channel string) <-chan string {
+	return c.cache.Subscribe(ctx, channel)
+}
+
+func (c circuitBreakerCache) AcquireLease(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	result, err := c.cache.AcquireLease(ctx, key, owner, ttl)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) ReleaseLease(ctx context.Context, key string, owner string) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.cache.ReleaseLease(ctx, key, owner)
+	c.breaker.record(err)
+	return err
+}
+
+func (c circuitBreakerCache) Batch(ctx context.Context, reads []BatchRead) ([]BatchResult, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.cache.Batch(ctx, reads)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) Scan(ctx context.Context, pattern string) ([]string, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.cache.Scan(ctx, pattern)
+	c.breaker.record(err)
+	return result, err
+}
+
+func (c circuitBreakerCache) Move(ctx context.Context, from string, to string) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	result, err := c.cache.Move(ctx, from, to)
+	c.breaker.record(err)
+	return result, err
+}
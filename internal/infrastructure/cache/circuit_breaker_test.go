@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errRedis := errors.New("connection refused")
+	config := CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: 50 * time.Millisecond}
+	open := func(b *CircuitBreaker) {
+		for i := 0; i < config.FailureThreshold; i++ {
+			require.NoError(t, b.allow())
+			b.record(errRedis)
+		}
+	}
+
+	t.Run("연속 실패 횟수가 기준에 도달하면 열려야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		for i := 0; i < config.FailureThreshold-1; i++ {
+			b.record(errRedis)
+		}
+		assert.False(t, b.IsOpen())
+
+		b.record(errRedis)
+		assert.True(t, b.IsOpen())
+		assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+	})
+
+	t.Run("성공하면 연속 실패 횟수를 초기화해야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		b.record(errRedis)
+		b.record(errRedis)
+		b.record(nil)
+		b.record(errRedis)
+		b.record(errRedis)
+
+		assert.False(t, b.IsOpen())
+	})
+
+	t.Run("키가 없는 것은 실패로 세지 않아야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		for i := 0; i < config.FailureThreshold; i++ {
+			b.record(ErrKeyNotFound)
+		}
+
+		assert.False(t, b.IsOpen())
+	})
+
+	t.Run("요청 취소와 제한 시간 초과는 실패로 세지 않아야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		for i := 0; i < config.FailureThreshold; i++ {
+			b.record(fmt.Errorf("occurred an error when adding value to cache: %w", context.Canceled))
+			b.record(context.DeadlineExceeded)
+		}
+
+		assert.False(t, b.IsOpen())
+	})
+
+	t.Run("열린 후 제한 시간이 지나면 요청 하나만 통과시켜야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		open(b)
+		time.Sleep(config.OpenTimeout)
+
+		assert.NoError(t, b.allow())
+		assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+	})
+
+	t.Run("half-open 상태의 요청이 성공하면 닫혀야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		open(b)
+		time.Sleep(config.OpenTimeout)
+
+		require.NoError(t, b.allow())
+		b.record(nil)
+
+		assert.False(t, b.IsOpen())
+		assert.NoError(t, b.allow())
+		assert.NoError(t, b.allow())
+	})
+
+	t.Run("half-open 상태의 요청이 실패하면 다시 열려야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		open(b)
+		time.Sleep(config.OpenTimeout)
+
+		require.NoError(t, b.allow())
+		b.record(errRedis)
+
+		assert.True(t, b.IsOpen())
+		assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+	})
+
+	t.Run("half-open 상태의 요청이 취소되면 다음 요청으로 다시 확인해야 한다", func(t *testing.T) {
+		b := NewCircuitBreaker(config)
+		open(b)
+		time.Sleep(config.OpenTimeout)
+
+		require.NoError(t, b.allow())
+		b.record(context.Canceled)
+
+		assert.NoError(t, b.allow())
+	})
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		ttl      time.Duration
+		run      func(c *LocalCache[int])
+		present  []string
+		missing  []string
+	}{
+		{
+			name:     "저장한 값을 조회할 수 있다",
+			capacity: 2,
+			ttl:      time.Minute,
+			run: func(c *LocalCache[int]) {
+				c.Set("a", 1)
+			},
+			present: []string{"a"},
+		},
+		{
+			name:     "용량을 넘으면 가장 오래 사용하지 않은 값을 제거한다",
+			capacity: 2,
+			ttl:      time.Minute,
+			run: func(c *LocalCache[int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Get("a")
+				c.Set("c", 3)
+			},
+			present: []string{"a", "c"},
+			missing: []string{"b"},
+		},
+		{
+			name:     "같은 키를 다시 저장하면 가장 최근에 사용한 값이 된다",
+			capacity: 2,
+			ttl:      time.Minute,
+			run: func(c *LocalCache[int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Set("a", 10)
+				c.Set("c", 3)
+			},
+			present: []string{"a", "c"},
+			missing: []string{"b"},
+		},
+		{
+			name:     "TTL 이 지나면 조회되지 않는다",
+			capacity: 2,
+			ttl:      10 * time.Millisecond,
+			run: func(c *LocalCache[int]) {
+				c.Set("a", 1)
+				time.Sleep(20 * time.Millisecond)
+			},
+			missing: []string{"a"},
+		},
+		{
+			name:     "삭제한 키만 조회되지 않는다",
+			capacity: 2,
+			ttl:      time.Minute,
+			run: func(c *LocalCache[int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Delete("a")
+			},
+			present: []string{"b"},
+			missing: []string{"a"},
+		},
+		{
+			name:     "Purge 하면 모든 값이 조회되지 않는다",
+			capacity: 2,
+			ttl:      time.Minute,
+			run: func(c *LocalCache[int]) {
+				c.Set("a", 1)
+				c.Set("b", 2)
+				c.Purge()
+				c.Set("c", 3)
+			},
+			present: []string{"c"},
+			missing: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLocalCache[int](tt.capacity, tt.ttl)
+			tt.run(c)
+			for _, key := range tt.present {
+				_, ok := c.Get(key)
+				assert.True(t, ok, key)
+			}
+			for _, key := range tt.missing {
+				_, ok := c.Get(key)
+				assert.False(t, ok, key)
+			}
+		})
+	}
+
+	t.Run("다시 저장한 값으로 갱신된다", func(t *testing.T) {
+		c := NewLocalCache[int](2, time.Minute)
+		c.Set("a", 1)
+		c.Set("a", 2)
+
+		value, ok := c.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 2, value)
+	})
+}
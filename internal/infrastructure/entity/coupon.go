@@ -2,30 +2,87 @@ package entity
 
 import "time"
 
+// CouponEntity Eligibility 는 발급 대상 조건(JSON), SharedCode 는 공용 코드 캠페인의 코드로 없으면 NULL 이다
+// ValidFrom, ValidUntil, ValidSeconds 는 발급된 쿠폰의 사용 기간으로, 지정하지 않으면 NULL 또는 0 이다
+// OpenedAt, SoldOutAt 은 캠페인 시작, 매진 이벤트를 발행한 시각이다
+// IssuanceStrategy 는 캠페인을 만들 때 사용한 발급 방식으로, 발급 방식이 추가되기 전에 만든 캠페인은 redis 이다
+// DegradedAt 은 Redis 장애 중 DB 만으로 발급한 시각으로, 발급 내역이 Redis 에 반영될 때까지 DB 를 기준으로 발급한다
 type CouponEntity struct {
-	ID          string     `gorm:"primary_key;type:varchar(36);not null"`
-	Name        string     `gorm:"type:varchar(20);not null"`
-	IssueAmount int64      `gorm:"type:bigint(20);not null"`
-	IssuedAt    time.Time  `gorm:"type:timestamp;not null"`
-	ExpiresAt   time.Time  `gorm:"type:timestamp;not null"`
-	CreatedAt   time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
-	ModifiedAt  time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
-	DeletedAt   *time.Time `gorm:"type:timestamp"`
+	ID               string     `gorm:"primary_key;type:varchar(36);not null"`
+	Name             string     `gorm:"type:varchar(20);not null"`
+	IssueAmount      int64      `gorm:"type:bigint(20);not null"`
+	IssuedAt         time.Time  `gorm:"type:timestamp;not null"`
+	ExpiresAt        time.Time  `gorm:"type:timestamp;not null"`
+	StockShards      int        `gorm:"type:int;not null;default:1"`
+	IssuanceStrategy string     `gorm:"type:varchar(16);not null;default:'redis'"`
+	Remaining        int64      `gorm:"type:bigint(20);not null;default:0"`
+	Eligibility      *string    `gorm:"type:text"`
+	CodeSource       string     `gorm:"type:varchar(16);not null;default:'generated'"`
+	SharedCode       *string    `gorm:"type:varchar(64);index:uk_shared_code,unique"`
+	PerUserLimit     int        `gorm:"type:int;not null;default:1"`
+	ValidFrom        *time.Time `gorm:"type:timestamp"`
+	ValidUntil       *time.Time `gorm:"type:timestamp"`
+	ValidSeconds     int64      `gorm:"type:bigint(20);not null;default:0"`
+	OpenedAt         *time.Time `gorm:"type:timestamp"`
+	SoldOutAt        *time.Time `gorm:"type:timestamp"`
+	PausedAt         *time.Time `gorm:"type:timestamp"`
+	DegradedAt       *time.Time `gorm:"type:timestamp"`
+	CreatedAt        time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt       time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+	DeletedAt        *time.Time `gorm:"type:timestamp"`
 }
 
 func (CouponEntity) TableName() string {
 	return "coupons"
 }
 
+// IssuedCouponEntity Sequence 는 같은 사용자에게 발급된 순번으로, 공용 코드 캠페인만 0 보다 클 수 있다
+// ValidFrom 이 NULL 이면 사용 기간이 추가되기 전에 발급된 쿠폰으로 발급 시각부터 사용할 수 있다
+// ExpiryNotifiedAt 은 만료 예정 알림을 보낸 시각이다
 type IssuedCouponEntity struct {
-	ID         string     `gorm:"primary_key;type:varchar(36)"`
-	CouponID   string     `gorm:"type:varchar(36);not null;index:idx_coupon_code,unique"`
-	Code       string     `gorm:"type:varchar(10);not null;index:idx_coupon_code,unique"`
-	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
-	ModifiedAt time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
-	DeletedAt  *time.Time `gorm:"type:timestamp"`
+	ID               string     `gorm:"primary_key;type:varchar(36)"`
+	CouponID         string     `gorm:"type:varchar(36);not null;index:idx_coupon_code,unique;index:uk_coupon_user_seq,unique"`
+	UserID           string     `gorm:"type:varchar(64);not null;default:'';index:uk_coupon_user_seq,unique"`
+	Sequence         int        `gorm:"type:int;not null;default:0;index:uk_coupon_user_seq,unique"`
+	Code             string     `gorm:"type:varchar(64);not null;index:idx_coupon_code,unique"`
+	Status           string     `gorm:"type:varchar(16);not null;default:'issued';index:idx_status_valid_until"`
+	RedeemedAt       *time.Time `gorm:"type:timestamp"`
+	ValidFrom        *time.Time `gorm:"type:timestamp"`
+	ValidUntil       *time.Time `gorm:"type:timestamp;index:idx_status_valid_until"`
+	ExpiryNotifiedAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt        time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt       time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+	DeletedAt        *time.Time `gorm:"type:timestamp"`
 }
 
 func (IssuedCouponEntity) TableName() string {
 	return "issued_coupons"
 }
+
+// DegradedIssuanceEntity Redis 장애 중 DB 만으로 발급되어 아직 Redis 의 사용자 집합과 재고 카운터에 반영하지 않은 발급 내역
+type DegradedIssuanceEntity struct {
+	ID             uint64    `gorm:"primary_key;autoIncrement"`
+	CouponID       string    `gorm:"type:varchar(36);not null;index:idx_degraded_coupon"`
+	IssuedCouponID string    `gorm:"type:varchar(36);not null"`
+	UserID         string    `gorm:"type:varchar(64);not null"`
+	Sequence       int       `gorm:"type:int;not null;default:0"`
+	CreatedAt      time.Time `gorm:"type:timestamp;not null;default:current_timestamp"`
+}
+
+func (DegradedIssuanceEntity) TableName() string {
+	return "degraded_issuances"
+}
+
+// CouponCodeEntity 코드 풀 캠페인에 등록한 코드. 발급되면 IssuedCouponID 가 채워진다
+type CouponCodeEntity struct {
+	ID             uint64    `gorm:"primary_key;autoIncrement"`
+	CouponID       string    `gorm:"type:varchar(36);not null;index:uk_coupon_pool_code,unique;index:idx_coupon_available"`
+	Code           string    `gorm:"type:varchar(64);not null;index:uk_coupon_pool_code,unique"`
+	IssuedCouponID *string   `gorm:"type:varchar(36);index:idx_coupon_available"`
+	CreatedAt      time.Time `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt     time.Time `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+}
+
+func (CouponCodeEntity) TableName() string {
+	return "coupon_codes"
+}
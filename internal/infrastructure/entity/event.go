@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// EventOutboxEntity 발행을 기다리는 도메인 이벤트. 발행한 행은 삭제하며, ID 순서대로 발행한다
+type EventOutboxEntity struct {
+	ID         uint64    `gorm:"primary_key;autoIncrement"`
+	EventID    string    `gorm:"type:varchar(36);not null"`
+	EventType  string    `gorm:"type:varchar(64);not null"`
+	Version    int       `gorm:"type:int;not null"`
+	EventKey   string    `gorm:"type:varchar(64);not null"`
+	Payload    string    `gorm:"type:mediumtext;not null"`
+	OccurredAt time.Time `gorm:"type:timestamp(3);not null"`
+	CreatedAt  time.Time `gorm:"type:timestamp;not null;default:current_timestamp"`
+}
+
+func (EventOutboxEntity) TableName() string {
+	return "event_outbox"
+}
@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// WebhookSubscriptionEntity EventTypes 는 쉼표로 구분한 이벤트 종류로, 비어있으면 모든 이벤트를 전달한다
+type WebhookSubscriptionEntity struct {
+	ID         string     `gorm:"primary_key;type:varchar(36);not null"`
+	CouponID   string     `gorm:"type:varchar(36);not null;index:idx_webhook_coupon"`
+	URL        string     `gorm:"type:varchar(512);not null"`
+	Secret     string     `gorm:"type:varchar(128);not null"`
+	EventTypes string     `gorm:"type:varchar(512);not null;default:''"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+	DeletedAt  *time.Time `gorm:"type:timestamp"`
+}
+
+func (WebhookSubscriptionEntity) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDeliveryEntity Status 가 dead 인 행이 재시도를 포기한 dead letter 이다
+// 같은 이벤트를 다시 발행해도 구독마다 한 번만 전달하도록 (subscription_id, event_id) 가 유일하다
+type WebhookDeliveryEntity struct {
+	ID             string     `gorm:"primary_key;type:varchar(36);not null"`
+	SubscriptionID string     `gorm:"type:varchar(36);not null;index:uk_webhook_delivery_event,unique,priority:1"`
+	CouponID       string     `gorm:"type:varchar(36);not null;index:idx_webhook_delivery_coupon"`
+	EventID        string     `gorm:"type:varchar(36);not null;index:uk_webhook_delivery_event,unique,priority:2"`
+	EventType      string     `gorm:"type:varchar(64);not null"`
+	Payload        string     `gorm:"type:mediumtext;not null"`
+	Status         string     `gorm:"type:varchar(16);not null;default:'pending';index:idx_webhook_delivery_due"`
+	Attempts       int        `gorm:"type:int;not null;default:0"`
+	NextAttemptAt  time.Time  `gorm:"type:timestamp(3);not null;index:idx_webhook_delivery_due"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt      time.Time  `gorm:"type:timestamp;not null;default:current_timestamp"`
+	ModifiedAt     time.Time  `gorm:"type:timestamp;not null;default:current_timestamp ON UPDATE current_timestamp"`
+}
+
+func (WebhookDeliveryEntity) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookAttemptEntity 전달 시도마다 남기는 기록. StatusCode 는 응답을 받지 못했으면 0 이다
+type WebhookAttemptEntity struct {
+	ID          uint64    `gorm:"primary_key;autoIncrement"`
+	DeliveryID  string    `gorm:"type:varchar(36);not null;index:idx_webhook_attempt_delivery"`
+	Attempt     int       `gorm:"type:int;not null"`
+	StatusCode  int       `gorm:"type:int;not null;default:0"`
+	Error       string    `gorm:"type:varchar(512);not null;default:''"`
+	DurationMs  int64     `gorm:"type:bigint(20);not null;default:0"`
+	AttemptedAt time.Time `gorm:"type:timestamp(3);not null"`
+}
+
+func (WebhookAttemptEntity) TableName() string {
+	return "webhook_attempts"
+}
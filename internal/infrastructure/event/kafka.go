@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher 이벤트를 Kafka 토픽에 발행한다
+// 레코드의 키는 이벤트의 Key(캠페인 ID)이고 키 해시로 파티션을 고르므로 같은 캠페인의 이벤트는 같은 파티션에 순서대로 쌓인다. 값은 envelope 전체이다
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+type KafkaOption func(*kafka.Writer)
+
+// WithKafkaWriteTimeout 브로커에 레코드를 쓰는 제한 시간을 지정한다
+func WithKafkaWriteTimeout(timeout time.Duration) KafkaOption {
+	return func(w *kafka.Writer) {
+		w.WriteTimeout = timeout
+	}
+}
+
+func NewKafkaPublisher(brokers []string, topic string, opts ...KafkaOption) *KafkaPublisher {
+	writer := &kafka.Writer{
+		Addr:     kafka.TCP(brokers...),
+		Topic:    topic,
+		Balancer: &kafka.Hash{},
+		// 릴레이가 발행에 성공한 이벤트만 outbox 에서 지우므로 모든 복제본에 쓰일 때까지 기다린다
+		RequiredAcks: kafka.RequireAll,
+		// Publish 는 동기로 쓰므로 배치가 찰 때까지 기다리지 않는다
+		BatchTimeout: 10 * time.Millisecond,
+		WriteTimeout: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	return &KafkaPublisher{writer: writer}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	messages := make([]kafka.Message, len(events))
+	for i, e := range events {
+		value, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		messages[i] = kafka.Message{
+			Key:     []byte(e.Key),
+			Value:   value,
+			Headers: []kafka.Header{{Key: "type", Value: []byte(e.Type)}},
+			Time:    e.OccurredAt,
+		}
+	}
+	// 레코드 하나라도 실패하면 전체를 실패로 보고 릴레이가 다시 발행한다
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to publish %d events to kafka: %w", len(events), err)
+	}
+	return nil
+}
+
+// Close 버퍼에 남은 레코드를 보내고 브로커 연결을 닫는다
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,75 @@
+package event_test
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/event"
+	"coupon-service/internal/test"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(eventType domain.EventType) domain.Event {
+	return newCampaignTestEvent("campaign-1", eventType)
+}
+
+func newCampaignTestEvent(campaignID string, eventType domain.EventType) domain.Event {
+	coupon := &domain.Coupon{ID: campaignID, Name: "봄 프로모션", IssueAmount: 10}
+	return domain.NewCampaignEvent(eventType, coupon, time.Now())
+}
+
+func TestKafkaPublisherWithContainer(t *testing.T) {
+	kafkaContainer, ctx := test.SetupKafkaForTest(t)
+	const topic, partitions = "coupon-events", 3
+	conn, err := kafka.DialContext(ctx, "tcp", kafkaContainer.Brokers[0])
+	require.NoError(t, err)
+	require.NoError(t, conn.CreateTopics(kafka.TopicConfig{Topic: topic, NumPartitions: partitions, ReplicationFactor: 1}))
+	require.NoError(t, conn.Close())
+
+	publisher := event.NewKafkaPublisher(kafkaContainer.Brokers, topic)
+	t.Cleanup(func() { _ = publisher.Close() })
+	events := []domain.Event{
+		newCampaignTestEvent("campaign-1", domain.CampaignCreated),
+		newCampaignTestEvent("campaign-2", domain.CampaignCreated),
+		newCampaignTestEvent("campaign-1", domain.CampaignOpened),
+		newCampaignTestEvent("campaign-2", domain.CampaignOpened),
+		newCampaignTestEvent("campaign-1", domain.CampaignSoldOut),
+	}
+
+	require.NoError(t, publisher.Publish(ctx, events...))
+
+	// 파티션별로 레코드를 읽어 캠페인이 쓰인 파티션과 파티션 안의 이벤트 순서를 모은다
+	partitionOf := map[string]int{}
+	published := map[string][]string{}
+	for partition := 0; partition < partitions; partition++ {
+		leader, err := kafka.DialLeader(ctx, "tcp", kafkaContainer.Brokers[0], topic, partition)
+		require.NoError(t, err)
+		last, err := leader.ReadLastOffset()
+		require.NoError(t, err)
+		_, err = leader.Seek(0, kafka.SeekAbsolute)
+		require.NoError(t, err)
+		batch := leader.ReadBatch(1, 1<<20)
+		for offset := int64(0); offset < last; offset++ {
+			message, err := batch.ReadMessage()
+			require.NoError(t, err)
+			var received domain.Event
+			require.NoError(t, json.Unmarshal(message.Value, &received))
+			key := string(message.Key)
+			assert.Equal(t, received.Key, key)
+			if previous, ok := partitionOf[key]; ok {
+				assert.Equal(t, previous, partition, "%s 의 이벤트가 여러 파티션에 쓰였다", key)
+			}
+			partitionOf[key] = partition
+			published[key] = append(published[key], received.ID)
+		}
+		require.NoError(t, batch.Close())
+		require.NoError(t, leader.Close())
+	}
+
+	assert.Equal(t, []string{events[0].ID, events[2].ID, events[4].ID}, published["campaign-1"])
+	assert.Equal(t, []string{events[1].ID, events[3].ID}, published["campaign-2"])
+}
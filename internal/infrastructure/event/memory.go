@@ -0,0 +1,42 @@
+package event
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"sync"
+)
+
+// MemoryPublisher 발행한 이벤트를 메모리에 보관한다. 테스트에서 발행된 이벤트를 확인하는 용도이다
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, events ...domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events 발행된 순서대로 이벤트의 복사본을 반환한다
+func (p *MemoryPublisher) Events() []domain.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.Event(nil), p.events...)
+}
+
+// EventsOf eventType 인 이벤트만 반환한다
+func (p *MemoryPublisher) EventsOf(eventType domain.EventType) []domain.Event {
+	var events []domain.Event
+	for _, e := range p.Events() {
+		if e.Type == eventType {
+			events = append(events, e)
+		}
+	}
+	return events
+}
@@ -0,0 +1,79 @@
+package event
+
+import (
+	"context"
+	"coupon-service/internal/domain"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultStreamMaxLen 스트림에 보관하는 이벤트 수의 기본값. 오래된 이벤트부터 대략적으로 잘라낸다
+const DefaultStreamMaxLen = 100000
+
+// RedisStreamPublisher 이벤트를 Redis Stream 에 XADD 로 추가한다
+// 각 항목은 envelope 의 필드(id, type, version, occurred_at, key, payload)를 그대로 갖는다
+type RedisStreamPublisher struct {
+	client redis.UniversalClient
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamPublisher(client redis.UniversalClient, stream string, maxLen int64) *RedisStreamPublisher {
+	if maxLen <= 0 {
+		maxLen = DefaultStreamMaxLen
+	}
+	return &RedisStreamPublisher{client: client, stream: stream, maxLen: maxLen}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	pipe := p.client.Pipeline()
+	for _, e := range events {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: p.stream,
+			MaxLen: p.maxLen,
+			Approx: true,
+			Values: map[string]interface{}{
+				"id":          e.ID,
+				"type":        string(e.Type),
+				"version":     strconv.Itoa(e.Version),
+				"occurred_at": e.OccurredAt.UTC().Format(time.RFC3339Nano),
+				"key":         e.Key,
+				"payload":     string(e.Payload),
+			},
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to publish %d events to the stream(%s): %w", len(events), p.stream, err)
+	}
+	return nil
+}
+
+// ParseStreamMessage Redis Stream 항목을 이벤트로 변환한다
+func ParseStreamMessage(message redis.XMessage) (domain.Event, error) {
+	value := func(field string) string {
+		v, _ := message.Values[field].(string)
+		return v
+	}
+	version, err := strconv.Atoi(value("version"))
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("invalid event version of the message(%s): %w", message.ID, err)
+	}
+	occurredAt, err := time.Parse(time.RFC3339Nano, value("occurred_at"))
+	if err != nil {
+		return domain.Event{}, fmt.Errorf("invalid event time of the message(%s): %w", message.ID, err)
+	}
+	return domain.Event{
+		ID:         value("id"),
+		Type:       domain.EventType(value("type")),
+		Version:    version,
+		OccurredAt: occurredAt,
+		Key:        value("key"),
+		Payload:    []byte(value("payload")),
+	}, nil
+}
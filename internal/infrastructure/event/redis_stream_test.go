@@ -0,0 +1,29 @@
+package event_test
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/event"
+	"coupon-service/internal/test"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStreamPublisherWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	publisher := event.NewRedisStreamPublisher(redisContainer.Client, "coupon:events", 0)
+	created := newTestEvent(domain.CampaignCreated)
+
+	require.NoError(t, publisher.Publish(ctx, created, newTestEvent(domain.CampaignSoldOut)))
+
+	messages, err := redisContainer.Client.XRange(ctx, "coupon:events", "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	parsed, err := event.ParseStreamMessage(messages[0])
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, parsed.ID)
+	assert.Equal(t, domain.EventVersion, parsed.Version)
+	assert.True(t, created.OccurredAt.Equal(parsed.OccurredAt))
+	assert.JSONEq(t, string(created.Payload), string(parsed.Payload))
+}
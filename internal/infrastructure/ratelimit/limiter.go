@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit period 동안 Rate 개의 요청을 허용한다. 요청이 없던 동안 쌓인 여유분도 Rate 개를 넘지 않는다
+type Limit struct {
+	Rate   int
+	Period time.Duration
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Rate, l.Period)
+}
+
+// Result 허용되지 않은 요청이면 RetryAfter 이후에 다시 시도할 수 있다
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcraScript GCRA(Generic Cell Rate Algorithm) 로 구현한 token bucket
+// 키에는 다음 요청이 도착할 것으로 예상되는 시각(TAT)만 저장한다. 여러 인스턴스의 시계 차이를 피하기 위해 Redis 서버 시간을 사용한다
+//   - ARGV[1]: 토큰 하나가 채워지는 간격(마이크로초)
+//   - ARGV[2]: 버킷 크기
+var gcraScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local next_tat = tat + interval
+local allow_at = next_tat - burst * interval
+if allow_at > now then
+	return {0, allow_at - now}
+end
+redis.call('SET', KEYS[1], next_tat, 'PX', math.ceil((next_tat - now) / 1000))
+return {1, 0}
+`)
+
+type redisLimiter struct {
+	client redis.UniversalClient
+}
+
+func NewRedisLimiter(client redis.UniversalClient) Limiter {
+	return &redisLimiter{client: client}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Rate <= 0 || limit.Period <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	interval := limit.Period.Microseconds() / int64(limit.Rate)
+	if interval < 1 {
+		interval = 1
+	}
+
+	values, err := gcraScript.Run(ctx, l.client, []string{genRateLimitKey(key)}, interval, limit.Rate).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		RetryAfter: time.Duration(values[1]) * time.Microsecond,
+	}, nil
+}
+
+func genRateLimitKey(key string) string {
+	return fmt.Sprintf("ratelimit:{%s}", key)
+}
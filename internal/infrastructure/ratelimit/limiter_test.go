@@ -0,0 +1,38 @@
+package ratelimit_test
+
+import (
+	"coupon-service/internal/infrastructure/ratelimit"
+	"coupon-service/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestRedisLimiterWithContainer(t *testing.T) {
+	redisContainer, ctx := test.SetupRedisForTest(t)
+	limiter := ratelimit.NewRedisLimiter(redisContainer.Client)
+	limit := ratelimit.Limit{Rate: 3, Period: time.Minute}
+
+	t.Run("한도까지 허용되고 이후 요청은 재시도 시간과 함께 거부되어야 한다", func(t *testing.T) {
+		for i := 0; i < limit.Rate; i++ {
+			result, err := limiter.Allow(ctx, "user-1", limit)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+
+		result, err := limiter.Allow(ctx, "user-1", limit)
+
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Greater(t, result.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, result.RetryAfter, limit.Period/time.Duration(limit.Rate))
+	})
+
+	t.Run("키가 다르면 한도가 따로 적용되어야 한다", func(t *testing.T) {
+		result, err := limiter.Allow(ctx, "user-2", limit)
+
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dimension 요청 한도를 나누어 적용하는 기준
+type Dimension string
+
+const (
+	ByRPC      Dimension = "rpc"
+	ByUser     Dimension = "user"
+	ByIP       Dimension = "ip"
+	ByCampaign Dimension = "campaign"
+)
+
+// AnyProcedure 모든 RPC 에 적용하는 규칙의 Procedure
+const AnyProcedure = "*"
+
+// Rule Procedure 에 해당하는 RPC 를 Dimension 별로 Limit 만큼 허용한다
+// Procedure 는 RPC 이름(IssueCoupon) 또는 전체 경로(/io.coupon.service.GreetService/IssueCoupon) 이다
+type Rule struct {
+	Procedure string
+	Dimension Dimension
+	Limit     Limit
+}
+
+// Matches 요청의 procedure 가 규칙에 해당하는지 확인한다
+func (r Rule) Matches(procedure string) bool {
+	if r.Procedure == AnyProcedure || r.Procedure == procedure {
+		return true
+	}
+	return strings.HasSuffix(procedure, "/"+r.Procedure)
+}
+
+// ParseRules 쉼표로 구분된 "<procedure>:<dimension>=<rate>/<period>" 목록을 규칙으로 변환한다
+// 예: "IssueCoupon:user=5/1s,IssueCoupon:ip=20/1s,*:rpc=5000/1s"
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		rule, err := parseRule(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit rule(%s): %w", item, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRule(item string) (Rule, error) {
+	target, limitSpec, ok := strings.Cut(item, "=")
+	if !ok {
+		return Rule{}, fmt.Errorf("missing '='")
+	}
+	procedure, dimension, ok := strings.Cut(target, ":")
+	if !ok || procedure == "" {
+		return Rule{}, fmt.Errorf("missing procedure or dimension")
+	}
+	switch Dimension(dimension) {
+	case ByRPC, ByUser, ByIP, ByCampaign:
+	default:
+		return Rule{}, fmt.Errorf("unknown dimension %q", dimension)
+	}
+
+	rateSpec, periodSpec, ok := strings.Cut(limitSpec, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("missing '/'")
+	}
+	rate, err := strconv.Atoi(rateSpec)
+	if err != nil || rate <= 0 {
+		return Rule{}, fmt.Errorf("rate must be a positive integer")
+	}
+	period, err := time.ParseDuration(periodSpec)
+	if err != nil || period <= 0 {
+		return Rule{}, fmt.Errorf("period must be a positive duration")
+	}
+
+	return Rule{
+		Procedure: procedure,
+		Dimension: Dimension(dimension),
+		Limit:     Limit{Rate: rate, Period: period},
+	}, nil
+}
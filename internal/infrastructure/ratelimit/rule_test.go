@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Run("규칙 목록이 변환되어야 한다", func(t *testing.T) {
+		rules, err := ParseRules("IssueCoupon:user=5/1s, *:rpc=5000/1m")
+
+		require.NoError(t, err)
+		assert.Equal(t, []Rule{
+			{Procedure: "IssueCoupon", Dimension: ByUser, Limit: Limit{Rate: 5, Period: time.Second}},
+			{Procedure: AnyProcedure, Dimension: ByRPC, Limit: Limit{Rate: 5000, Period: time.Minute}},
+		}, rules)
+	})
+
+	t.Run("빈 값이면 규칙이 없어야 한다", func(t *testing.T) {
+		rules, err := ParseRules("")
+
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+
+	for _, spec := range []string{"IssueCoupon=5/1s", "IssueCoupon:device=5/1s", "IssueCoupon:user=0/1s", "IssueCoupon:user=5/s"} {
+		t.Run("올바르지 않은 규칙은 에러가 반환되어야 한다: "+spec, func(t *testing.T) {
+			_, err := ParseRules(spec)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	rule := Rule{Procedure: "IssueCoupon"}
+
+	assert.True(t, rule.Matches("/io.coupon.service.GreetService/IssueCoupon"))
+	assert.False(t, rule.Matches("/io.coupon.service.GreetService/GetCampaign"))
+	assert.True(t, Rule{Procedure: AnyProcedure}.Matches("/io.coupon.service.GreetService/GetCampaign"))
+}
@@ -3,11 +3,25 @@ package repository
 import (
 	"coupon-service/internal/domain"
 	"coupon-service/internal/infrastructure/entity"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
+var (
+	ErrCouponNotFound = errors.New("coupon not found")
+	// ErrSharedCodeExists 다른 캠페인이 같은 공용 코드를 사용하고 있다
+	ErrSharedCodeExists = errors.New("shared code already exists")
+)
+
+const sharedCodeIndexName = "uk_shared_code"
+
+// codeImportBatchSize 코드 풀 등록 시 한 번의 INSERT 로 저장하는 코드 수
+const codeImportBatchSize = 500
+
 type CouponRepository struct {
 	db *gorm.DB
 }
@@ -18,17 +32,41 @@ func NewCouponRepository(db *gorm.DB) *CouponRepository {
 	}
 }
 
+// Save 캠페인 생성 시에만 사용한다. 남은 재고(remaining)를 발급 수량으로 초기화한다
 func (r *CouponRepository) Save(domain *domain.Coupon) error {
-	return r.db.Save(&entity.CouponEntity{
-		ID:          domain.ID,
-		Name:        domain.Name,
-		IssueAmount: domain.IssueAmount,
-		IssuedAt:    domain.IssuedAt,
-		ExpiresAt:   domain.ExpiresAt,
-		CreatedAt:   domain.CreatedAt,
-		ModifiedAt:  domain.ModifiedAt,
-		DeletedAt:   nil,
+	eligibility, err := marshalEligibility(domain.Eligibility)
+	if err != nil {
+		return err
+	}
+	var sharedCode *string
+	if domain.SharedCode != "" {
+		sharedCode = &domain.SharedCode
+	}
+	err = r.db.Save(&entity.CouponEntity{
+		ID:               domain.ID,
+		Name:             domain.Name,
+		IssueAmount:      domain.IssueAmount,
+		IssuedAt:         domain.IssuedAt,
+		ExpiresAt:        domain.ExpiresAt,
+		StockShards:      domain.StockShards,
+		IssuanceStrategy: domain.IssuanceStrategy,
+		Remaining:        domain.IssueAmount,
+		Eligibility:      eligibility,
+		CodeSource:       string(domain.CodeSource),
+		SharedCode:       sharedCode,
+		PerUserLimit:     domain.UserLimit(),
+		ValidFrom:        domain.Validity.From,
+		ValidUntil:       domain.Validity.Until,
+		ValidSeconds:     int64(domain.Validity.Duration / time.Second),
+		PausedAt:         domain.PausedAt,
+		CreatedAt:        domain.CreatedAt,
+		ModifiedAt:       domain.ModifiedAt,
+		DeletedAt:        nil,
 	}).Error
+	if isDuplicateEntry(err, sharedCodeIndexName) {
+		return ErrSharedCodeExists
+	}
+	return err
 }
 
 func (r *CouponRepository) Delete(id string) error {
@@ -45,20 +83,244 @@ func (r *CouponRepository) FindOne(id string) (*domain.Coupon, error) {
 		"id = ? AND deleted_at IS NULL", id,
 	).First(&couponEntity).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, errors.New("coupon not found")
+		return nil, ErrCouponNotFound
 	}
 	if err != nil {
 		fmt.Println(err)
 		return nil, errors.New(fmt.Sprintf("occurred an error when find a coupon by id(%s)", id))
 	}
 
+	return toCouponDomain(couponEntity)
+}
+
+// MarkSoldOut 캠페인을 매진 처리한 시각을 기록한다. 이미 기록되어 있으면 false 를 반환한다
+func (r *CouponRepository) MarkSoldOut(id string, soldOutAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.CouponEntity{}).Where(
+		"id = ? AND sold_out_at IS NULL AND deleted_at IS NULL", id,
+	).UpdateColumn("sold_out_at", soldOutAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// SetPaused 캠페인의 발급 중지 시각을 기록하고, pausedAt 이 nil 이면 지운다
+// 이미 같은 상태이면 변경하지 않고 false 를 반환하며, 캠페인이 없으면 ErrCouponNotFound 를 반환한다
+func (r *CouponRepository) SetPaused(id string, pausedAt *time.Time) (bool, error) {
+	query := r.db.Model(&entity.CouponEntity{}).Where("id = ? AND deleted_at IS NULL", id)
+	if pausedAt != nil {
+		query = query.Where("paused_at IS NULL")
+	} else {
+		query = query.Where("paused_at IS NOT NULL")
+	}
+	result := query.UpdateColumn("paused_at", pausedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+	var count int64
+	err := r.db.Model(&entity.CouponEntity{}).Where("id = ? AND deleted_at IS NULL", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, ErrCouponNotFound
+	}
+	return false, nil
+}
+
+// OpenBatch 발급 시작 시각이 지났고 아직 종료되지 않은 캠페인 중 시작 처리하지 않은 캠페인을 최대 limit 개 시작 처리한다
+// events 가 만든 이벤트는 시작 처리와 같은 트랜잭션에서 발행 대기열에 저장한다
+func (r *CouponRepository) OpenBatch(now time.Time, limit int, events func([]domain.Coupon) []domain.Event) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var couponEntities []entity.CouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where(
+			"issued_at <= ? AND expires_at > ? AND opened_at IS NULL AND deleted_at IS NULL", now, now,
+		).Order("issued_at").Limit(limit).Find(&couponEntities).Error
+		if err != nil || len(couponEntities) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(couponEntities))
+		coupons := make([]domain.Coupon, len(couponEntities))
+		for i, v := range couponEntities {
+			coupon, err2 := toCouponDomain(v)
+			if err2 != nil {
+				return err2
+			}
+			ids[i] = v.ID
+			coupons[i] = *coupon
+		}
+		err = tx.Model(&entity.CouponEntity{}).Where("id IN ?", ids).UpdateColumn("opened_at", now).Error
+		if err != nil {
+			return err
+		}
+		if err = enqueueEvents(tx, events(coupons)); err != nil {
+			return err
+		}
+		count = len(coupons)
+		return nil
+	})
+	return count, err
+}
+
+func toCouponDomain(couponEntity entity.CouponEntity) (*domain.Coupon, error) {
+	eligibility, err := unmarshalEligibility(couponEntity.Eligibility)
+	if err != nil {
+		return nil, fmt.Errorf("invalid eligibility rules of a coupon(%s): %w", couponEntity.ID, err)
+	}
+
+	var sharedCode string
+	if couponEntity.SharedCode != nil {
+		sharedCode = *couponEntity.SharedCode
+	}
+
 	return &domain.Coupon{
-		ID:          couponEntity.ID,
-		Name:        couponEntity.Name,
-		IssueAmount: couponEntity.IssueAmount,
-		IssuedAt:    couponEntity.IssuedAt,
-		ExpiresAt:   couponEntity.ExpiresAt,
-		CreatedAt:   couponEntity.CreatedAt,
-		ModifiedAt:  couponEntity.ModifiedAt,
+		ID:               couponEntity.ID,
+		Name:             couponEntity.Name,
+		IssueAmount:      couponEntity.IssueAmount,
+		IssuedAt:         couponEntity.IssuedAt,
+		ExpiresAt:        couponEntity.ExpiresAt,
+		StockShards:      couponEntity.StockShards,
+		IssuanceStrategy: couponEntity.IssuanceStrategy,
+		Eligibility:      eligibility,
+		CodeSource:       domain.CodeSource(couponEntity.CodeSource),
+		SharedCode:       sharedCode,
+		PerUserLimit:     couponEntity.PerUserLimit,
+		Validity: domain.UsageValidity{
+			From:     couponEntity.ValidFrom,
+			Until:    couponEntity.ValidUntil,
+			Duration: time.Duration(couponEntity.ValidSeconds) * time.Second,
+		},
+		PausedAt:   couponEntity.PausedAt,
+		CreatedAt:  couponEntity.CreatedAt,
+		ModifiedAt: couponEntity.ModifiedAt,
 	}, nil
 }
+
+// FindIDBySharedCode 공용 코드를 사용하는 캠페인의 ID
+func (r *CouponRepository) FindIDBySharedCode(code string) (string, error) {
+	var couponEntity entity.CouponEntity
+	err := r.db.Select("id").Where(
+		"shared_code = ? AND deleted_at IS NULL", code,
+	).First(&couponEntity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrCouponNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("occurred an error when find a coupon by shared code(%s): %w", code, err)
+	}
+	return couponEntity.ID, nil
+}
+
+func (r *CouponRepository) FindRemaining(id string) (int64, error) {
+	var couponEntity entity.CouponEntity
+	err := r.db.Select("remaining").Where(
+		"id = ? AND deleted_at IS NULL", id,
+	).First(&couponEntity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrCouponNotFound
+	}
+	if err != nil {
+		fmt.Println(err)
+		return 0, errors.New(fmt.Sprintf("occurred an error when find remaining stock by id(%s)", id))
+	}
+	return couponEntity.Remaining, nil
+}
+
+// ImportCodes 코드 풀에 코드를 등록하고 새로 등록된 코드 목록을 반환한다. 이미 등록된 코드는 무시한다
+// 등록한 코드 수만큼 발급 수량과 남은 재고를 늘리며, 같은 캠페인의 등록은 캠페인 row 잠금으로 직렬화한다
+func (r *CouponRepository) ImportCodes(couponId string, codes []string) ([]string, error) {
+	var imported []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var couponEntity entity.CouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(
+			"id = ? AND deleted_at IS NULL", couponId,
+		).First(&couponEntity).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCouponNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var existing []string
+		err = tx.Model(&entity.CouponCodeEntity{}).Where(
+			"coupon_id = ? AND code IN ?", couponId, codes,
+		).Pluck("code", &existing).Error
+		if err != nil {
+			return err
+		}
+		registered := make(map[string]struct{}, len(existing))
+		for _, code := range existing {
+			registered[code] = struct{}{}
+		}
+
+		var codeEntities []entity.CouponCodeEntity
+		for _, code := range codes {
+			if _, ok := registered[code]; ok {
+				continue
+			}
+			codeEntities = append(codeEntities, entity.CouponCodeEntity{CouponID: couponId, Code: code})
+			imported = append(imported, code)
+		}
+		if len(codeEntities) == 0 {
+			return nil
+		}
+		if err = tx.CreateInBatches(codeEntities, codeImportBatchSize).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.CouponEntity{}).Where("id = ?", couponId).Updates(map[string]interface{}{
+			"issue_amount": gorm.Expr("issue_amount + ?", len(codeEntities)),
+			"remaining":    gorm.Expr("remaining + ?", len(codeEntities)),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// RemoveCodes 발급되지 않은 코드를 코드 풀에서 제거하고 발급 수량과 남은 재고를 줄인다
+func (r *CouponRepository) RemoveCodes(couponId string, codes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where(
+			"coupon_id = ? AND code IN ? AND issued_coupon_id IS NULL", couponId, codes,
+		).Delete(&entity.CouponCodeEntity{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&entity.CouponEntity{}).Where("id = ?", couponId).Updates(map[string]interface{}{
+			"issue_amount": gorm.Expr("issue_amount - ?", result.RowsAffected),
+			"remaining":    gorm.Expr("remaining - ?", result.RowsAffected),
+		}).Error
+	})
+}
+
+// marshalEligibility 조건이 없는 캠페인은 NULL 로 저장한다
+func marshalEligibility(rules domain.EligibilityRules) (*string, error) {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "{}" {
+		return nil, nil
+	}
+	value := string(data)
+	return &value, nil
+}
+
+func unmarshalEligibility(value *string) (domain.EligibilityRules, error) {
+	var rules domain.EligibilityRules
+	if value == nil || *value == "" {
+		return rules, nil
+	}
+	err := json.Unmarshal([]byte(*value), &rules)
+	return rules, err
+}
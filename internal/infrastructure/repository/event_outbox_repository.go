@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"encoding/json"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewEventOutboxRepository(db *gorm.DB) *EventOutboxRepository {
+	return &EventOutboxRepository{
+		db: db,
+	}
+}
+
+// Enqueue 이벤트를 발행 대기열에 저장한다
+func (r *EventOutboxRepository) Enqueue(events []domain.Event) error {
+	return enqueueEvents(r.db, events)
+}
+
+// enqueueEvents 다른 저장소의 트랜잭션에서도 변경 내용과 이벤트를 함께 저장할 수 있도록 tx 로 저장한다
+func enqueueEvents(tx *gorm.DB, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	eventEntities := make([]entity.EventOutboxEntity, len(events))
+	for i, event := range events {
+		eventEntities[i] = entity.EventOutboxEntity{
+			EventID:    event.ID,
+			EventType:  string(event.Type),
+			Version:    event.Version,
+			EventKey:   event.Key,
+			Payload:    string(event.Payload),
+			OccurredAt: event.OccurredAt,
+		}
+	}
+	return tx.CreateInBatches(eventEntities, 100).Error
+}
+
+// PublishBatch 먼저 저장한 이벤트부터 최대 limit 개를 잠근 상태에서 publish 로 발행하고, 발행에 성공하면 삭제한다
+// publish 가 실패하면 삭제하지 않으므로 다음 실행에서 같은 이벤트를 다시 발행한다
+func (r *EventOutboxRepository) PublishBatch(limit int, publish func(events []domain.Event) error) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var eventEntities []entity.EventOutboxEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Limit(limit).Find(&eventEntities).Error
+		if err != nil || len(eventEntities) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(eventEntities))
+		events := make([]domain.Event, len(eventEntities))
+		for i, v := range eventEntities {
+			ids[i] = v.ID
+			events[i] = toEventDomain(v)
+		}
+		if err = publish(events); err != nil {
+			return err
+		}
+		if err = tx.Delete(&entity.EventOutboxEntity{}, ids).Error; err != nil {
+			return err
+		}
+		count = len(eventEntities)
+		return nil
+	})
+	return count, err
+}
+
+func toEventDomain(v entity.EventOutboxEntity) domain.Event {
+	return domain.Event{
+		ID:         v.EventID,
+		Type:       domain.EventType(v.EventType),
+		Version:    v.Version,
+		OccurredAt: v.OccurredAt,
+		Key:        v.EventKey,
+		Payload:    json.RawMessage(v.Payload),
+	}
+}
@@ -1,10 +1,37 @@
 package repository
 
 import (
+	"context"
 	"coupon-service/internal/domain"
 	"coupon-service/internal/infrastructure/entity"
+	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+const (
+	mysqlDuplicateEntry = 1062
+	couponUserIndexName = "uk_coupon_user_seq"
+)
+
+var (
+	ErrCouponSoldOut        = errors.New("all coupons has been issued")
+	ErrDuplicatedCouponUser = errors.New("coupon already issued to this user")
+	// ErrPoolCodeUnavailable 지정한 코드가 코드 풀에 없거나 이미 발급되었다
+	ErrPoolCodeUnavailable = errors.New("pool code is not available")
+	// ErrIssuedCouponNotFound 사용자에게 발급된 쿠폰 중 코드가 일치하는 쿠폰이 없다
+	ErrIssuedCouponNotFound  = errors.New("issued coupon not found")
+	ErrCouponAlreadyRedeemed = errors.New("coupon already redeemed")
+	ErrCouponNotUsable       = errors.New("coupon is outside its validity window")
+	// ErrIssuedCouponNotRevocable 사용했거나 만료, 취소된 쿠폰은 취소할 수 없다
+	ErrIssuedCouponNotRevocable = errors.New("issued coupon is not revocable")
+	// ErrCouponDegraded Redis 에 반영하지 않은 DB 발급 내역이 있어 캠페인을 DB 기준으로 발급해야 한다
+	ErrCouponDegraded = errors.New("coupon is issued from the database until degraded issuances are synced")
 )
 
 type IssuedCouponRepository struct {
@@ -18,14 +45,443 @@ func NewIssuedCouponRepository(db *gorm.DB) *IssuedCouponRepository {
 }
 
 func (r *IssuedCouponRepository) Save(domain *domain.IssuedCoupon) error {
-	return r.db.Save(&entity.IssuedCouponEntity{
-		ID:         domain.ID,
-		CouponID:   domain.CouponID,
-		Code:       domain.Code,
-		CreatedAt:  domain.CreatedAt,
-		ModifiedAt: domain.ModifiedAt,
-		DeletedAt:  nil,
-	}).Error
+	return r.db.Save(toIssuedCouponEntity(domain)).Error
+}
+
+// SaveUnlessDegraded Redis 에서 재고를 차감한 발급 내역을 저장한다
+// 캠페인 row 에 공유 잠금을 잡아 DB 만으로 발급하는 트랜잭션과 순서를 정하며, Redis 에 반영하지 않은 DB 발급 내역이 있으면
+// Redis 의 재고 카운터를 믿을 수 없으므로 저장하지 않고 ErrCouponDegraded 를 반환한다
+func (r *IssuedCouponRepository) SaveUnlessDegraded(domain *domain.IssuedCoupon) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var couponEntity entity.CouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id", "degraded_at").Where(
+			"id = ? AND deleted_at IS NULL", domain.CouponID,
+		).First(&couponEntity).Error
+		// DB 에 없는 캠페인은 DB 로 발급될 수 없으므로 Redis 의 재고 카운터를 따른다
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if couponEntity.DegradedAt != nil {
+			return ErrCouponDegraded
+		}
+		return tx.Create(toIssuedCouponEntity(domain)).Error
+	})
+}
+
+// SaveDegraded Redis 를 사용할 수 없을 때 캠페인 row 에 SELECT ... FOR UPDATE 잠금을 잡은 상태에서
+// 발행량과 사용자 중복 여부를 DB 기준으로 확인한 후 저장한다
+// userLimit 은 한 사용자에게 발급할 수 있는 횟수이며, 발급된 횟수를 발급 순번으로 사용한다
+// 발급 내역은 Redis 에 반영할 때까지 degraded_issuances 에 남기고, 그동안 캠페인은 DB 기준으로 발급한다
+// 저장한 후 남은 발행량을 반환한다
+func (r *IssuedCouponRepository) SaveDegraded(domain *domain.IssuedCoupon, userLimit int) (int64, error) {
+	var remaining int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var couponEntity entity.CouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(
+			"id = ? AND deleted_at IS NULL", domain.CouponID,
+		).First(&couponEntity).Error
+		if err != nil {
+			return err
+		}
+
+		var issuedCount int64
+		err = tx.Model(&entity.IssuedCouponEntity{}).Where(
+			"coupon_id = ? AND deleted_at IS NULL", domain.CouponID,
+		).Count(&issuedCount).Error
+		if err != nil {
+			return err
+		}
+		if issuedCount >= couponEntity.IssueAmount {
+			return ErrCouponSoldOut
+		}
+
+		var userCount int64
+		err = tx.Model(&entity.IssuedCouponEntity{}).Where(
+			"coupon_id = ? AND user_id = ? AND deleted_at IS NULL", domain.CouponID, domain.UserID,
+		).Count(&userCount).Error
+		if err != nil {
+			return err
+		}
+		if userCount >= int64(userLimit) {
+			return ErrDuplicatedCouponUser
+		}
+		domain.Sequence = int(userCount)
+
+		if err = tx.Create(toIssuedCouponEntity(domain)).Error; err != nil {
+			return err
+		}
+		if couponEntity.DegradedAt == nil {
+			err = tx.Model(&couponEntity).UpdateColumn("degraded_at", time.Now()).Error
+			if err != nil {
+				return err
+			}
+		}
+		remaining = couponEntity.IssueAmount - issuedCount - 1
+		return tx.Create(toDegradedIssuanceEntity(domain)).Error
+	})
+	return remaining, err
+}
+
+// SaveDegradedWithPoolCode Redis 를 사용할 수 없을 때 코드 풀에서 발급하고, 사용자 집합에 반영할 발급 내역을 남긴다
+// 코드 풀은 DB 의 코드로 발행량이 보장되므로 캠페인을 DB 기준 발급으로 전환하지 않는다
+func (r *IssuedCouponRepository) SaveDegradedWithPoolCode(domain *domain.IssuedCoupon) (int64, error) {
+	var remaining int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if remaining, err = saveWithPoolCode(tx, domain); err != nil {
+			return err
+		}
+		return tx.Create(toDegradedIssuanceEntity(domain)).Error
+	})
+	return remaining, err
+}
+
+// SyncDegradedIssuances Redis 에 반영하지 않은 DB 발급 내역이 있는 캠페인을 최대 limit 개 골라 apply 로 반영한다
+// 캠페인 row 를 잠근 상태에서 반영하므로 그동안 같은 캠페인의 발급은 기다린다
+// 모든 내역을 반영한 캠페인만 DB 기준 발급을 해제하며, apply 가 실패하면 반영한 내역까지만 지우고 나머지는 다음 실행에서 다시 반영한다
+func (r *IssuedCouponRepository) SyncDegradedIssuances(
+	limit int,
+	apply func(issued domain.IssuedCoupon) error,
+) (int, error) {
+	var couponIds []string
+	err := r.db.Model(&entity.DegradedIssuanceEntity{}).Distinct("coupon_id").
+		Limit(limit).Pluck("coupon_id", &couponIds).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	var errs []error
+	for _, couponId := range couponIds {
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			var couponEntity entity.CouponEntity
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where(
+				"id = ?", couponId,
+			).First(&couponEntity).Error
+			if err != nil {
+				return err
+			}
+
+			var pending []entity.DegradedIssuanceEntity
+			err = tx.Where("coupon_id = ?", couponId).Order("id").Find(&pending).Error
+			if err != nil {
+				return err
+			}
+			applied := make([]uint64, 0, len(pending))
+			var applyErr error
+			for _, v := range pending {
+				if applyErr = apply(toDegradedIssuanceDomain(v)); applyErr != nil {
+					break
+				}
+				applied = append(applied, v.ID)
+			}
+			if len(applied) > 0 {
+				if err = tx.Delete(&entity.DegradedIssuanceEntity{}, applied).Error; err != nil {
+					return err
+				}
+				count += len(applied)
+			}
+			if applyErr != nil {
+				// 반영한 내역을 지운 것은 커밋해야 다음 실행에서 재고를 두 번 차감하지 않는다
+				errs = append(errs, applyErr)
+				return nil
+			}
+			return tx.Model(&couponEntity).UpdateColumn("degraded_at", nil).Error
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return count, errors.Join(errs...)
+}
+
+// SaveWithStockDecrement 발급 내역 저장과 coupons.remaining 의 조건부 차감을 하나의 트랜잭션으로 처리한다
+// 사용자 중복은 (coupon_id, user_id, sequence) 유니크 제약으로 판단한다
+// userLimit 이 1 보다 크면 사용자에게 발급된 횟수를 발급 순번으로 사용하고, 같은 사용자의 동시 요청이 같은 순번을 먼저 저장하면
+// 발급 한도 안에서 다음 순번으로 다시 저장한다
+// 차감한 후 남은 재고를 반환한다
+func (r *IssuedCouponRepository) SaveWithStockDecrement(domain *domain.IssuedCoupon, userLimit int) (int64, error) {
+	var remaining int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if userLimit > 1 {
+			var userCount int64
+			err := tx.Model(&entity.IssuedCouponEntity{}).Where(
+				"coupon_id = ? AND user_id = ? AND deleted_at IS NULL", domain.CouponID, domain.UserID,
+			).Count(&userCount).Error
+			if err != nil {
+				return err
+			}
+			if userCount >= int64(userLimit) {
+				return ErrDuplicatedCouponUser
+			}
+			domain.Sequence = int(userCount)
+		}
+
+		// 중복 요청은 캠페인 row 잠금을 잡기 전에 실패하도록 먼저 저장한다
+		// 유니크 제약 위반은 해당 INSERT 만 실패하므로 같은 트랜잭션에서 다음 순번으로 다시 저장할 수 있다
+		for {
+			err := tx.Create(toIssuedCouponEntity(domain)).Error
+			if err == nil {
+				break
+			}
+			if !isDuplicatedCouponUser(err) {
+				return err
+			}
+			if domain.Sequence+1 >= userLimit {
+				return ErrDuplicatedCouponUser
+			}
+			domain.Sequence++
+		}
+
+		var err error
+		remaining, err = decrementRemaining(tx, domain.CouponID)
+		return err
+	})
+	return remaining, err
+}
+
+// SaveWithPoolCode 코드 풀에서 발급되지 않은 코드 하나를 발급 처리하고 발급 내역을 저장한다
+// domain.Code 가 비어있으면 다른 트랜잭션이 잠그지 않은 코드 중 가장 먼저 등록된 코드를 골라 채운다
+// 발급한 후 남은 코드 수를 반환한다
+func (r *IssuedCouponRepository) SaveWithPoolCode(domain *domain.IssuedCoupon) (int64, error) {
+	var remaining int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		remaining, err = saveWithPoolCode(tx, domain)
+		return err
+	})
+	return remaining, err
+}
+
+func saveWithPoolCode(tx *gorm.DB, domain *domain.IssuedCoupon) (int64, error) {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where(
+		"coupon_id = ? AND issued_coupon_id IS NULL", domain.CouponID,
+	)
+	if domain.Code != "" {
+		query = query.Where("code = ?", domain.Code)
+	}
+	var codeEntity entity.CouponCodeEntity
+	err := query.Order("id").First(&codeEntity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if domain.Code != "" {
+			return 0, ErrPoolCodeUnavailable
+		}
+		return 0, ErrCouponSoldOut
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	domain.Code = codeEntity.Code
+	if err = tx.Create(toIssuedCouponEntity(domain)).Error; err != nil {
+		if isDuplicatedCouponUser(err) {
+			return 0, ErrDuplicatedCouponUser
+		}
+		return 0, err
+	}
+	err = tx.Model(&codeEntity).UpdateColumn("issued_coupon_id", domain.ID).Error
+	if err != nil {
+		return 0, err
+	}
+	return decrementRemaining(tx, domain.CouponID)
+}
+
+// decrementRemaining 남은 재고가 있으면 1 차감하고 차감한 후의 값을 반환한다
+// 캠페인 row 를 갱신한 트랜잭션 안에서 조회하므로 다른 트랜잭션의 차감이 섞이지 않는다
+func decrementRemaining(tx *gorm.DB, couponId string) (int64, error) {
+	result := tx.Model(&entity.CouponEntity{}).Where(
+		"id = ? AND remaining > 0 AND deleted_at IS NULL", couponId,
+	).UpdateColumn("remaining", gorm.Expr("remaining - 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, ErrCouponSoldOut
+	}
+	var remaining int64
+	err := tx.Model(&entity.CouponEntity{}).Select("remaining").Where("id = ?", couponId).Scan(&remaining).Error
+	return remaining, err
+}
+
+// Redeem 사용자에게 발급된 쿠폰을 사용 처리한다
+func (r *IssuedCouponRepository) Redeem(
+	couponId string,
+	code string,
+	userId string,
+	redeemedAt time.Time,
+) (*domain.IssuedCoupon, error) {
+	var issuedCoupon *domain.IssuedCoupon
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var issuedCouponEntity entity.IssuedCouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(
+			"coupon_id = ? AND code = ? AND user_id = ? AND deleted_at IS NULL", couponId, code, userId,
+		).First(&issuedCouponEntity).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrIssuedCouponNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if issuedCouponEntity.Status == string(domain.IssuedCouponExpired) ||
+			issuedCouponEntity.Status == string(domain.IssuedCouponRevoked) {
+			return ErrCouponNotUsable
+		}
+		if issuedCouponEntity.Status != string(domain.IssuedCouponIssued) {
+			return ErrCouponAlreadyRedeemed
+		}
+		if !toIssuedCouponDomain(issuedCouponEntity).UsableAt(redeemedAt) {
+			return ErrCouponNotUsable
+		}
+
+		err = tx.Model(&issuedCouponEntity).Updates(map[string]interface{}{
+			"status":      string(domain.IssuedCouponRedeemed),
+			"redeemed_at": redeemedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		issuedCouponEntity.Status = string(domain.IssuedCouponRedeemed)
+		issuedCouponEntity.RedeemedAt = &redeemedAt
+		issuedCoupon = toIssuedCouponDomain(issuedCouponEntity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issuedCoupon, nil
+}
+
+// Revoke 사용하지 않은 발급 쿠폰을 취소 처리한다
+func (r *IssuedCouponRepository) Revoke(id string) (*domain.IssuedCoupon, error) {
+	var issuedCoupon *domain.IssuedCoupon
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var issuedCouponEntity entity.IssuedCouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(
+			"id = ? AND deleted_at IS NULL", id,
+		).First(&issuedCouponEntity).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrIssuedCouponNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if issuedCouponEntity.Status != string(domain.IssuedCouponIssued) {
+			return ErrIssuedCouponNotRevocable
+		}
+
+		now := time.Now()
+		err = tx.Model(&issuedCouponEntity).Updates(map[string]interface{}{
+			"status":      string(domain.IssuedCouponRevoked),
+			"modified_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		issuedCouponEntity.Status = string(domain.IssuedCouponRevoked)
+		issuedCouponEntity.ModifiedAt = now
+		issuedCoupon = toIssuedCouponDomain(issuedCouponEntity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issuedCoupon, nil
+}
+
+// ExpireBatch 사용 기간이 지난 미사용 쿠폰을 만료 일시 순으로 최대 limit 개 만료 처리한다
+// 다른 트랜잭션이 처리 중인 쿠폰은 건너뛰며, events 가 만든 이벤트는 같은 트랜잭션에서 발행 대기열에 저장한다
+func (r *IssuedCouponRepository) ExpireBatch(
+	now time.Time,
+	limit int,
+	events func([]domain.IssuedCoupon) []domain.Event,
+) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var issuedCouponEntities []entity.IssuedCouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where(
+			"status = ? AND valid_until <= ? AND deleted_at IS NULL", string(domain.IssuedCouponIssued), now,
+		).Order("valid_until").Limit(limit).Find(&issuedCouponEntities).Error
+		if err != nil || len(issuedCouponEntities) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(issuedCouponEntities))
+		expired := make([]domain.IssuedCoupon, len(issuedCouponEntities))
+		for i, v := range issuedCouponEntities {
+			v.Status = string(domain.IssuedCouponExpired)
+			ids[i] = v.ID
+			expired[i] = *toIssuedCouponDomain(v)
+		}
+		err = tx.Model(&entity.IssuedCouponEntity{}).Where("id IN ?", ids).
+			UpdateColumn("status", string(domain.IssuedCouponExpired)).Error
+		if err != nil {
+			return err
+		}
+		if err = enqueueEvents(tx, events(expired)); err != nil {
+			return err
+		}
+		count = len(expired)
+		return nil
+	})
+	return count, err
+}
+
+// NotifyExpiringBatch 사용 기간이 now 이후 before 이전에 끝나는 미사용 쿠폰 중 만료 예정 알림을 보내지 않은 쿠폰을 최대 limit 개 처리한다
+// 알림 일시와 events 가 만든 이벤트를 같은 트랜잭션에서 저장하므로 둘 중 하나만 저장되지 않는다
+func (r *IssuedCouponRepository) NotifyExpiringBatch(
+	now time.Time,
+	before time.Time,
+	limit int,
+	events func([]domain.IssuedCoupon) []domain.Event,
+) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var issuedCouponEntities []entity.IssuedCouponEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where(
+			"status = ? AND valid_until > ? AND valid_until <= ? AND expiry_notified_at IS NULL AND deleted_at IS NULL",
+			string(domain.IssuedCouponIssued), now, before,
+		).Order("valid_until").Limit(limit).Find(&issuedCouponEntities).Error
+		if err != nil || len(issuedCouponEntities) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(issuedCouponEntities))
+		expiring := make([]domain.IssuedCoupon, len(issuedCouponEntities))
+		for i, v := range issuedCouponEntities {
+			ids[i] = v.ID
+			expiring[i] = *toIssuedCouponDomain(v)
+		}
+		err = tx.Model(&entity.IssuedCouponEntity{}).Where("id IN ?", ids).
+			UpdateColumn("expiry_notified_at", now).Error
+		if err != nil {
+			return err
+		}
+		if err = enqueueEvents(tx, events(expiring)); err != nil {
+			return err
+		}
+		count = len(expiring)
+		return nil
+	})
+	return count, err
+}
+
+// WithAdvisoryLock MySQL 의 GET_LOCK 으로 name 잠금을 획득한 경우에만 fn 을 실행한다
+// 잠금은 연결에 묶이므로 하나의 연결에서 획득과 해제를 하며, 다른 연결이 잠금을 가지고 있으면 false 를 반환한다
+func (r *IssuedCouponRepository) WithAdvisoryLock(ctx context.Context, name string, fn func() error) (bool, error) {
+	var acquired bool
+	err := r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var result sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, 0)", name).Scan(&result).Error; err != nil {
+			return err
+		}
+		if !result.Valid || result.Int64 != 1 {
+			return nil
+		}
+		acquired = true
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", name)
+		return fn()
+	})
+	return acquired, err
 }
 
 func (r *IssuedCouponRepository) FindByCouponId(couponId string) []domain.IssuedCoupon {
@@ -39,14 +495,92 @@ func (r *IssuedCouponRepository) FindByCouponId(couponId string) []domain.Issued
 
 	domains := make([]domain.IssuedCoupon, len(issuedCouponEntities))
 	for i, v := range issuedCouponEntities {
-		domains[i] = domain.IssuedCoupon{
-			ID:         v.ID,
-			CouponID:   v.CouponID,
-			Code:       v.Code,
-			CreatedAt:  v.CreatedAt,
-			ModifiedAt: v.ModifiedAt,
-		}
+		domains[i] = *toIssuedCouponDomain(v)
 	}
 
 	return domains
 }
+
+// FindByUser couponIds 캠페인에서 사용자에게 발급된 쿠폰을 캠페인별 발급 순번 순으로 조회한다
+func (r *IssuedCouponRepository) FindByUser(userId string, couponIds []string) ([]domain.IssuedCoupon, error) {
+	var issuedCouponEntities []entity.IssuedCouponEntity
+	err := r.db.Where(
+		"coupon_id IN ? AND user_id = ? AND deleted_at IS NULL", couponIds, userId,
+	).Order("coupon_id, sequence").Find(&issuedCouponEntities).Error
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make([]domain.IssuedCoupon, len(issuedCouponEntities))
+	for i, v := range issuedCouponEntities {
+		domains[i] = *toIssuedCouponDomain(v)
+	}
+	return domains, nil
+}
+
+func toIssuedCouponDomain(v entity.IssuedCouponEntity) *domain.IssuedCoupon {
+	validFrom := v.CreatedAt
+	if v.ValidFrom != nil {
+		validFrom = *v.ValidFrom
+	}
+	return &domain.IssuedCoupon{
+		ID:         v.ID,
+		CouponID:   v.CouponID,
+		UserID:     v.UserID,
+		Code:       v.Code,
+		Sequence:   v.Sequence,
+		Status:     domain.IssuedCouponStatus(v.Status),
+		RedeemedAt: v.RedeemedAt,
+		ValidFrom:  validFrom,
+		ValidUntil: v.ValidUntil,
+		CreatedAt:  v.CreatedAt,
+		ModifiedAt: v.ModifiedAt,
+	}
+}
+
+func toIssuedCouponEntity(domain *domain.IssuedCoupon) *entity.IssuedCouponEntity {
+	return &entity.IssuedCouponEntity{
+		ID:         domain.ID,
+		CouponID:   domain.CouponID,
+		UserID:     domain.UserID,
+		Code:       domain.Code,
+		Sequence:   domain.Sequence,
+		Status:     string(domain.Status),
+		RedeemedAt: domain.RedeemedAt,
+		ValidFrom:  &domain.ValidFrom,
+		ValidUntil: domain.ValidUntil,
+		CreatedAt:  domain.CreatedAt,
+		ModifiedAt: domain.ModifiedAt,
+		DeletedAt:  nil,
+	}
+}
+
+func toDegradedIssuanceEntity(domain *domain.IssuedCoupon) *entity.DegradedIssuanceEntity {
+	return &entity.DegradedIssuanceEntity{
+		CouponID:       domain.CouponID,
+		IssuedCouponID: domain.ID,
+		UserID:         domain.UserID,
+		Sequence:       domain.Sequence,
+	}
+}
+
+func toDegradedIssuanceDomain(v entity.DegradedIssuanceEntity) domain.IssuedCoupon {
+	return domain.IssuedCoupon{
+		ID:       v.IssuedCouponID,
+		CouponID: v.CouponID,
+		UserID:   v.UserID,
+		Sequence: v.Sequence,
+	}
+}
+
+func isDuplicatedCouponUser(err error) bool {
+	return isDuplicateEntry(err, couponUserIndexName)
+}
+
+// isDuplicateEntry indexName 유니크 인덱스를 위반한 에러인지 여부
+func isDuplicateEntry(err error, indexName string) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) &&
+		mysqlErr.Number == mysqlDuplicateEntry &&
+		strings.Contains(mysqlErr.Message, indexName)
+}
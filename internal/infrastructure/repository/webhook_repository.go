@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"coupon-service/internal/domain"
+	"coupon-service/internal/infrastructure/entity"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+var (
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+	// ErrWebhookDeliveryNotRetryable dead letter 로 옮겨진 전달만 다시 전달할 수 있다
+	ErrWebhookDeliveryNotRetryable = errors.New("webhook delivery is not dead")
+)
+
+// maxWebhookErrorLength webhook_attempts.error 컬럼(varchar(512))의 최대 길이
+const maxWebhookErrorLength = 512
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// WebhookDeliveryFilter 비어있는 조건은 적용하지 않는다
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	CouponID       string
+	Status         domain.WebhookDeliveryStatus
+	Limit          int
+}
+
+func (r *WebhookRepository) SaveSubscription(subscription *domain.WebhookSubscription) error {
+	eventTypes := make([]string, len(subscription.EventTypes))
+	for i, eventType := range subscription.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+	return r.db.Create(&entity.WebhookSubscriptionEntity{
+		ID:         subscription.ID,
+		CouponID:   subscription.CouponID,
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventTypes: strings.Join(eventTypes, ","),
+		CreatedAt:  subscription.CreatedAt,
+		ModifiedAt: subscription.CreatedAt,
+	}).Error
+}
+
+func (r *WebhookRepository) FindSubscription(id string) (*domain.WebhookSubscription, error) {
+	var subscriptionEntity entity.WebhookSubscriptionEntity
+	err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&subscriptionEntity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookSubscriptionDomain(subscriptionEntity), nil
+}
+
+func (r *WebhookRepository) FindSubscriptionsByCouponId(couponId string) ([]domain.WebhookSubscription, error) {
+	var subscriptionEntities []entity.WebhookSubscriptionEntity
+	err := r.db.Where(
+		"coupon_id = ? AND deleted_at IS NULL", couponId,
+	).Order("created_at").Find(&subscriptionEntities).Error
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookSubscriptionDomains(subscriptionEntities), nil
+}
+
+// FindSubscriptionsByIds 삭제된 구독은 결과에 포함하지 않는다
+func (r *WebhookRepository) FindSubscriptionsByIds(ids []string) (map[string]domain.WebhookSubscription, error) {
+	subscriptions := make(map[string]domain.WebhookSubscription, len(ids))
+	if len(ids) == 0 {
+		return subscriptions, nil
+	}
+	var subscriptionEntities []entity.WebhookSubscriptionEntity
+	err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&subscriptionEntities).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, subscription := range toWebhookSubscriptionDomains(subscriptionEntities) {
+		subscriptions[subscription.ID] = subscription
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription 구독을 삭제하고 아직 전달하지 못한 이벤트는 dead letter 로 옮긴다
+func (r *WebhookRepository) DeleteSubscription(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.WebhookSubscriptionEntity{}).Where(
+			"id = ? AND deleted_at IS NULL", id,
+		).UpdateColumn("deleted_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookSubscriptionNotFound
+		}
+		return tx.Model(&entity.WebhookDeliveryEntity{}).Where(
+			"subscription_id = ? AND status = ?", id, string(domain.WebhookDeliveryPending),
+		).UpdateColumn("status", string(domain.WebhookDeliveryDead)).Error
+	})
+}
+
+// EnqueueDeliveries 이미 대기열에 넣은 구독과 이벤트의 전달은 다시 넣지 않는다
+func (r *WebhookRepository) EnqueueDeliveries(deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	deliveryEntities := make([]entity.WebhookDeliveryEntity, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveryEntities[i] = entity.WebhookDeliveryEntity{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			CouponID:       delivery.CouponID,
+			EventID:        delivery.EventID,
+			EventType:      string(delivery.EventType),
+			Payload:        string(delivery.Payload),
+			Status:         string(delivery.Status),
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			CreatedAt:      delivery.CreatedAt,
+			ModifiedAt:     delivery.CreatedAt,
+		}
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(deliveryEntities, 100).Error
+}
+
+// ClaimDueDeliveries 전달 시각이 지난 대기 중인 전달을 최대 limit 개 가져온다
+// 가져온 전달은 다음 전달 시각을 lease 만큼 미뤄 결과를 기록하기 전까지 다른 인스턴스가 함께 전달하지 않도록 한다
+func (r *WebhookRepository) ClaimDueDeliveries(now time.Time, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var deliveryEntities []entity.WebhookDeliveryEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where(
+			"status = ? AND next_attempt_at <= ?", string(domain.WebhookDeliveryPending), now,
+		).Order("next_attempt_at").Limit(limit).Find(&deliveryEntities).Error
+		if err != nil || len(deliveryEntities) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(deliveryEntities))
+		deliveries = make([]domain.WebhookDelivery, len(deliveryEntities))
+		for i, v := range deliveryEntities {
+			ids[i] = v.ID
+			deliveries[i] = *toWebhookDeliveryDomain(v)
+		}
+		return tx.Model(&entity.WebhookDeliveryEntity{}).Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt delivery.Record 로 반영한 마지막 시도 결과와 전달 상태를 저장한다
+func (r *WebhookRepository) RecordAttempt(delivery *domain.WebhookDelivery) error {
+	if len(delivery.History) == 0 {
+		return nil
+	}
+	attempt := delivery.History[len(delivery.History)-1]
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&entity.WebhookAttemptEntity{
+			DeliveryID:  delivery.ID,
+			Attempt:     attempt.Attempt,
+			StatusCode:  attempt.StatusCode,
+			Error:       truncate(attempt.Error, maxWebhookErrorLength),
+			DurationMs:  attempt.Duration.Milliseconds(),
+			AttemptedAt: attempt.AttemptedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entity.WebhookDeliveryEntity{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+			"status":          string(delivery.Status),
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+	})
+}
+
+// FindDeliveries 최근에 만든 전달부터 전달 시도 기록과 함께 반환한다
+func (r *WebhookRepository) FindDeliveries(filter WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	query := r.db.Model(&entity.WebhookDeliveryEntity{})
+	if filter.SubscriptionID != "" {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.CouponID != "" {
+		query = query.Where("coupon_id = ?", filter.CouponID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var deliveryEntities []entity.WebhookDeliveryEntity
+	if err := query.Order("created_at DESC").Order("id").Find(&deliveryEntities).Error; err != nil {
+		return nil, err
+	}
+	if len(deliveryEntities) == 0 {
+		return []domain.WebhookDelivery{}, nil
+	}
+
+	ids := make([]string, len(deliveryEntities))
+	for i, v := range deliveryEntities {
+		ids[i] = v.ID
+	}
+	var attemptEntities []entity.WebhookAttemptEntity
+	if err := r.db.Where("delivery_id IN ?", ids).Order("id").Find(&attemptEntities).Error; err != nil {
+		return nil, err
+	}
+	history := make(map[string][]domain.WebhookAttempt, len(deliveryEntities))
+	for _, v := range attemptEntities {
+		history[v.DeliveryID] = append(history[v.DeliveryID], domain.WebhookAttempt{
+			Attempt:     v.Attempt,
+			StatusCode:  v.StatusCode,
+			Error:       v.Error,
+			Duration:    time.Duration(v.DurationMs) * time.Millisecond,
+			AttemptedAt: v.AttemptedAt,
+		})
+	}
+
+	deliveries := make([]domain.WebhookDelivery, len(deliveryEntities))
+	for i, v := range deliveryEntities {
+		deliveries[i] = *toWebhookDeliveryDomain(v)
+		deliveries[i].History = history[v.ID]
+	}
+	return deliveries, nil
+}
+
+// RequeueDelivery dead letter 로 옮겨진 전달의 시도 횟수를 초기화하고 바로 다시 전달하도록 한다
+func (r *WebhookRepository) RequeueDelivery(id string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var deliveryEntity entity.WebhookDeliveryEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&deliveryEntity).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWebhookDeliveryNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if deliveryEntity.Status != string(domain.WebhookDeliveryDead) {
+			return ErrWebhookDeliveryNotRetryable
+		}
+		return tx.Model(&deliveryEntity).Updates(map[string]interface{}{
+			"status":          string(domain.WebhookDeliveryPending),
+			"attempts":        0,
+			"next_attempt_at": now,
+		}).Error
+	})
+}
+
+func toWebhookSubscriptionDomains(subscriptionEntities []entity.WebhookSubscriptionEntity) []domain.WebhookSubscription {
+	subscriptions := make([]domain.WebhookSubscription, len(subscriptionEntities))
+	for i, v := range subscriptionEntities {
+		subscriptions[i] = *toWebhookSubscriptionDomain(v)
+	}
+	return subscriptions
+}
+
+func toWebhookSubscriptionDomain(v entity.WebhookSubscriptionEntity) *domain.WebhookSubscription {
+	var eventTypes []domain.EventType
+	if v.EventTypes != "" {
+		for _, eventType := range strings.Split(v.EventTypes, ",") {
+			eventTypes = append(eventTypes, domain.EventType(eventType))
+		}
+	}
+	return &domain.WebhookSubscription{
+		ID:         v.ID,
+		CouponID:   v.CouponID,
+		URL:        v.URL,
+		Secret:     v.Secret,
+		EventTypes: eventTypes,
+		CreatedAt:  v.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryDomain(v entity.WebhookDeliveryEntity) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             v.ID,
+		SubscriptionID: v.SubscriptionID,
+		CouponID:       v.CouponID,
+		EventID:        v.EventID,
+		EventType:      domain.EventType(v.EventType),
+		Payload:        []byte(v.Payload),
+		Status:         domain.WebhookDeliveryStatus(v.Status),
+		Attempts:       v.Attempts,
+		NextAttemptAt:  v.NextAttemptAt,
+		DeliveredAt:    v.DeliveredAt,
+		CreatedAt:      v.CreatedAt,
+	}
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return strings.ToValidUTF8(value[:length], "")
+}
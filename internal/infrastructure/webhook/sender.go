@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 수신 서버는 Webhook-Timestamp 와 요청 본문으로 Webhook-Signature 를 검증하고,
+// 재전송 공격을 막기 위해 오래된 Timestamp 의 요청은 거절해야 한다
+const (
+	IDHeader        = "Webhook-Id"
+	EventHeader     = "Webhook-Event"
+	TimestampHeader = "Webhook-Timestamp"
+	SignatureHeader = "Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// DefaultTimeout 수신 서버의 응답을 기다리는 시간
+const DefaultTimeout = 5 * time.Second
+
+// Request 하나의 웹훅 요청. ID 는 재시도해도 바뀌지 않으므로 수신 서버는 중복 수신을 걸러낼 수 있다
+type Request struct {
+	ID        string
+	URL       string
+	Secret    string
+	EventType string
+	Body      []byte
+	Timestamp time.Time
+}
+
+// Sender 서명한 웹훅 요청을 전송한다
+type Sender struct {
+	client *http.Client
+}
+
+type Option func(*Sender)
+
+// WithHTTPClient 요청에 사용할 http.Client 를 지정한다
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Sender) {
+		s.client = client
+	}
+}
+
+func NewSender(timeout time.Duration, opts ...Option) *Sender {
+	sender := &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			// 다른 주소로 보내지 않도록 리다이렉트는 따라가지 않고 실패로 처리한다
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(sender)
+	}
+	return sender
+}
+
+// Send 수신 서버의 응답 상태 코드를 반환하며, 2xx 가 아니면 에러를 함께 반환한다
+func (s *Sender) Send(ctx context.Context, req Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(req.Timestamp.Unix(), 10)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "coupon-service-webhook")
+	httpReq.Header.Set(IDHeader, req.ID)
+	httpReq.Header.Set(EventHeader, req.EventType)
+	httpReq.Header.Set(TimestampHeader, timestamp)
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, timestamp, req.Body))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// 연결을 재사용할 수 있도록 응답 본문을 일부 읽고 버린다
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign "{timestamp}.{body}" 의 HMAC-SHA256 을 "sha256={hex}" 형식으로 반환한다
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 수신 서버에서 서명을 검증할 때 사용한다
+func Verify(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
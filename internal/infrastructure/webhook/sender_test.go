@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender(t *testing.T) {
+	body := []byte(`{"type":"campaign.sold_out"}`)
+	request := func(url string) Request {
+		return Request{
+			ID:        "delivery-1",
+			URL:       url,
+			Secret:    "0123456789abcdef",
+			EventType: "campaign.sold_out",
+			Body:      body,
+			Timestamp: time.Unix(1767225600, 0),
+		}
+	}
+
+	t.Run("본문과 타임스탬프로 서명한 요청을 보내야 한다", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, body, received)
+			assert.Equal(t, "delivery-1", r.Header.Get(IDHeader))
+			assert.Equal(t, "campaign.sold_out", r.Header.Get(EventHeader))
+			assert.Equal(t, "1767225600", r.Header.Get(TimestampHeader))
+			assert.True(t, Verify("0123456789abcdef", r.Header.Get(TimestampHeader), received, r.Header.Get(SignatureHeader)))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		statusCode, err := NewSender(DefaultTimeout).Send(context.Background(), request(server.URL))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+	})
+
+	t.Run("2xx 가 아닌 응답은 상태 코드와 함께 실패해야 한다", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		statusCode, err := NewSender(DefaultTimeout).Send(context.Background(), request(server.URL))
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+	})
+
+	t.Run("리다이렉트는 따라가지 않아야 한다", func(t *testing.T) {
+		server := httptest.NewServer(http.RedirectHandler("http://example.com", http.StatusFound))
+		defer server.Close()
+
+		statusCode, err := NewSender(DefaultTimeout).Send(context.Background(), request(server.URL))
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusFound, statusCode)
+	})
+
+	t.Run("응답 시간이 초과되면 실패해야 한다", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer server.Close()
+
+		statusCode, err := NewSender(50*time.Millisecond).Send(context.Background(), request(server.URL))
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, statusCode)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	signature := Sign("0123456789abcdef", "1767225600", []byte("{}"))
+
+	assert.True(t, Verify("0123456789abcdef", "1767225600", []byte("{}"), signature))
+	assert.False(t, Verify("0123456789abcdef", "1767225601", []byte("{}"), signature))
+	assert.False(t, Verify("other-secret-value", "1767225600", []byte("{}"), signature))
+}
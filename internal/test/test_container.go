@@ -9,6 +9,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/redpanda"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
@@ -184,3 +185,36 @@ func (mc *MySQLContainer) Cleanup(ctx context.Context) error {
 func (mc *MySQLContainer) MigrateEntities(entities ...interface{}) error {
 	return mc.DB.AutoMigrate(entities...)
 }
+
+// KafkaContainer Kafka 호환 브로커(Redpanda) 테스트 컨테이너. Brokers 는 Kafka 프로토콜 주소이다
+type KafkaContainer struct {
+	Container *redpanda.Container
+	Brokers   []string
+}
+
+func NewKafkaContainer(ctx context.Context) (*KafkaContainer, error) {
+	container, err := redpanda.Run(ctx, "docker.redpanda.com/redpandadata/redpanda:v24.2.4")
+	if err != nil {
+		return nil, fmt.Errorf("kafka 컨테이너 시작 실패: %w", err)
+	}
+
+	broker, err := container.KafkaSeedBroker(ctx)
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, fmt.Errorf("kafka 브로커 주소 가져오기 실패: %w", err)
+	}
+
+	return &KafkaContainer{
+		Container: container,
+		Brokers:   []string{broker},
+	}, nil
+}
+
+func (kc *KafkaContainer) Cleanup(ctx context.Context) error {
+	if kc.Container != nil {
+		if err := kc.Container.Terminate(ctx); err != nil {
+			return fmt.Errorf("kafka 컨테이너 종료 실패: %w", err)
+		}
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 	"time"
 )
 
-func SetupRedisForTest(t *testing.T) (*RedisContainer, context.Context) {
+func SetupRedisForTest(t testing.TB) (*RedisContainer, context.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	t.Cleanup(func() { cancel() })
 
@@ -53,3 +53,23 @@ func SetupMySQLForTest(t testing.TB) (*MySQLContainer, context.Context) {
 
 	return mysqlContainer, ctx
 }
+
+func SetupKafkaForTest(t testing.TB) (*KafkaContainer, context.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	t.Cleanup(func() { cancel() })
+
+	kafkaContainer, err := NewKafkaContainer(ctx)
+	if err != nil {
+		t.Fatalf("Kafka 컨테이너 설정 실패: %v", err)
+	}
+
+	t.Cleanup(func() {
+		cleanCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := kafkaContainer.Cleanup(cleanCtx); err != nil {
+			t.Logf("Kafka 컨테이너 정리 실패: %v", err)
+		}
+	})
+
+	return kafkaContainer, ctx
+}
@@ -35,7 +35,11 @@ export function checkResponse(response, expectedStatus = 200) {
 export const connectHeaders = {
   'Content-Type': 'application/json',
   'Connect-Protocol-Version': '1',
-  'Accept': 'application/json'
+  'Accept': 'application/json',
+  // CONNECT_ERRORS=true 이면 실패한 요청이 HTTP 에러 상태로 응답되어 http_req_failed 에 집계된다
+  ...(__ENV.CONNECT_ERRORS === 'true' ? { 'Coupon-Error-Mode': 'connect' } : {}),
+  // 서버에 인증이 설정되어 있으면 AUTH_TOKEN 으로 관리자 토큰을 전달한다
+  ...(__ENV.AUTH_TOKEN ? { 'Authorization': `Bearer ${__ENV.AUTH_TOKEN}` } : {})
 };
 
 export const endpoints = {